@@ -17,7 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -31,79 +35,28 @@ func main() {
 	flags := pflag.NewFlagSet("", pflag.ExitOnError)
 	pflag.CommandLine = flags
 
-	root := &cobra.Command{
-		Use: "sdc",
-		Annotations: map[string]string{
-			cobra.CommandDisplayNameAnnotation: "kubectl sdc",
-		},
-	}
-
-	blameCmd, err := sdcCmd.NewCmdBlame(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	root, err := sdcCmd.NewCmdRoot(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
 	if err != nil {
 		panic(err)
 	}
-	root.AddCommand(blameCmd)
-	deviationCmd, err := sdcCmd.NewCmdDeviation(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
-	if err != nil {
-		panic(err)
-	}
-	root.AddCommand(deviationCmd)
-	applyCmd, err := sdcCmd.NewCmdApply(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
-	if err != nil {
-		panic(err)
-	}
-	root.AddCommand(applyCmd)
-	runningConfigCmd, err := sdcCmd.NewCmdRunningConfig(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
-	if err != nil {
-		panic(err)
-	}
-	root.AddCommand(runningConfigCmd)
-
-	root.AddCommand(completionCmd)
-	root.Version = "v0.0.0"
-	root.CompletionOptions.DisableDefaultCmd = false
 
 	cobra.EnableCommandSorting = false
 
-	if err := root.Execute(); err != nil {
-		os.Exit(1)
-	}
+	// SIGINT and SIGTERM cancel the command context, so the commands return and
+	// close their data-server connection. A second signal terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
-}
-
-var err error
-var completionCmd = &cobra.Command{
-	Use:   "completion [bash|zsh|fish|powershell]",
-	Short: "Generate completion script",
-	Long: `To load completions:
-
-Bash:
-
-$ source <(kubectl sdc completion bash)
-
-Zsh:
-
-$ source <(kubectl sdc completion zsh)
-
-Fish:
-
-$ kubectl sdc completion fish | source
-`,
-	DisableFlagsInUseLine: true,
-	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
-
-	Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		switch args[0] {
-		case "bash":
-			err = cmd.Root().GenBashCompletion(os.Stdout)
-		case "zsh":
-			err = cmd.Root().GenZshCompletion(os.Stdout)
-		case "fish":
-			err = cmd.Root().GenFishCompletion(os.Stdout, true)
-		case "powershell":
-			err = cmd.Root().GenPowerShellCompletionWithDesc(os.Stdout)
+	err = root.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		if hint := sdcCmd.ErrorHint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
 		}
-		return err
-	},
+		os.Exit(sdcCmd.ExitCode(err))
+	}
+
 }
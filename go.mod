@@ -10,11 +10,13 @@ require (
 	github.com/beevik/etree v1.6.0
 	github.com/fatih/color v1.18.0
 	github.com/ktr0731/go-fuzzyfinder v0.9.0
+	github.com/openconfig/gnmi v0.14.1
 	github.com/sdcio/config-server v0.0.56-0.20260306131400-036f632d2a7b
 	github.com/sdcio/sdc-protos v0.0.51-0.20260312105324-fdf21a9d8280
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/pflag v1.0.10
 	go.uber.org/mock v0.6.0
+	golang.org/x/term v0.40.0
 	google.golang.org/grpc v1.79.2
 	google.golang.org/protobuf v1.36.11
 	gopkg.in/yaml.v3 v3.0.1
@@ -131,7 +133,6 @@ require (
 	golang.org/x/oauth2 v0.34.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.41.0 // indirect
-	golang.org/x/term v0.40.0 // indirect
 	golang.org/x/text v0.34.0 // indirect
 	golang.org/x/time v0.14.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20251213004720-97cd9d5aeac2 // indirect
@@ -14,7 +14,10 @@ import (
 	reflect "reflect"
 
 	v1alpha1 "github.com/sdcio/config-server/apis/config/v1alpha1"
+	client "github.com/sdcio/kubectl-sdc/pkg/client"
 	gomock "go.uber.org/mock/gomock"
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	watch "k8s.io/apimachinery/pkg/watch"
 )
 
 // MockApplyClient is a mock of ApplyClient interface.
@@ -54,3 +57,33 @@ func (mr *MockApplyClientMockRecorder) ClearTargetDeviations(ctx, resource any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearTargetDeviations", reflect.TypeOf((*MockApplyClient)(nil).ClearTargetDeviations), ctx, resource)
 }
+
+// ServerSideApply mocks base method.
+func (m *MockApplyClient) ServerSideApply(ctx context.Context, obj *unstructured.Unstructured, dryRun bool) (client.ApplyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerSideApply", ctx, obj, dryRun)
+	ret0, _ := ret[0].(client.ApplyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerSideApply indicates an expected call of ServerSideApply.
+func (mr *MockApplyClientMockRecorder) ServerSideApply(ctx, obj, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerSideApply", reflect.TypeOf((*MockApplyClient)(nil).ServerSideApply), ctx, obj, dryRun)
+}
+
+// WatchStatus mocks base method.
+func (m *MockApplyClient) WatchStatus(ctx context.Context, kind, namespace, name string) (watch.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchStatus", ctx, kind, namespace, name)
+	ret0, _ := ret[0].(watch.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchStatus indicates an expected call of WatchStatus.
+func (mr *MockApplyClientMockRecorder) WatchStatus(ctx, kind, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchStatus", reflect.TypeOf((*MockApplyClient)(nil).WatchStatus), ctx, kind, namespace, name)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/commands/completion/completion.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mockcompletion -source=pkg/commands/completion/completion.go -destination=./mocks/completion/completion.go
+//
+
+// Package mockcompletion is a generated GoMock package.
+package mockcompletion
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1alpha1 "github.com/sdcio/config-server/apis/config/v1alpha1"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockConfigClient is a mock of ConfigClient interface.
+type MockConfigClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigClientMockRecorder
+	isgomock struct{}
+}
+
+// MockConfigClientMockRecorder is the mock recorder for MockConfigClient.
+type MockConfigClientMockRecorder struct {
+	mock *MockConfigClient
+}
+
+// NewMockConfigClient creates a new mock instance.
+func NewMockConfigClient(ctrl *gomock.Controller) *MockConfigClient {
+	mock := &MockConfigClient{ctrl: ctrl}
+	mock.recorder = &MockConfigClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfigClient) EXPECT() *MockConfigClientMockRecorder {
+	return m.recorder
+}
+
+// GetBlameTree mocks base method.
+func (m *MockConfigClient) GetBlameTree(ctx context.Context, namespace, device string) (*sdcpb.BlameTreeElement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlameTree", ctx, namespace, device)
+	ret0, _ := ret[0].(*sdcpb.BlameTreeElement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlameTree indicates an expected call of GetBlameTree.
+func (mr *MockConfigClientMockRecorder) GetBlameTree(ctx, namespace, device any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlameTree", reflect.TypeOf((*MockConfigClient)(nil).GetBlameTree), ctx, namespace, device)
+}
+
+// GetTargetSchema mocks base method.
+func (m *MockConfigClient) GetTargetSchema(ctx context.Context, namespace, targetName string) (*sdcpb.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetSchema", ctx, namespace, targetName)
+	ret0, _ := ret[0].(*sdcpb.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetSchema indicates an expected call of GetTargetSchema.
+func (mr *MockConfigClientMockRecorder) GetTargetSchema(ctx, namespace, targetName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetSchema", reflect.TypeOf((*MockConfigClient)(nil).GetTargetSchema), ctx, namespace, targetName)
+}
+
+// ListConfigs mocks base method.
+func (m *MockConfigClient) ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigs", ctx, namespace, labels)
+	ret0, _ := ret[0].([]v1alpha1.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigs indicates an expected call of ListConfigs.
+func (mr *MockConfigClientMockRecorder) ListConfigs(ctx, namespace, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigs", reflect.TypeOf((*MockConfigClient)(nil).ListConfigs), ctx, namespace, labels)
+}
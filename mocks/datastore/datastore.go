@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/commands/datastore/datastore.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mockdatastore -source=pkg/commands/datastore/datastore.go -destination=./mocks/datastore/datastore.go
+//
+
+// Package mockdatastore is a generated GoMock package.
+package mockdatastore
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDatastoreClient is a mock of DatastoreClient interface.
+type MockDatastoreClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockDatastoreClientMockRecorder
+	isgomock struct{}
+}
+
+// MockDatastoreClientMockRecorder is the mock recorder for MockDatastoreClient.
+type MockDatastoreClientMockRecorder struct {
+	mock *MockDatastoreClient
+}
+
+// NewMockDatastoreClient creates a new mock instance.
+func NewMockDatastoreClient(ctrl *gomock.Controller) *MockDatastoreClient {
+	mock := &MockDatastoreClient{ctrl: ctrl}
+	mock.recorder = &MockDatastoreClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDatastoreClient) EXPECT() *MockDatastoreClientMockRecorder {
+	return m.recorder
+}
+
+// GetDataStore mocks base method.
+func (m *MockDatastoreClient) GetDataStore(ctx context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDataStore", ctx, datastoreName)
+	ret0, _ := ret[0].(*sdcpb.GetDataStoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDataStore indicates an expected call of GetDataStore.
+func (mr *MockDatastoreClientMockRecorder) GetDataStore(ctx, datastoreName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDataStore", reflect.TypeOf((*MockDatastoreClient)(nil).GetDataStore), ctx, datastoreName)
+}
+
+// ListDataStores mocks base method.
+func (m *MockDatastoreClient) ListDataStores(ctx context.Context) ([]*sdcpb.GetDataStoreResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDataStores", ctx)
+	ret0, _ := ret[0].([]*sdcpb.GetDataStoreResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataStores indicates an expected call of ListDataStores.
+func (mr *MockDatastoreClientMockRecorder) ListDataStores(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataStores", reflect.TypeOf((*MockDatastoreClient)(nil).ListDataStores), ctx)
+}
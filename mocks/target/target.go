@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/commands/target/target.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mocktarget -source=pkg/commands/target/target.go -destination=./mocks/target/target.go
+//
+
+// Package mocktarget is a generated GoMock package.
+package mocktarget
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1alpha1 "github.com/sdcio/config-server/apis/config/v1alpha1"
+	types "github.com/sdcio/kubectl-sdc/pkg/types"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTargetClient is a mock of TargetClient interface.
+type MockTargetClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTargetClientMockRecorder
+	isgomock struct{}
+}
+
+// MockTargetClientMockRecorder is the mock recorder for MockTargetClient.
+type MockTargetClientMockRecorder struct {
+	mock *MockTargetClient
+}
+
+// NewMockTargetClient creates a new mock instance.
+func NewMockTargetClient(ctrl *gomock.Controller) *MockTargetClient {
+	mock := &MockTargetClient{ctrl: ctrl}
+	mock.recorder = &MockTargetClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTargetClient) EXPECT() *MockTargetClientMockRecorder {
+	return m.recorder
+}
+
+// GetDeviationsByTarget mocks base method.
+func (m *MockTargetClient) GetDeviationsByTarget(ctx context.Context, namespace, targetName string) (types.Deviations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeviationsByTarget", ctx, namespace, targetName)
+	ret0, _ := ret[0].(types.Deviations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeviationsByTarget indicates an expected call of GetDeviationsByTarget.
+func (mr *MockTargetClientMockRecorder) GetDeviationsByTarget(ctx, namespace, targetName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviationsByTarget", reflect.TypeOf((*MockTargetClient)(nil).GetDeviationsByTarget), ctx, namespace, targetName)
+}
+
+// GetTarget mocks base method.
+func (m *MockTargetClient) GetTarget(ctx context.Context, namespace, targetName string) (*v1alpha1.Target, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTarget", ctx, namespace, targetName)
+	ret0, _ := ret[0].(*v1alpha1.Target)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTarget indicates an expected call of GetTarget.
+func (mr *MockTargetClientMockRecorder) GetTarget(ctx, namespace, targetName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTarget", reflect.TypeOf((*MockTargetClient)(nil).GetTarget), ctx, namespace, targetName)
+}
+
+// ListConfigs mocks base method.
+func (m *MockTargetClient) ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConfigs", ctx, namespace, labels)
+	ret0, _ := ret[0].([]v1alpha1.Config)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConfigs indicates an expected call of ListConfigs.
+func (mr *MockTargetClientMockRecorder) ListConfigs(ctx, namespace, labels any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConfigs", reflect.TypeOf((*MockTargetClient)(nil).ListConfigs), ctx, namespace, labels)
+}
+
+// ListDeviations mocks base method.
+func (m *MockTargetClient) ListDeviations(ctx context.Context, namespace string) ([]v1alpha1.Deviation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeviations", ctx, namespace)
+	ret0, _ := ret[0].([]v1alpha1.Deviation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeviations indicates an expected call of ListDeviations.
+func (mr *MockTargetClientMockRecorder) ListDeviations(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeviations", reflect.TypeOf((*MockTargetClient)(nil).ListDeviations), ctx, namespace)
+}
+
+// ListTargets mocks base method.
+func (m *MockTargetClient) ListTargets(ctx context.Context, namespace string) ([]v1alpha1.Target, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTargets", ctx, namespace)
+	ret0, _ := ret[0].([]v1alpha1.Target)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTargets indicates an expected call of ListTargets.
+func (mr *MockTargetClientMockRecorder) ListTargets(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTargets", reflect.TypeOf((*MockTargetClient)(nil).ListTargets), ctx, namespace)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/commands/validate/validate.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mockvalidate -source=pkg/commands/validate/validate.go -destination=./mocks/validate/validate.go
+//
+
+// Package mockvalidate is a generated GoMock package.
+package mockvalidate
+
+import (
+	context "context"
+	reflect "reflect"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTargetClient is a mock of TargetClient interface.
+type MockTargetClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockTargetClientMockRecorder
+	isgomock struct{}
+}
+
+// MockTargetClientMockRecorder is the mock recorder for MockTargetClient.
+type MockTargetClientMockRecorder struct {
+	mock *MockTargetClient
+}
+
+// NewMockTargetClient creates a new mock instance.
+func NewMockTargetClient(ctrl *gomock.Controller) *MockTargetClient {
+	mock := &MockTargetClient{ctrl: ctrl}
+	mock.recorder = &MockTargetClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTargetClient) EXPECT() *MockTargetClientMockRecorder {
+	return m.recorder
+}
+
+// GetTargetSchema mocks base method.
+func (m *MockTargetClient) GetTargetSchema(ctx context.Context, namespace, targetName string) (*sdcpb.Schema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetSchema", ctx, namespace, targetName)
+	ret0, _ := ret[0].(*sdcpb.Schema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetSchema indicates an expected call of GetTargetSchema.
+func (mr *MockTargetClientMockRecorder) GetTargetSchema(ctx, namespace, targetName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetSchema", reflect.TypeOf((*MockTargetClient)(nil).GetTargetSchema), ctx, namespace, targetName)
+}
+
+// MockSchemaClient is a mock of SchemaClient interface.
+type MockSchemaClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchemaClientMockRecorder
+	isgomock struct{}
+}
+
+// MockSchemaClientMockRecorder is the mock recorder for MockSchemaClient.
+type MockSchemaClientMockRecorder struct {
+	mock *MockSchemaClient
+}
+
+// NewMockSchemaClient creates a new mock instance.
+func NewMockSchemaClient(ctrl *gomock.Controller) *MockSchemaClient {
+	mock := &MockSchemaClient{ctrl: ctrl}
+	mock.recorder = &MockSchemaClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSchemaClient) EXPECT() *MockSchemaClientMockRecorder {
+	return m.recorder
+}
+
+// GetSchemaElem mocks base method.
+func (m *MockSchemaClient) GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaElem", ctx, schema, path)
+	ret0, _ := ret[0].(*sdcpb.SchemaElem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaElem indicates an expected call of GetSchemaElem.
+func (mr *MockSchemaClientMockRecorder) GetSchemaElem(ctx, schema, path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaElem", reflect.TypeOf((*MockSchemaClient)(nil).GetSchemaElem), ctx, schema, path)
+}
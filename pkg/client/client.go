@@ -2,14 +2,23 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/sdcio/config-server/apis/config/v1alpha1"
 	configCR "github.com/sdcio/config-server/pkg/generated/clientset/versioned"
 	"github.com/sdcio/kubectl-sdc/pkg/types"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"google.golang.org/protobuf/encoding/protojson"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 )
@@ -17,13 +26,40 @@ import (
 const (
 	// TargetLabel is the label used to identify targets in Kubernetes
 	TargetLabel = "config.sdcio.dev/targetName"
+	// FieldManager is the field manager used for server-side apply
+	FieldManager = "kubectl-sdc"
 )
 
+// ApplyResult describes the outcome of a server-side apply, in kubectl terms
+type ApplyResult string
+
+const (
+	ApplyResultCreated    ApplyResult = "created"
+	ApplyResultConfigured ApplyResult = "configured"
+	ApplyResultUnchanged  ApplyResult = "unchanged"
+)
+
+// applyResources maps the config.sdcio.dev kinds that can be applied to their resource names
+var applyResources = map[string]string{
+	v1alpha1.ConfigKind:          "configs",
+	v1alpha1.ConfigSetKind:       "configsets",
+	v1alpha1.SensitiveConfigKind: "sensitiveconfigs",
+	v1alpha1.TargetKind:          "targets",
+}
+
+// ApplyResourceName returns the resource name for an applicable config.sdcio.dev kind
+func ApplyResourceName(kind string) (string, bool) {
+	resource, ok := applyResources[kind]
+	return resource, ok
+}
+
 type ConfigClient struct {
 	// c is the clientset for interacting with the config server CRDs
 	c *configCR.Clientset
 	// mdClient is used for fetching metadata like names of resources without fetching the entire object
 	mdClient metadata.Interface
+	// k is the clientset of the core resources, e.g. the ConfigMaps of the history store
+	k kubernetes.Interface
 }
 
 func NewConfigClient(restConfig *rest.Config) (*ConfigClient, error) {
@@ -39,15 +75,21 @@ func NewConfigClient(restConfig *rest.Config) (*ConfigClient, error) {
 		return nil, err
 	}
 
+	k, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ConfigClient{
 		c:        clientset,
 		mdClient: mdclient,
+		k:        k,
 	}, nil
 }
 
 // GetDeviationByName retrieves a specific deviation by name and converts it to the internal type
 func (c *ConfigClient) GetDeviationByName(ctx context.Context, namespace string, deviationName string) (*types.IntentDeviations, error) {
-	resp, err := c.c.ConfigV1alpha1().Deviations(namespace).Get(ctx, deviationName, metav1.GetOptions{})
+	resp, err := c.getDeviation(ctx, namespace, deviationName)
 	if err != nil {
 		return nil, err
 	}
@@ -57,9 +99,7 @@ func (c *ConfigClient) GetDeviationByName(ctx context.Context, namespace string,
 
 // GetDeviationsByTarget retrieves all deviations for a given target and converts them to the internal type
 func (c *ConfigClient) GetDeviationsByTarget(ctx context.Context, namespace string, targetName string) (types.Deviations, error) {
-	labelselector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: map[string]string{TargetLabel: targetName}})
-
-	resp, err := c.c.ConfigV1alpha1().Deviations(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelselector})
+	resp, err := c.listDeviations(ctx, namespace, targetName)
 	if err != nil {
 		return nil, err
 	}
@@ -67,7 +107,18 @@ func (c *ConfigClient) GetDeviationsByTarget(ctx context.Context, namespace stri
 	return ConvertDeviations(resp)
 }
 
-func (c *ConfigClient) ListDeviationNames(ctx context.Context, namespace string, labels map[string]string) ([]string, error) {
+func (c *ConfigClient) getDeviation(ctx context.Context, namespace string, deviationName string) (*v1alpha1.Deviation, error) {
+	return c.c.ConfigV1alpha1().Deviations(namespace).Get(ctx, deviationName, metav1.GetOptions{})
+}
+
+func (c *ConfigClient) listDeviations(ctx context.Context, namespace string, targetName string) (*v1alpha1.DeviationList, error) {
+	labelselector := metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: map[string]string{TargetLabel: targetName}})
+	return c.c.ConfigV1alpha1().Deviations(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelselector})
+}
+
+// ListDeviationNames lists the names of the deviations of the targets, all
+// deviations of the namespace without targets
+func (c *ConfigClient) ListDeviationNames(ctx context.Context, namespace string, targets []string) ([]string, error) {
 	// Define the GVR for your CRD
 	gvr := schema.GroupVersionResource{
 		Group:    "config.sdcio.dev", // Replace with your actual group
@@ -76,8 +127,12 @@ func (c *ConfigClient) ListDeviationNames(ctx context.Context, namespace string,
 	}
 
 	listOptions := metav1.ListOptions{}
-	if len(labels) > 0 {
-		listOptions.LabelSelector = metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	if len(targets) > 0 {
+		listOptions.LabelSelector = metav1.FormatLabelSelector(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: TargetLabel, Operator: metav1.LabelSelectorOpIn, Values: targets},
+			},
+		})
 	}
 
 	// This call sends the "Accept: application/json;as=PartialObjectMetadataList" header automatically
@@ -109,13 +164,19 @@ func (c *ConfigClient) GetBlameTree(ctx context.Context, namespace string, devic
 }
 
 func (c *ConfigClient) ListTargetNames(ctx context.Context, namespace string) ([]string, error) {
+	return c.SelectTargetNames(ctx, namespace, "")
+}
+
+// SelectTargetNames lists the names of the targets of a namespace matching a
+// label selector, all targets if the selector is empty
+func (c *ConfigClient) SelectTargetNames(ctx context.Context, namespace string, selector string) ([]string, error) {
 	gvr := schema.GroupVersionResource{
 		Group:    "config.sdcio.dev",
 		Version:  "v1alpha1",
 		Resource: "targets",
 	}
 
-	resp, err := c.mdClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	resp, err := c.mdClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +190,56 @@ func (c *ConfigClient) ListTargetNames(ctx context.Context, namespace string) ([
 	return result, nil
 }
 
+// ListTargets lists the targets of a namespace
+func (c *ConfigClient) ListTargets(ctx context.Context, namespace string) ([]v1alpha1.Target, error) {
+	resp, err := c.c.ConfigV1alpha1().Targets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetTarget retrieves a target by name
+func (c *ConfigClient) GetTarget(ctx context.Context, namespace string, targetName string) (*v1alpha1.Target, error) {
+	return c.c.ConfigV1alpha1().Targets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+}
+
+// ListConfigs lists the configs of a namespace, optionally filtered by labels
+func (c *ConfigClient) ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error) {
+	listOptions := metav1.ListOptions{}
+	if len(labels) > 0 {
+		listOptions.LabelSelector = metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	}
+
+	resp, err := c.c.ConfigV1alpha1().Configs(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// ListDeviations retrieves all deviations of a namespace
+func (c *ConfigClient) ListDeviations(ctx context.Context, namespace string) ([]v1alpha1.Deviation, error) {
+	resp, err := c.c.ConfigV1alpha1().Deviations(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// GetTargetSchema returns the schema (vendor and version) discovered for a target
+func (c *ConfigClient) GetTargetSchema(ctx context.Context, namespace string, targetName string) (*sdcpb.Schema, error) {
+	target, err := c.c.ConfigV1alpha1().Targets(namespace).Get(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info := target.Status.DiscoveryInfo
+	if info == nil || info.Provider == "" || info.Version == "" {
+		return nil, fmt.Errorf("target %s/%s has not been discovered yet, its schema is unknown", namespace, targetName)
+	}
+	return &sdcpb.Schema{Vendor: info.Provider, Version: info.Version}, nil
+}
+
 // ListRunningConfigNames lists all running config names in a namespace
 func (c *ConfigClient) ListRunningConfigNames(ctx context.Context, namespace string) ([]string, error) {
 	gvr := schema.GroupVersionResource{
@@ -193,3 +304,113 @@ func (c *ConfigClient) ClearTargetDeviations(ctx context.Context, resource *v1al
 
 	return result.Error()
 }
+
+// ServerSideApply applies a config.sdcio.dev resource with server-side apply using
+// the kubectl-sdc field manager. The resource is fetched beforehand so the result
+// can tell a create from an update or a no-op. With dryRun the request is only
+// validated and admitted by the server, nothing is persisted.
+func (c *ConfigClient) ServerSideApply(ctx context.Context, obj *unstructured.Unstructured, dryRun bool) (ApplyResult, error) {
+	resource, ok := ApplyResourceName(obj.GetKind())
+	if !ok {
+		return "", fmt.Errorf("unsupported kind %q", obj.GetKind())
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+
+	restClient := c.c.ConfigV1alpha1().RESTClient()
+
+	var before []byte
+	found := true
+	existing, err := restClient.
+		Get().
+		Namespace(obj.GetNamespace()).
+		Resource(resource).
+		Name(obj.GetName()).
+		DoRaw(ctx)
+	switch {
+	case apierrors.IsNotFound(err):
+		found = false
+	case err != nil:
+		return "", err
+	default:
+		before = existing
+	}
+
+	req := restClient.
+		Patch(k8stypes.ApplyPatchType).
+		Namespace(obj.GetNamespace()).
+		Resource(resource).
+		Name(obj.GetName()).
+		Param("fieldManager", FieldManager)
+	if dryRun {
+		req = req.Param("dryRun", metav1.DryRunAll)
+	}
+	applied, err := req.Body(data).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return ApplyResultCreated, nil
+	}
+
+	// a dry run never bumps the resource version, the objects are compared instead
+	var unchanged bool
+	if dryRun {
+		unchanged, err = sameResource(before, applied)
+	} else {
+		unchanged, err = sameResourceVersion(before, applied)
+	}
+	if err != nil {
+		return "", err
+	}
+	if unchanged {
+		return ApplyResultUnchanged, nil
+	}
+	return ApplyResultConfigured, nil
+}
+
+// sameResourceVersion reports whether two raw resources have the same metadata.resourceVersion
+func sameResourceVersion(before, after []byte) (bool, error) {
+	beforeObj, afterObj := &unstructured.Unstructured{}, &unstructured.Unstructured{}
+	if err := beforeObj.UnmarshalJSON(before); err != nil {
+		return false, err
+	}
+	if err := afterObj.UnmarshalJSON(after); err != nil {
+		return false, err
+	}
+	return beforeObj.GetResourceVersion() == afterObj.GetResourceVersion(), nil
+}
+
+// sameResource reports whether two raw resources are equal, apart from their
+// resource version, managed fields and status
+func sameResource(before, after []byte) (bool, error) {
+	objs := make([]*unstructured.Unstructured, 0, 2)
+	for _, raw := range [][]byte{before, after} {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw); err != nil {
+			return false, err
+		}
+		obj.SetResourceVersion("")
+		obj.SetManagedFields(nil)
+		unstructured.RemoveNestedField(obj.Object, "status")
+		objs = append(objs, obj)
+	}
+	return equality.Semantic.DeepEqual(objs[0].Object, objs[1].Object), nil
+}
+
+// WatchStatus watches a single Config or ConfigSet. The events carry *v1alpha1.Config
+// or *v1alpha1.ConfigSet objects, starting with the current state of the resource.
+func (c *ConfigClient) WatchStatus(ctx context.Context, kind, namespace, name string) (watch.Interface, error) {
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+	switch kind {
+	case v1alpha1.ConfigKind:
+		return c.c.ConfigV1alpha1().Configs(namespace).Watch(ctx, opts)
+	case v1alpha1.ConfigSetKind:
+		return c.c.ConfigV1alpha1().ConfigSets(namespace).Watch(ctx, opts)
+	default:
+		return nil, fmt.Errorf("watching kind %q is not supported", kind)
+	}
+}
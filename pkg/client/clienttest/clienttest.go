@@ -0,0 +1,47 @@
+// Package clienttest provides stubs of the data-server client for the tests of
+// the commands.
+package clienttest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Intent is a client.Intent in the proto format
+type Intent struct {
+	Proto *sdcpb.Intent
+}
+
+func (i *Intent) String() string          { return "" }
+func (i *Intent) GetBlob() []byte         { return nil }
+func (i *Intent) GetProto() *sdcpb.Intent { return i.Proto }
+func (i *Intent) GetType() client.Format  { return client.FormatXPath }
+
+// IntentClient serves the intents by datastore and intent name. Unknown intents
+// are NotFound, like on the data-server.
+type IntentClient map[string]map[string]*sdcpb.Intent
+
+// GetIntent returns the intent of the datastore
+func (c IntentClient) GetIntent(_ context.Context, _ client.Format, datastoreName, intentName string) (client.Intent, error) {
+	intent, ok := c[datastoreName][intentName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown intent %s of datastore %s", intentName, datastoreName)
+	}
+	return &Intent{Proto: intent}, nil
+}
+
+// NewUpdate returns the update of the leaf at xpath to a string value
+func NewUpdate(t testing.TB, xpath, value string) *sdcpb.Update {
+	t.Helper()
+	p, err := sdcpb.ParsePath(xpath)
+	if err != nil {
+		t.Fatalf("parse path %q: %v", xpath, err)
+	}
+	p.IsRootBased = true
+	return &sdcpb.Update{Path: p, Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: value}}}
+}
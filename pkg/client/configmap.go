@@ -0,0 +1,29 @@
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListConfigMaps lists the ConfigMaps of a namespace, optionally filtered by labels
+func (c *ConfigClient) ListConfigMaps(ctx context.Context, namespace string, labels map[string]string) ([]corev1.ConfigMap, error) {
+	listOptions := metav1.ListOptions{}
+	if len(labels) > 0 {
+		listOptions.LabelSelector = metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: labels})
+	}
+
+	resp, err := c.k.CoreV1().ConfigMaps(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// CreateConfigMap creates a ConfigMap with the kubectl-sdc field manager, it fails
+// with an AlreadyExists error when the ConfigMap exists
+func (c *ConfigClient) CreateConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	_, err := c.k.CoreV1().ConfigMaps(cm.Namespace).Create(ctx, cm, metav1.CreateOptions{FieldManager: FieldManager})
+	return err
+}
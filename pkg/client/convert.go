@@ -50,23 +50,24 @@ func ConvertDeviationIntent(d *v1alpha1.Deviation) (*types.IntentDeviations, err
 }
 
 func ConvertDeviation(d *v1alpha1.ConfigDeviation) (*types.Deviation, error) {
+	var desired, current *sdcpb.TypedValue
 	var err error
-	desired := ""
-	current := ""
 
 	if d.DesiredValue != nil {
-		desired, err = convertTypedValueTextToString(*d.DesiredValue)
+		desired, err = parseTypedValueText(*d.DesiredValue)
 		if err != nil {
 			return nil, err
 		}
 	}
 	if d.ActualValue != nil {
-		current, err = convertTypedValueTextToString(*d.ActualValue)
+		current, err = parseTypedValueText(*d.ActualValue)
 		if err != nil {
 			return nil, err
 		}
 	}
-	return types.NewDeviation(d.Path, desired, current, d.Reason), nil
+	dev := types.NewDeviation(d.Path, typedValueString(desired), typedValueString(current), d.Reason)
+	dev.Desired = desired
+	return dev, nil
 }
 
 func ConvertDeviationType(dt v1alpha1.DeviationType) (types.DeviationType, error) {
@@ -80,11 +81,18 @@ func ConvertDeviationType(dt v1alpha1.DeviationType) (types.DeviationType, error
 	}
 }
 
-func convertTypedValueTextToString(tvText string) (string, error) {
+func parseTypedValueText(tvText string) (*sdcpb.TypedValue, error) {
 	tv := &sdcpb.TypedValue{}
-	err := prototext.Unmarshal([]byte(tvText), tv)
-	if err != nil {
-		return "", err
+	if err := prototext.Unmarshal([]byte(tvText), tv); err != nil {
+		return nil, err
+	}
+	return tv, nil
+}
+
+// typedValueString returns the string of a typed value, or an empty string if it is nil
+func typedValueString(tv *sdcpb.TypedValue) string {
+	if tv == nil {
+		return ""
 	}
-	return tv.ToString(), nil
+	return tv.ToString()
 }
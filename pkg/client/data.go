@@ -6,6 +6,10 @@
 //   2. Establishing a port-forward tunnel to a ready pod
 //   3. Connecting via gRPC over the local port-forward
 //
+// Connect retries with exponential backoff and fails over to the next ready pod.
+// When the port-forward drops or the data-server becomes unavailable, calls
+// such as GetIntent reconnect once and are retried transparently.
+//
 // Usage example:
 //
 //	client, err := NewDataClient(restConfig, "sdc-system", "data-server", 56000)
@@ -51,8 +55,9 @@
 // Error handling:
 //
 // The client provides custom error types for better context:
-//   - ConnectionError: for port-forward or gRPC connection failures
-//   - DataFetchError: for data retrieval failures
+//   - ConnectionError: for Kubernetes lookup, port-forward or gRPC connection failures,
+//     wrapping ErrNoReadyPod when the data-server has no ready pod
+//   - DataFetchError: for data retrieval failures, wrapping the gRPC status error
 //
 
 package client
@@ -60,6 +65,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -67,15 +73,20 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/beevik/etree"
-	"github.com/fatih/color"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
@@ -86,18 +97,56 @@ import (
 // It manages the lifecycle of port-forwarding tunnels and gRPC connections to the data server.
 type DataClient struct {
 	restConfig *rest.Config
-	clientset  *kubernetes.Clientset
+	clientset  kubernetes.Interface
 	namespace  string
 	service    string
 	port       int
 
+	// backoff paces the connection attempts of Connect
+	backoff wait.Backoff
+	// forwardPod establishes the port-forward to a pod, replaced in tests
+	forwardPod func(ctx context.Context, pod *corev1.Pod) (*portForward, error)
+
 	// Active port-forward management
-	pf        *portforward.PortForwarder
-	stopChan  chan struct{}
+	fwd  *portForward
+	conn *grpc.ClientConn
+}
+
+// portForward is an established port-forward to a data-server pod
+type portForward struct {
+	pod       string
 	localPort int
-	conn      *grpc.ClientConn
+	// stop is closed to terminate the port-forward
+	stop chan struct{}
+	// done is closed when the port-forward terminated, err tells why
+	done chan struct{}
+	err  error
+}
+
+// dropped returns true if the port-forward terminated without being stopped
+func (f *portForward) dropped() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
 }
 
+// forwardCloseTimeout bounds the time Close waits for the port-forward to stop
+const forwardCloseTimeout = 5 * time.Second
+
+// defaultBackoff retries a failed connection for about 4 seconds
+var defaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// errForwardClosed is the reason of a port-forward that terminated without error
+var errForwardClosed = errors.New("port-forward closed")
+
 // NewDataClient creates a new data service client that will connect via port-forward
 func NewDataClient(restConfig *rest.Config, namespace, service string, port int) (*DataClient, error) {
 	clientset, err := kubernetes.NewForConfig(restConfig)
@@ -105,54 +154,93 @@ func NewDataClient(restConfig *rest.Config, namespace, service string, port int)
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
 	}
 
-	return &DataClient{
+	d := &DataClient{
 		restConfig: restConfig,
 		clientset:  clientset,
 		namespace:  namespace,
 		service:    service,
 		port:       port,
-	}, nil
+		backoff:    defaultBackoff,
+	}
+	d.forwardPod = d.setupPortForward
+	return d, nil
 }
 
-// Connect establishes the port-forward and gRPC connection
+// Connect establishes the port-forward and gRPC connection. Failed attempts are retried
+// with backoff, trying every ready pod of the service in turn.
 func (d *DataClient) Connect(ctx context.Context) error {
-	// Find a free local port
-	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, d.backoff, func(ctx context.Context) (bool, error) {
+		lastErr = d.connectOnce(ctx)
+		if lastErr == nil {
+			return true, nil
+		}
+		if !retryable(lastErr) {
+			return false, lastErr
+		}
+		return false, nil
+	})
+	if wait.Interrupted(err) && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// retryable returns false for the connection errors that another attempt cannot fix
+func retryable(err error) bool {
+	return !apierrors.IsForbidden(err) && !apierrors.IsUnauthorized(err) && !apierrors.IsNotFound(err) &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// connectOnce tries to connect to each ready pod, the pod of a dropped port-forward last
+func (d *DataClient) connectOnce(ctx context.Context) error {
+	pods, err := d.readyDataPods(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to find free port: %w", err)
+		return err
 	}
-	d.localPort = listener.Addr().(*net.TCPAddr).Port
-	if err := listener.Close(); err != nil {
-		return fmt.Errorf("failed to close listener: %w", err)
+	if d.fwd != nil {
+		slices.SortStableFunc(pods, func(a, b *corev1.Pod) int {
+			return boolToInt(a.Name == d.fwd.pod) - boolToInt(b.Name == d.fwd.pod)
+		})
 	}
 
-	// Set up port-forward (this blocks until ready or fails)
-	if err := d.setupPortForward(ctx); err != nil {
-		return fmt.Errorf("failed to setup port-forward: %w", err)
-	}
+	var errs []error
+	for _, pod := range pods {
+		fwd, err := d.forwardPod(ctx, pod)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
-	conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", d.localPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		if closeErr := d.Close(); closeErr != nil {
-			return fmt.Errorf("failed to connect to data service: %w (cleanup failed: %v)", err, closeErr)
+		conn, err := grpc.NewClient(fmt.Sprintf("127.0.0.1:%d", fwd.localPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			close(fwd.stop)
+			return &ConnectionError{Component: "grpc", Namespace: d.namespace, Reason: "failed to connect to data service", Err: err}
 		}
-		return fmt.Errorf("failed to connect to data service: %w", err)
+		d.fwd = fwd
+		d.conn = conn
+		return nil
 	}
+	return errors.Join(errs...)
+}
 
-	d.conn = conn
-	return nil
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
 }
 
-// findDataPod finds a ready pod for the data service using the service's selector
-func (d *DataClient) findDataPod(ctx context.Context) (*corev1.Pod, error) {
+// readyDataPods returns the ready pods of the data service using the service's selector
+func (d *DataClient) readyDataPods(ctx context.Context) ([]*corev1.Pod, error) {
 	// Get the service to find its selector
 	svc, err := d.clientset.CoreV1().Services(d.namespace).Get(ctx, d.service, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get service %s: %w", d.service, err)
+		return nil, &ConnectionError{Component: "kubernetes", Namespace: d.namespace, Reason: fmt.Sprintf("failed to get service %s/%s", d.namespace, d.service), Err: err}
 	}
 
 	if len(svc.Spec.Selector) == 0 {
-		return nil, fmt.Errorf("service %s has no selector", d.service)
+		return nil, &ConnectionError{Component: "kubernetes", Namespace: d.namespace, Reason: fmt.Sprintf("service %s/%s has no selector", d.namespace, d.service), Err: ErrNoReadyPod}
 	}
 
 	// List pods matching the service selector
@@ -164,41 +252,51 @@ func (d *DataClient) findDataPod(ctx context.Context) (*corev1.Pod, error) {
 		LabelSelector: labelSelector,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %w", err)
+		return nil, &ConnectionError{Component: "kubernetes", Namespace: d.namespace, Reason: fmt.Sprintf("failed to list pods of service %s/%s", d.namespace, d.service), Err: err}
 	}
 
 	if len(pods.Items) == 0 {
-		return nil, fmt.Errorf("no pods found for service %s with selector %s", d.service, labelSelector)
+		return nil, &ConnectionError{Component: "kubernetes", Namespace: d.namespace, Reason: fmt.Sprintf("no pods found for service %s/%s with selector %s", d.namespace, d.service, labelSelector), Err: ErrNoReadyPod}
 	}
 
-	// Find a ready pod
+	// Keep the ready pods
+	var ready []*corev1.Pod
 	for i := range pods.Items {
 		pod := &pods.Items[i]
-		if pod.Status.Phase == corev1.PodRunning {
-			for _, cond := range pod.Status.Conditions {
-				if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
-					return pod, nil
-				}
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+				ready = append(ready, pod)
+				break
 			}
 		}
 	}
 
-	return nil, fmt.Errorf("no ready pods found for service %s", d.service)
+	if len(ready) == 0 {
+		return nil, &ConnectionError{Component: "kubernetes", Namespace: d.namespace, Reason: fmt.Sprintf("service %s/%s", d.namespace, d.service), Err: ErrNoReadyPod}
+	}
+	return ready, nil
 }
 
 // setupPortForward creates a programmatic port-forward to the data service pod
-func (d *DataClient) setupPortForward(ctx context.Context) error {
-	// Find a ready pod
-	pod, err := d.findDataPod(ctx)
+func (d *DataClient) setupPortForward(ctx context.Context, pod *corev1.Pod) (*portForward, error) {
+	// Find a free local port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
-		return err
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: "failed to find free port", Err: err}
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+	if err := listener.Close(); err != nil {
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: "failed to close listener", Err: err}
 	}
 
 	// Build the URL for port-forward API
 	hostIP := d.restConfig.Host
 	parsedURL, err := url.Parse(hostIP)
 	if err != nil {
-		return fmt.Errorf("failed to parse host: %w", err)
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: "failed to parse host", Err: err}
 	}
 
 	// Construct the port-forward request URL to the specific pod
@@ -212,42 +310,50 @@ func (d *DataClient) setupPortForward(ctx context.Context) error {
 	// Create SPDY round tripper
 	transport, upgrader, err := spdy.RoundTripperFor(d.restConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create round tripper: %w", err)
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: "failed to create round tripper", Err: err}
 	}
 
 	// Create dialer
 	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, pfURL)
 
-	d.stopChan = make(chan struct{}, 1)
+	fwd := &portForward{
+		pod:       pod.Name,
+		localPort: localPort,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
 	readyChan := make(chan struct{})
 
 	// Create port-forward
-	ports := []string{fmt.Sprintf("%d:%d", d.localPort, d.port)}
+	ports := []string{fmt.Sprintf("%d:%d", localPort, d.port)}
 
 	// Use io.Discard for quiet operation
 	out, errOut := io.Discard, io.Discard
 
-	pf, err := portforward.New(dialer, ports, d.stopChan, readyChan, out, errOut)
+	pf, err := portforward.New(dialer, ports, fwd.stop, readyChan, out, errOut)
 	if err != nil {
-		return fmt.Errorf("failed to create port forwarder: %w", err)
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: "failed to create port forwarder", Err: err}
 	}
 
-	d.pf = pf
-
-	// Start port-forward in background
-	errChan := make(chan error, 1)
+	// Start port-forward in background, done is closed whenever it terminates,
+	// so a forward dropped after ready is detected by the next call
 	go func() {
-		if err := pf.ForwardPorts(); err != nil {
-			errChan <- err
+		fwd.err = pf.ForwardPorts()
+		if fwd.err == nil {
+			fwd.err = errForwardClosed
 		}
+		close(fwd.done)
 	}()
 
 	// Wait for ready or error
 	select {
-	case err := <-errChan:
-		return fmt.Errorf("port-forward failed: %w", err)
+	case <-fwd.done:
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: fmt.Sprintf("failed to forward to pod %s/%s", d.namespace, pod.Name), Err: fwd.err}
+	case <-ctx.Done():
+		close(fwd.stop)
+		return nil, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: fmt.Sprintf("failed to forward to pod %s/%s", d.namespace, pod.Name), Err: ctx.Err()}
 	case <-readyChan:
-		return nil
+		return fwd, nil
 	}
 }
 
@@ -256,21 +362,64 @@ func (d *DataClient) GetConnection() *grpc.ClientConn {
 	return d.conn
 }
 
-// getIntentResponse is a helper that fetches an intent response from the data server
-func (d *DataClient) getIntentResponse(ctx context.Context, datastoreName, intentName string, format sdcpb.Format) (*sdcpb.GetIntentResponse, error) {
+// call runs an idempotent data-server call. If the port-forward dropped or the
+// data-server is unavailable, it reconnects, possibly to another pod, and retries once.
+func (d *DataClient) call(ctx context.Context, fn func(conn *grpc.ClientConn) error) error {
 	if d.conn == nil {
-		return nil, fmt.Errorf("not connected to data server")
+		return errNotConnected
+	}
+	if d.fwd != nil && d.fwd.dropped() {
+		if err := d.reconnect(ctx); err != nil {
+			return err
+		}
 	}
 
-	client := sdcpb.NewDataServerClient(d.conn)
+	err := fn(d.conn)
+	if err == nil || ctx.Err() != nil || d.fwd == nil {
+		return err
+	}
+	// a dropped port-forward surfaces as Unavailable, a restarting data-server as well
+	if status.Code(err) != codes.Unavailable && !d.fwd.dropped() {
+		return err
+	}
+	if rerr := d.reconnect(ctx); rerr != nil {
+		return fmt.Errorf("%w (reconnect failed: %w)", err, rerr)
+	}
+	err = fn(d.conn)
+	if status.Code(err) == codes.Unavailable {
+		return &ConnectionError{Component: "grpc", Namespace: d.namespace, Reason: "data-server unavailable after reconnecting", Err: err}
+	}
+	return err
+}
 
+// reconnect closes the current connection and connects again
+func (d *DataClient) reconnect(ctx context.Context) error {
+	fwd := d.fwd
+	_ = d.Close()
+	// keep the dropped forward so Connect tries the other pods first
+	d.fwd = fwd
+	err := d.Connect(ctx)
+	if err != nil {
+		d.fwd = nil
+	}
+	return err
+}
+
+// getIntentResponse is a helper that fetches an intent response from the data server
+func (d *DataClient) getIntentResponse(ctx context.Context, datastoreName, intentName string, format sdcpb.Format) (*sdcpb.GetIntentResponse, error) {
 	req := &sdcpb.GetIntentRequest{
 		DatastoreName: datastoreName,
 		Intent:        intentName,
 		Format:        format,
 	}
 
-	return client.GetIntent(ctx, req)
+	var resp *sdcpb.GetIntentResponse
+	err := d.call(ctx, func(conn *grpc.ClientConn) error {
+		var err error
+		resp, err = sdcpb.NewDataServerClient(conn).GetIntent(ctx, req)
+		return err
+	})
+	return resp, err
 }
 
 // GetIntent fetches configuration data in the specified format.
@@ -300,7 +449,10 @@ func (d *DataClient) GetIntent(ctx context.Context, format Format, datastoreName
 
 	resp, err := d.getIntentResponse(ctx, datastoreName, intentName, sdcpbFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get intent: %w", err)
+		if isConnectionError(err) {
+			return nil, err
+		}
+		return nil, &DataFetchError{DatastoreName: datastoreName, IntentName: intentName, Reason: "get intent", Err: err}
 	}
 
 	// Create and return the appropriate output type based on format
@@ -318,9 +470,16 @@ func (d *DataClient) Close() error {
 		d.conn = nil
 	}
 
-	if d.stopChan != nil {
-		close(d.stopChan)
-		d.stopChan = nil
+	if d.fwd != nil {
+		close(d.fwd.stop)
+		// wait for the port-forward to be torn down, so an interrupted command
+		// does not exit with the stream to the pod half closed
+		select {
+		case <-d.fwd.done:
+		case <-time.After(forwardCloseTimeout):
+			errs = append(errs, &ConnectionError{Component: "port-forward", Namespace: d.namespace, Reason: fmt.Sprintf("port-forward to pod %s/%s did not stop", d.namespace, d.fwd.pod), Err: context.DeadlineExceeded})
+		}
+		d.fwd = nil
 	}
 
 	if len(errs) > 0 {
@@ -329,10 +488,17 @@ func (d *DataClient) Close() error {
 	return nil
 }
 
+// ErrNoReadyPod is the underlying error of a ConnectionError when the data-server has no ready pod
+var ErrNoReadyPod = errors.New("no ready pod")
+
+// errNotConnected is returned by the data-server calls made before Connect
+var errNotConnected = &ConnectionError{Component: "grpc", Reason: "not connected to data server", Err: errors.New("connect must be called first")}
+
 // ConnectionError represents a failure in establishing or maintaining a connection to the data server.
 // It includes information about which component failed and the underlying error.
 type ConnectionError struct {
 	Component string // The component that failed (e.g., "port-forward", "grpc")
+	Namespace string // The namespace of the data-server, empty when not known
 	Reason    string // Human-readable reason for the failure
 	Err       error  // The underlying error
 }
@@ -348,13 +514,19 @@ func (e *ConnectionError) Unwrap() error {
 // DataFetchError represents a failure when fetching configuration data from the server.
 // It includes the datastore name, intent name, and specific reason for the failure.
 type DataFetchError struct {
-	DatastoreName string // The datastore being accessed (e.g., "sdc.device1")
-	IntentName    string // The intent being fetched (e.g., "running", "config")
+	DatastoreName string // The datastore being accessed (e.g., "sdc.device1"), empty when listing datastores or fetching schemas
+	IntentName    string // The intent being fetched (e.g., "running", "config"), empty when fetching a datastore
 	Reason        string // Human-readable reason for the failure
 	Err           error  // The underlying error
 }
 
 func (e *DataFetchError) Error() string {
+	switch {
+	case e.DatastoreName == "":
+		return fmt.Sprintf("failed to %s: %v", e.Reason, e.Err)
+	case e.IntentName == "":
+		return fmt.Sprintf("failed to fetch %s: %s: %v", e.DatastoreName, e.Reason, e.Err)
+	}
 	return fmt.Sprintf("failed to fetch %s/%s: %s: %v", e.DatastoreName, e.IntentName, e.Reason, e.Err)
 }
 
@@ -423,8 +595,8 @@ func (f *ProtoConfigOutput) String() string {
 		value := update.GetValue().ToString()
 		// Escape special characters to show them literally (\n, \t, etc)
 		escapedValue := valueReplacer.Replace(value)
-		// Color the value in cyan for better distinction
-		coloredValue := color.CyanString(escapedValue)
+		// Color the value for better distinction, when colors are enabled
+		coloredValue := render.Default().Value(escapedValue)
 		lines = append(lines, fmt.Sprintf("%s: %s", path, coloredValue))
 	}
 
@@ -476,6 +648,11 @@ type Intent interface {
 	GetType() Format
 }
 
+// IntentClient fetches the intents of the datastores of the data-server
+type IntentClient interface {
+	GetIntent(ctx context.Context, format Format, datastoreName, intentName string) (Intent, error)
+}
+
 // JSONBlobConfigOutput holds configuration data in JSON format.
 // String() returns automatically pretty-printed JSON output.
 type JSONBlobConfigOutput struct {
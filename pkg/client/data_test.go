@@ -0,0 +1,458 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func dataServerService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sdc-system", Name: "data-server"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "data-server"}},
+	}
+}
+
+func dataServerPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "sdc-system", Name: name, Labels: map[string]string{"app": "data-server"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestReadyDataPods(t *testing.T) {
+	tests := []struct {
+		name      string
+		objects   []runtime.Object
+		forbidden bool
+		wantPods  []string
+		wantErr   func(error) bool
+	}{
+		{
+			name:     "ready pod",
+			objects:  []runtime.Object{dataServerService(), dataServerPod("data-server-0", false), dataServerPod("data-server-1", true), dataServerPod("data-server-2", true)},
+			wantPods: []string{"data-server-1", "data-server-2"},
+		},
+		{
+			name:    "no pods",
+			objects: []runtime.Object{dataServerService()},
+			wantErr: func(err error) bool { return errors.Is(err, ErrNoReadyPod) },
+		},
+		{
+			name:    "no ready pod",
+			objects: []runtime.Object{dataServerService(), dataServerPod("data-server-0", false)},
+			wantErr: func(err error) bool { return errors.Is(err, ErrNoReadyPod) },
+		},
+		{
+			name:    "missing service",
+			wantErr: apierrors.IsNotFound,
+		},
+		{
+			name:      "forbidden",
+			objects:   []runtime.Object{dataServerService()},
+			forbidden: true,
+			wantErr:   apierrors.IsForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset(tt.objects...)
+			if tt.forbidden {
+				clientset.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("RBAC"))
+				})
+			}
+			d := &DataClient{clientset: clientset, namespace: "sdc-system", service: "data-server"}
+
+			pods, err := d.readyDataPods(context.Background())
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("readyDataPods() error = %v", err)
+				}
+				var names []string
+				for _, pod := range pods {
+					names = append(names, pod.Name)
+				}
+				if !slices.Equal(names, tt.wantPods) {
+					t.Fatalf("readyDataPods() = %v, want %v", names, tt.wantPods)
+				}
+				return
+			}
+
+			var connErr *ConnectionError
+			if !errors.As(err, &connErr) {
+				t.Fatalf("readyDataPods() error = %v, want a ConnectionError", err)
+			}
+			if !tt.wantErr(err) {
+				t.Fatalf("readyDataPods() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+// fakeDataServer serves fixed responses for the data-server RPCs
+type fakeDataServer struct {
+	sdcpb.UnimplementedDataServerServer
+	intents    map[string]*sdcpb.Intent
+	datastores map[string]*sdcpb.GetDataStoreResponse
+	err        error
+}
+
+func (f *fakeDataServer) GetIntent(_ context.Context, req *sdcpb.GetIntentRequest) (*sdcpb.GetIntentResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	intent, ok := f.intents[req.GetDatastoreName()+"/"+req.GetIntent()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "intent %s not found", req.GetIntent())
+	}
+	return &sdcpb.GetIntentResponse{Format: sdcpb.Format_Intent_Format_PROTO, Intent: &sdcpb.GetIntentResponse_Proto{Proto: intent}}, nil
+}
+
+func (f *fakeDataServer) GetDataStore(_ context.Context, req *sdcpb.GetDataStoreRequest) (*sdcpb.GetDataStoreResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	ds, ok := f.datastores[req.GetDatastoreName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "datastore %s not found", req.GetDatastoreName())
+	}
+	return ds, nil
+}
+
+func (f *fakeDataServer) ListDataStore(context.Context, *sdcpb.ListDataStoreRequest) (*sdcpb.ListDataStoreResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &sdcpb.ListDataStoreResponse{}
+	for _, ds := range f.datastores {
+		resp.Datastores = append(resp.Datastores, ds)
+	}
+	return resp, nil
+}
+
+// fakeSchemaServer serves the schema-server RPCs alongside the fake data-server
+type fakeSchemaServer struct {
+	sdcpb.UnimplementedSchemaServerServer
+	err error
+}
+
+func (f *fakeSchemaServer) GetSchema(_ context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return nil, status.Errorf(codes.NotFound, "schema %s not found", req.GetSchema().GetName())
+}
+
+func (f *fakeSchemaServer) ListSchema(context.Context, *sdcpb.ListSchemaRequest) (*sdcpb.ListSchemaResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &sdcpb.ListSchemaResponse{}, nil
+}
+
+// newTestDataClient returns a DataClient connected to an in-process data-server
+func newTestDataClient(t *testing.T, srv *fakeDataServer) *DataClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	sdcpb.RegisterDataServerServer(s, srv)
+	sdcpb.RegisterSchemaServerServer(s, &fakeSchemaServer{err: srv.err})
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	d := &DataClient{conn: conn}
+	t.Cleanup(func() { _ = d.Close() })
+	return d
+}
+
+func TestGetIntent(t *testing.T) {
+	d := newTestDataClient(t, &fakeDataServer{
+		intents: map[string]*sdcpb.Intent{
+			"default.srl1/running": {Intent: "running"},
+		},
+	})
+
+	intent, err := d.GetIntent(context.Background(), FormatXPath, "default.srl1", "running")
+	if err != nil {
+		t.Fatalf("GetIntent() error = %v", err)
+	}
+	if intent.GetProto().GetIntent() != "running" {
+		t.Fatalf("GetIntent() = %v, want the running intent", intent.GetProto())
+	}
+
+	_, err = d.GetIntent(context.Background(), FormatXPath, "default.srl1", "intent-a")
+	var fetchErr *DataFetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("GetIntent() error = %v, want a DataFetchError", err)
+	}
+	if fetchErr.DatastoreName != "default.srl1" || fetchErr.IntentName != "intent-a" || status.Code(err) != codes.NotFound {
+		t.Fatalf("GetIntent() unexpected error = %#v", fetchErr)
+	}
+}
+
+func TestDataClientErrors(t *testing.T) {
+	d := newTestDataClient(t, &fakeDataServer{err: status.Error(codes.Unavailable, "shutting down")})
+
+	if _, err := d.GetIntent(context.Background(), FormatXPath, "default.srl1", "running"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("GetIntent() error = %v, want code Unavailable", err)
+	}
+	if _, err := d.ListDataStores(context.Background()); status.Code(err) != codes.Unavailable {
+		t.Fatalf("ListDataStores() error = %v, want code Unavailable", err)
+	}
+
+	var fetchErr *DataFetchError
+	_, err := d.GetDataStore(context.Background(), "default.srl1")
+	if !errors.As(err, &fetchErr) || fetchErr.DatastoreName != "default.srl1" {
+		t.Fatalf("GetDataStore() error = %v, want a DataFetchError of default.srl1", err)
+	}
+
+	_, err = d.ListSchemas(context.Background())
+	if !errors.As(err, &fetchErr) || fetchErr.Reason != "list schemas" || status.Code(err) != codes.Unavailable {
+		t.Fatalf("ListSchemas() error = %v, want an Unavailable DataFetchError", err)
+	}
+	_, err = newTestDataClient(t, &fakeDataServer{}).GetSchemaElem(context.Background(), &sdcpb.Schema{Name: "srl"}, nil)
+	if !errors.As(err, &fetchErr) || fetchErr.Reason != "get schema" || status.Code(err) != codes.NotFound {
+		t.Fatalf("GetSchemaElem() error = %v, want a NotFound DataFetchError", err)
+	}
+
+	var connErr *ConnectionError
+	notConnected := &DataClient{}
+	if _, err := notConnected.GetIntent(context.Background(), FormatXPath, "default.srl1", "running"); !errors.As(err, &connErr) {
+		t.Fatalf("GetIntent() error = %v, want a ConnectionError", err)
+	}
+	if _, err := notConnected.GetSchemaElem(context.Background(), nil, nil); !errors.As(err, &connErr) {
+		t.Fatalf("GetSchemaElem() error = %v, want a ConnectionError", err)
+	}
+	if _, err := notConnected.ListSchemas(context.Background()); !errors.As(err, &connErr) {
+		t.Fatalf("ListSchemas() error = %v, want a ConnectionError", err)
+	}
+}
+
+// fakeForwarder forwards to in-process data-servers listening on TCP, keyed by pod name
+type fakeForwarder struct {
+	mu       sync.Mutex
+	ports    map[string]int
+	forwards []*portForward
+	lost     []chan struct{}
+}
+
+func (f *fakeForwarder) forward(_ context.Context, pod *corev1.Pod) (*portForward, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	port, ok := f.ports[pod.Name]
+	if !ok {
+		return nil, &ConnectionError{Component: "port-forward", Reason: "failed to forward to pod " + pod.Name, Err: errors.New("connection refused")}
+	}
+	fwd := &portForward{pod: pod.Name, localPort: port, stop: make(chan struct{}), done: make(chan struct{})}
+	lost := make(chan struct{})
+	go func() {
+		select {
+		case <-fwd.stop:
+			fwd.err = errForwardClosed
+		case <-lost:
+			fwd.err = errors.New("lost connection to pod")
+		}
+		close(fwd.done)
+	}()
+	f.forwards = append(f.forwards, fwd)
+	f.lost = append(f.lost, lost)
+	return fwd, nil
+}
+
+// drop terminates the last port-forward as if the connection to the pod was lost
+func (f *fakeForwarder) drop() {
+	f.mu.Lock()
+	lost, fwd := f.lost[len(f.lost)-1], f.forwards[len(f.forwards)-1]
+	f.mu.Unlock()
+	close(lost)
+	<-fwd.done
+}
+
+// serveTCP starts an in-process data-server on a local TCP port
+func serveTCP(t *testing.T, srv sdcpb.DataServerServer) int {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := grpc.NewServer()
+	sdcpb.RegisterDataServerServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return lis.Addr().(*net.TCPAddr).Port
+}
+
+func newFailoverClient(t *testing.T, fwd *fakeForwarder, objects ...runtime.Object) (*DataClient, *fake.Clientset) {
+	t.Helper()
+	clientset := fake.NewClientset(objects...)
+	d := &DataClient{
+		clientset:  clientset,
+		namespace:  "sdc-system",
+		service:    "data-server",
+		backoff:    wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3},
+		forwardPod: fwd.forward,
+	}
+	t.Cleanup(func() { _ = d.Close() })
+	return d, clientset
+}
+
+func runningIntentServer(name string) *fakeDataServer {
+	return &fakeDataServer{intents: map[string]*sdcpb.Intent{"default.srl1/running": {Intent: name}}}
+}
+
+func TestConnect_FailsOverToNextPod(t *testing.T) {
+	fwd := &fakeForwarder{ports: map[string]int{"data-server-1": serveTCP(t, runningIntentServer("running"))}}
+	d, _ := newFailoverClient(t, fwd, dataServerService(), dataServerPod("data-server-0", true), dataServerPod("data-server-1", true))
+
+	if err := d.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if d.fwd.pod != "data-server-1" {
+		t.Fatalf("Connect() forwarded to %s, want data-server-1", d.fwd.pod)
+	}
+}
+
+func TestConnect_RetriesUntilPodReady(t *testing.T) {
+	fwd := &fakeForwarder{ports: map[string]int{"data-server-0": serveTCP(t, runningIntentServer("running"))}}
+	d, clientset := newFailoverClient(t, fwd, dataServerService())
+
+	lists := 0
+	clientset.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		if lists < 2 {
+			return true, &corev1.PodList{}, nil
+		}
+		return true, &corev1.PodList{Items: []corev1.Pod{*dataServerPod("data-server-0", true)}}, nil
+	})
+
+	if err := d.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if lists != 2 {
+		t.Fatalf("Connect() listed pods %d times, want 2", lists)
+	}
+}
+
+func TestConnect_GivesUp(t *testing.T) {
+	t.Run("no ready pod after retries", func(t *testing.T) {
+		d, _ := newFailoverClient(t, &fakeForwarder{}, dataServerService(), dataServerPod("data-server-0", false))
+		if err := d.Connect(context.Background()); !errors.Is(err, ErrNoReadyPod) {
+			t.Fatalf("Connect() error = %v, want %v", err, ErrNoReadyPod)
+		}
+	})
+
+	t.Run("forbidden is not retried", func(t *testing.T) {
+		d, clientset := newFailoverClient(t, &fakeForwarder{}, dataServerService())
+		lists := 0
+		clientset.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			lists++
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("RBAC"))
+		})
+		if err := d.Connect(context.Background()); !apierrors.IsForbidden(err) {
+			t.Fatalf("Connect() error = %v, want forbidden", err)
+		}
+		if lists != 1 {
+			t.Fatalf("Connect() listed pods %d times, want 1", lists)
+		}
+	})
+}
+
+func TestGetIntent_ReconnectsAfterDroppedForward(t *testing.T) {
+	fwd := &fakeForwarder{ports: map[string]int{
+		"data-server-0": serveTCP(t, runningIntentServer("from-0")),
+		"data-server-1": serveTCP(t, runningIntentServer("from-1")),
+	}}
+	d, _ := newFailoverClient(t, fwd, dataServerService(), dataServerPod("data-server-0", true), dataServerPod("data-server-1", true))
+
+	if err := d.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	intent, err := d.GetIntent(context.Background(), FormatXPath, "default.srl1", "running")
+	if err != nil || intent.GetProto().GetIntent() != "from-0" {
+		t.Fatalf("GetIntent() = %v, %v, want the intent of data-server-0", intent, err)
+	}
+
+	fwd.drop()
+
+	// the dropped pod is tried last
+	intent, err = d.GetIntent(context.Background(), FormatXPath, "default.srl1", "running")
+	if err != nil || intent.GetProto().GetIntent() != "from-1" {
+		t.Fatalf("GetIntent() = %v, %v, want the intent of data-server-1", intent, err)
+	}
+}
+
+func TestGetIntent_ReconnectsWhenUnavailable(t *testing.T) {
+	fwd := &fakeForwarder{ports: map[string]int{
+		"data-server-0": serveTCP(t, &fakeDataServer{err: status.Error(codes.Unavailable, "shutting down")}),
+	}}
+	d, _ := newFailoverClient(t, fwd, dataServerService(), dataServerPod("data-server-0", true))
+	if err := d.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	// the data-server restarted on another pod
+	fwd.ports["data-server-1"] = serveTCP(t, runningIntentServer("from-1"))
+	delete(fwd.ports, "data-server-0")
+	if _, err := d.clientset.CoreV1().Pods("sdc-system").Create(context.Background(), dataServerPod("data-server-1", true), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create pod: %v", err)
+	}
+
+	intent, err := d.GetIntent(context.Background(), FormatXPath, "default.srl1", "running")
+	if err != nil || intent.GetProto().GetIntent() != "from-1" {
+		t.Fatalf("GetIntent() = %v, %v, want the intent of data-server-1", intent, err)
+	}
+	if len(fwd.forwards) != 2 {
+		t.Fatalf("GetIntent() forwarded %d times, want 2", len(fwd.forwards))
+	}
+}
+
+func TestClose_StopsPortForward(t *testing.T) {
+	fwd := &fakeForwarder{ports: map[string]int{"data-server-0": serveTCP(t, runningIntentServer("running"))}}
+	d, _ := newFailoverClient(t, fwd, dataServerService(), dataServerPod("data-server-0", true))
+	if err := d.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !fwd.forwards[0].dropped() {
+		t.Fatal("Close() returned before the port-forward stopped")
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+}
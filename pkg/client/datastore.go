@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+)
+
+// ListDataStores lists the datastores of the data-server
+func (d *DataClient) ListDataStores(ctx context.Context) ([]*sdcpb.GetDataStoreResponse, error) {
+	var resp *sdcpb.ListDataStoreResponse
+	err := d.call(ctx, func(conn *grpc.ClientConn) error {
+		var err error
+		resp, err = sdcpb.NewDataServerClient(conn).ListDataStore(ctx, &sdcpb.ListDataStoreRequest{})
+		return err
+	})
+	if err != nil {
+		if isConnectionError(err) {
+			return nil, err
+		}
+		return nil, &DataFetchError{Reason: "list datastores", Err: err}
+	}
+	return resp.GetDatastores(), nil
+}
+
+// GetDataStore fetches a single datastore of the data-server
+func (d *DataClient) GetDataStore(ctx context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error) {
+	var resp *sdcpb.GetDataStoreResponse
+	err := d.call(ctx, func(conn *grpc.ClientConn) error {
+		var err error
+		resp, err = sdcpb.NewDataServerClient(conn).GetDataStore(ctx, &sdcpb.GetDataStoreRequest{DatastoreName: datastoreName})
+		return err
+	})
+	if err != nil {
+		if isConnectionError(err) {
+			return nil, err
+		}
+		return nil, &DataFetchError{DatastoreName: datastoreName, Reason: "get datastore", Err: err}
+	}
+	return resp, nil
+}
+
+// DatastoreName returns the name of the datastore of a target, as created by the config-server
+func DatastoreName(namespace, target string) string {
+	return namespace + "." + target
+}
+
+// IntentName returns the name of the data-server intent of a Config, as created by the config-server
+func IntentName(namespace, name string) string {
+	return namespace + "." + name
+}
+
+func isConnectionError(err error) bool {
+	var connErr *ConnectionError
+	return errors.As(err, &connErr)
+}
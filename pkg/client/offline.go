@@ -0,0 +1,266 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrOffline is returned by the operations that need the cluster when reading from files
+var ErrOffline = errors.New("not supported when reading from files")
+
+// ReadBlameTree reads a BlameTreeElement saved as JSON, e.g. by blame --export
+func ReadBlameTree(path string) (*sdcpb.BlameTreeElement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tree := &sdcpb.BlameTreeElement{}
+	if err := protojson.Unmarshal(data, tree); err != nil {
+		return nil, fmt.Errorf("%s: invalid blame tree: %w", path, err)
+	}
+	return tree, nil
+}
+
+// WriteBlameTree writes a BlameTreeElement as indented JSON
+func WriteBlameTree(w io.Writer, tree *sdcpb.BlameTreeElement) error {
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// BlameFileClient serves a blame tree read from a file, whatever the namespace and target
+type BlameFileClient struct {
+	tree *sdcpb.BlameTreeElement
+}
+
+// NewBlameFileClient reads the blame tree of path
+func NewBlameFileClient(path string) (*BlameFileClient, error) {
+	tree, err := ReadBlameTree(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BlameFileClient{tree: tree}, nil
+}
+
+func (c *BlameFileClient) GetBlameTree(_ context.Context, _ string, _ string) (*sdcpb.BlameTreeElement, error) {
+	return c.tree, nil
+}
+
+// BlameRecorder wraps a ConfigClient and keeps the blame tree it fetched, so it can be exported
+type BlameRecorder struct {
+	*ConfigClient
+	tree *sdcpb.BlameTreeElement
+}
+
+func NewBlameRecorder(c *ConfigClient) *BlameRecorder {
+	return &BlameRecorder{ConfigClient: c}
+}
+
+func (r *BlameRecorder) GetBlameTree(ctx context.Context, namespace string, device string) (*sdcpb.BlameTreeElement, error) {
+	tree, err := r.ConfigClient.GetBlameTree(ctx, namespace, device)
+	if err != nil {
+		return nil, err
+	}
+	r.tree = tree
+	return tree, nil
+}
+
+// Tree returns the fetched blame tree, nil if none was fetched
+func (r *BlameRecorder) Tree() *sdcpb.BlameTreeElement {
+	return r.tree
+}
+
+// ReadDeviations reads the Deviation resources of YAML or JSON files. A file may
+// hold several documents and lists, as written by kubectl get deviations -o yaml.
+func ReadDeviations(paths []string) ([]v1alpha1.Deviation, error) {
+	var result []v1alpha1.Deviation
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		devs, err := decodeDeviations(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		result = append(result, devs...)
+	}
+	return result, nil
+}
+
+func decodeDeviations(data []byte) ([]v1alpha1.Deviation, error) {
+	var result []v1alpha1.Deviation
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return result, nil
+			}
+			return nil, fmt.Errorf("decoding document: %w", err)
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw); err != nil {
+			return nil, fmt.Errorf("decoding resource: %w", err)
+		}
+		items := []unstructured.Unstructured{*obj}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("decoding list: %w", err)
+			}
+			items = list.Items
+		}
+
+		for _, item := range items {
+			if item.GetKind() != v1alpha1.DeviationKind {
+				return nil, fmt.Errorf("unsupported kind %q, expected %s", item.GetKind(), v1alpha1.DeviationKind)
+			}
+			var dev v1alpha1.Deviation
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &dev); err != nil {
+				return nil, fmt.Errorf("decoding deviation %s: %w", item.GetName(), err)
+			}
+			result = append(result, dev)
+		}
+	}
+}
+
+// WriteDeviations writes Deviation resources as a YAML DeviationList, readable by ReadDeviations
+func WriteDeviations(w io.Writer, devs []v1alpha1.Deviation) error {
+	list := &v1alpha1.DeviationList{Items: make([]v1alpha1.Deviation, 0, len(devs))}
+	list.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	list.Kind = v1alpha1.DeviationKind + "List"
+	for _, dev := range devs {
+		dev.APIVersion = v1alpha1.SchemeGroupVersion.String()
+		dev.Kind = v1alpha1.DeviationKind
+		dev.ManagedFields = nil
+		list.Items = append(list.Items, dev)
+	}
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// DeviationFileClient serves Deviation resources read from files. An empty
+// namespace matches the deviations of all namespaces.
+type DeviationFileClient struct {
+	deviations []v1alpha1.Deviation
+}
+
+// NewDeviationFileClient reads the Deviation resources of paths
+func NewDeviationFileClient(paths []string) (*DeviationFileClient, error) {
+	devs, err := ReadDeviations(paths)
+	if err != nil {
+		return nil, err
+	}
+	return &DeviationFileClient{deviations: devs}, nil
+}
+
+func (c *DeviationFileClient) GetDeviationByName(_ context.Context, namespace string, deviationName string) (*types.IntentDeviations, error) {
+	for i := range c.deviations {
+		dev := &c.deviations[i]
+		if dev.GetName() == deviationName && matchNamespace(dev, namespace) {
+			return ConvertDeviationIntent(dev)
+		}
+	}
+	return nil, apierrors.NewNotFound(v1alpha1.Resource("deviations"), deviationName)
+}
+
+func (c *DeviationFileClient) GetDeviationsByTarget(_ context.Context, namespace string, targetName string) (types.Deviations, error) {
+	list := &v1alpha1.DeviationList{}
+	for _, dev := range c.deviations {
+		if dev.GetLabels()[TargetLabel] == targetName && matchNamespace(&dev, namespace) {
+			list.Items = append(list.Items, dev)
+		}
+	}
+	return ConvertDeviations(list)
+}
+
+func (c *DeviationFileClient) ClearTargetDeviations(_ context.Context, _ *v1alpha1.TargetClearDeviation) error {
+	return ErrOffline
+}
+
+// Targets returns the sorted names of the targets the deviations belong to
+func (c *DeviationFileClient) Targets() []string {
+	var targets []string
+	for _, dev := range c.deviations {
+		if target, ok := dev.GetLabels()[TargetLabel]; ok && !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+func matchNamespace(dev *v1alpha1.Deviation, namespace string) bool {
+	return namespace == "" || dev.GetNamespace() == "" || dev.GetNamespace() == namespace
+}
+
+// DeviationRecorder wraps a ConfigClient and keeps the Deviation resources it fetched, so they can be exported
+type DeviationRecorder struct {
+	*ConfigClient
+	mu         sync.Mutex
+	deviations []v1alpha1.Deviation
+}
+
+func NewDeviationRecorder(c *ConfigClient) *DeviationRecorder {
+	return &DeviationRecorder{ConfigClient: c}
+}
+
+func (r *DeviationRecorder) GetDeviationByName(ctx context.Context, namespace string, deviationName string) (*types.IntentDeviations, error) {
+	resp, err := r.getDeviation(ctx, namespace, deviationName)
+	if err != nil {
+		return nil, err
+	}
+	r.record(*resp)
+	return ConvertDeviationIntent(resp)
+}
+
+func (r *DeviationRecorder) GetDeviationsByTarget(ctx context.Context, namespace string, targetName string) (types.Deviations, error) {
+	resp, err := r.listDeviations(ctx, namespace, targetName)
+	if err != nil {
+		return nil, err
+	}
+	r.record(resp.Items...)
+	return ConvertDeviations(resp)
+}
+
+func (r *DeviationRecorder) record(devs ...v1alpha1.Deviation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deviations = append(r.deviations, devs...)
+}
+
+// Deviations returns the fetched Deviation resources
+func (r *DeviationRecorder) Deviations() []v1alpha1.Deviation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.deviations)
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/proto"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const deviationsYAML = `apiVersion: config.sdcio.dev/v1alpha1
+kind: Deviation
+metadata:
+  name: srl1
+  namespace: default
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  deviationType: target
+  deviations:
+    - path: /system/name/host-name
+      desiredValue: 'string_val:"srl1"'
+      actualValue: 'string_val:"leaf1"'
+      reason: NOT_APPLIED
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: config.sdcio.dev/v1alpha1
+    kind: Deviation
+    metadata:
+      name: intent-a
+      namespace: prod
+      labels:
+        config.sdcio.dev/targetName: srl2
+    spec:
+      deviationType: config
+      deviations:
+        - path: /interface[name=ethernet-1/1]/admin-state
+          desiredValue: 'string_val:"enable"'
+          reason: UNHANDLED
+`
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestReadDeviations(t *testing.T) {
+	devs, err := ReadDeviations([]string{writeTestFile(t, "deviations.yaml", deviationsYAML)})
+	if err != nil {
+		t.Fatalf("ReadDeviations() error = %v", err)
+	}
+	var names []string
+	for _, dev := range devs {
+		names = append(names, dev.Namespace+"/"+dev.Name)
+	}
+	if want := []string{"default/srl1", "prod/intent-a"}; !slices.Equal(names, want) {
+		t.Fatalf("ReadDeviations() = %v, want %v", names, want)
+	}
+
+	_, err = ReadDeviations([]string{writeTestFile(t, "config.yaml", "apiVersion: config.sdcio.dev/v1alpha1\nkind: Config\nmetadata:\n  name: intent-a\n")})
+	if err == nil || !strings.Contains(err.Error(), `unsupported kind "Config"`) {
+		t.Fatalf("ReadDeviations() error = %v, want unsupported kind", err)
+	}
+}
+
+func TestWriteDeviations_RoundTrip(t *testing.T) {
+	devs, err := ReadDeviations([]string{writeTestFile(t, "deviations.yaml", deviationsYAML)})
+	if err != nil {
+		t.Fatalf("ReadDeviations() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDeviations(&buf, devs); err != nil {
+		t.Fatalf("WriteDeviations() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "apiVersion: config.sdcio.dev/v1alpha1\n") || !strings.Contains(buf.String(), "kind: DeviationList\n") {
+		t.Fatalf("WriteDeviations() wrote unexpected list:\n%s", buf.String())
+	}
+
+	got, err := ReadDeviations([]string{writeTestFile(t, "export.yaml", buf.String())})
+	if err != nil {
+		t.Fatalf("ReadDeviations() of export error = %v", err)
+	}
+	if len(got) != 2 || *got[1].Spec.Deviations[0].DesiredValue != `string_val:"enable"` {
+		t.Fatalf("round trip lost deviations: %+v", got)
+	}
+}
+
+func TestDeviationFileClient(t *testing.T) {
+	cl, err := NewDeviationFileClient([]string{writeTestFile(t, "deviations.yaml", deviationsYAML)})
+	if err != nil {
+		t.Fatalf("NewDeviationFileClient() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if got := cl.Targets(); !slices.Equal(got, []string{"srl1", "srl2"}) {
+		t.Fatalf("Targets() = %v", got)
+	}
+
+	dev, err := cl.GetDeviationByName(ctx, "", "intent-a")
+	if err != nil {
+		t.Fatalf("GetDeviationByName() error = %v", err)
+	}
+	if dev.Target() != "srl2" || len(dev.Deviations()) != 1 || dev.Deviations()[0].DesiredValue != "enable" {
+		t.Fatalf("GetDeviationByName() = %+v", dev)
+	}
+	if _, err := cl.GetDeviationByName(ctx, "default", "intent-a"); !apierrors.IsNotFound(err) {
+		t.Fatalf("GetDeviationByName() in other namespace error = %v, want not found", err)
+	}
+
+	devs, err := cl.GetDeviationsByTarget(ctx, "default", "srl1")
+	if err != nil {
+		t.Fatalf("GetDeviationsByTarget() error = %v", err)
+	}
+	if len(devs) != 1 || devs["srl1"] == nil {
+		t.Fatalf("GetDeviationsByTarget() = %v", devs)
+	}
+
+	if err := cl.ClearTargetDeviations(ctx, nil); !errors.Is(err, ErrOffline) {
+		t.Fatalf("ClearTargetDeviations() error = %v, want %v", err, ErrOffline)
+	}
+}
+
+func TestBlameTreeFile_RoundTrip(t *testing.T) {
+	tree := &sdcpb.BlameTreeElement{
+		Name: "root",
+		Childs: []*sdcpb.BlameTreeElement{
+			{Name: "system", Childs: []*sdcpb.BlameTreeElement{
+				{Name: "name", Owner: "default.intent-a", Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "srl1"}}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBlameTree(&buf, tree); err != nil {
+		t.Fatalf("WriteBlameTree() error = %v", err)
+	}
+
+	cl, err := NewBlameFileClient(writeTestFile(t, "blame.json", buf.String()))
+	if err != nil {
+		t.Fatalf("NewBlameFileClient() error = %v", err)
+	}
+	got, err := cl.GetBlameTree(context.Background(), "any", "any")
+	if err != nil {
+		t.Fatalf("GetBlameTree() error = %v", err)
+	}
+	if !proto.Equal(got, tree) {
+		t.Fatalf("GetBlameTree() = %v, want %v", got, tree)
+	}
+
+	if _, err := NewBlameFileClient(writeTestFile(t, "invalid.json", "{")); err == nil || !strings.Contains(err.Error(), "invalid blame tree") {
+		t.Fatalf("NewBlameFileClient() error = %v, want invalid blame tree", err)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+)
+
+// GetSchemaElem fetches the schema element of the given path from the schema-server
+// served alongside the data-server. Keys in the path are ignored by the schema-server.
+func (d *DataClient) GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	var resp *sdcpb.GetSchemaResponse
+	err := d.call(ctx, func(conn *grpc.ClientConn) error {
+		var err error
+		resp, err = sdcpb.NewSchemaServerClient(conn).GetSchema(ctx, &sdcpb.GetSchemaRequest{
+			Path:            path,
+			Schema:          schema,
+			WithDescription: true,
+		})
+		return err
+	})
+	if err != nil {
+		if isConnectionError(err) {
+			return nil, err
+		}
+		return nil, &DataFetchError{Reason: "get schema", Err: err}
+	}
+	return resp.GetSchema(), nil
+}
+
+// ListSchemas lists the schemas loaded in the schema-server
+func (d *DataClient) ListSchemas(ctx context.Context) ([]*sdcpb.Schema, error) {
+	var resp *sdcpb.ListSchemaResponse
+	err := d.call(ctx, func(conn *grpc.ClientConn) error {
+		var err error
+		resp, err = sdcpb.NewSchemaServerClient(conn).ListSchema(ctx, &sdcpb.ListSchemaRequest{})
+		return err
+	})
+	if err != nil {
+		if isConnectionError(err) {
+			return nil, err
+		}
+		return nil, &DataFetchError{Reason: "list schemas", Err: err}
+	}
+	return resp.GetSchema(), nil
+}
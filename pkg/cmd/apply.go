@@ -17,19 +17,30 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/apply"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/validate"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 )
 
+const (
+	dryRunNone   = "none"
+	dryRunServer = "server"
+)
+
 type ApplyOptions struct {
-	files []string
+	files     []string
+	recursive bool
+	dryRun    string
+	diff      bool
+	validate  bool
+	wait      bool
+	timeout   time.Duration
 	GenericOptions
 }
 
@@ -37,23 +48,14 @@ type ApplyOptions struct {
 func NewApplyOptions(streams genericiooptions.IOStreams) *ApplyOptions {
 	return &ApplyOptions{
 		GenericOptions: GenericOptions{
-			configFlags: genericclioptions.NewConfigFlags(true),
+			configFlags: newConfigFlags(),
 			IOStreams:   streams,
 		},
 	}
 }
 
-func (o *ApplyOptions) Complete(_ *cobra.Command, args []string) error {
-	var err error
-	clientConfig := o.configFlags.ToRawKubeConfigLoader()
-
-	o.restConfig, err = o.configFlags.ToRESTConfig()
-	if err != nil {
-		return err
-	}
-
-	o.namespace, _, err = clientConfig.Namespace()
-	if err != nil {
+func (o *ApplyOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
 		return err
 	}
 
@@ -66,18 +68,51 @@ func (o *ApplyOptions) Validate() error {
 	if len(o.files) == 0 {
 		return fmt.Errorf("must provide at least one filename (use -f or pass paths as arguments)")
 	}
+	switch o.dryRun {
+	case dryRunNone, dryRunServer:
+	default:
+		return fmt.Errorf("invalid --dry-run value %q, must be %q or %q", o.dryRun, dryRunNone, dryRunServer)
+	}
+	if o.wait && o.dryRun != dryRunNone {
+		return fmt.Errorf("--wait cannot be combined with --dry-run")
+	}
+	if o.timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative")
+	}
 	return nil
 }
 
-func (o *ApplyOptions) Run(_ *cobra.Command) error {
-	ctx := context.Background()
+func (o *ApplyOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
 	cl, err := client.NewConfigClient(o.restConfig)
 	if err != nil {
 		return err
 	}
 
-	return apply.Apply(ctx, cl, o.namespace, o.files, o.Out)
+	opts := []apply.ApplyOptionSetter{
+		apply.WithRecursive(o.recursive),
+		apply.WithDryRun(o.dryRun == dryRunServer),
+		apply.WithWait(o.wait, o.timeout),
+	}
+
+	if o.diff || o.validate {
+		dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+		if err != nil {
+			return err
+		}
+		defer closeDataClient()
+
+		if o.diff {
+			opts = append(opts, apply.WithDiff(dataClient))
+		}
+		if o.validate {
+			opts = append(opts, apply.WithValidator(validate.NewValidator(cl, dataClient)))
+		}
+	}
+
+	return apply.Apply(ctx, cl, o.namespace, o.files, o.Out, opts...)
 }
 
 // NewCmdApply provides a cobra command wrapping ApplyOptions
@@ -86,7 +121,7 @@ func NewCmdApply(streams genericiooptions.IOStreams) (*cobra.Command, error) {
 
 	cmd := &cobra.Command{
 		Use:          "apply",
-		Short:        "Apply a resource from a file or stdin",
+		Short:        "Apply SDC resources from a file or stdin",
 		SilenceUsage: true,
 		RunE: func(c *cobra.Command, args []string) error {
 			if err := o.Complete(c, args); err != nil {
@@ -99,8 +134,20 @@ func NewCmdApply(streams genericiooptions.IOStreams) (*cobra.Command, error) {
 		},
 	}
 
-	cmd.Flags().StringSliceVarP(&o.files, "filename", "f", nil, "filename, directory, or URL to files to apply")
+	cmd.Flags().StringSliceVarP(&o.files, "filename", "f", nil, "filename, directory, glob pattern, or URL to files to apply")
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	cmd.Flags().StringVar(&o.dryRun, "dry-run", dryRunNone, `must be "none" or "server". If server, the resources are submitted as server-side dry run without being persisted`)
+	cmd.Flags().BoolVar(&o.diff, "diff", false, "show the intent changes of Config resources compared to the data-server before applying")
+	cmd.Flags().BoolVar(&o.validate, "validate", false, "validate the paths and values of Config resources against the target schema before applying")
+	cmd.Flags().BoolVar(&o.wait, "wait", false, "wait for the applied Config and ConfigSet resources to be ready on the target(s)")
+	cmd.Flags().DurationVar(&o.timeout, "timeout", 5*time.Minute, "the length of time to wait with --wait, zero means wait forever")
 	o.configFlags.AddFlags(cmd.Flags())
 
+	if err := cmd.RegisterFlagCompletionFunc("dry-run", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{dryRunNone, dryRunServer}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		return nil, err
+	}
+
 	return cmd, nil
 }
@@ -17,19 +17,20 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
-	"time"
 
 	fuzzyfinder "github.com/ktr0731/go-fuzzyfinder"
 	"github.com/spf13/cobra"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/blame"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 type BlameOptions struct {
@@ -40,6 +41,10 @@ type BlameOptions struct {
 	filterOwner     []string
 	filterPath      []string
 	filterDeviation bool
+	fromFile        string
+	export          string
+	printFlags      *output.PrintFlags
+	printer         printers.ResourcePrinter
 	GenericOptions
 }
 
@@ -79,33 +84,47 @@ func selectXPathResult(lines []string, interactive bool) (string, error) {
 // NewBlameOptions provides an instance of NamespaceOptions with default values
 func NewBlameOptions(streams genericiooptions.IOStreams) *BlameOptions {
 	return &BlameOptions{
+		printFlags: output.NewPrintFlags(string(blame.BlameFormatTree), string(blame.BlameFormatXPath)),
 		GenericOptions: GenericOptions{
-			configFlags: genericclioptions.NewConfigFlags(true),
+			configFlags: newConfigFlags(),
 			IOStreams:   streams,
 		},
 	}
 }
 
-func (o *BlameOptions) Complete(_ *cobra.Command, _ []string) error {
-	var err error
-	clientConfig := o.configFlags.ToRawKubeConfigLoader()
-
-	o.restConfig, err = o.configFlags.ToRESTConfig()
-	if err != nil {
+func (o *BlameOptions) Complete(c *cobra.Command, _ []string) error {
+	// the blame tree is read from a file, the cluster is not needed
+	if o.fromFile != "" {
+		if err := o.loadSettings(c); err != nil {
+			return err
+		}
+	} else if err := o.complete(c); err != nil {
 		return err
 	}
 
-	// retrieve the actual namespace from clientConfig
-	o.namespace, _, err = clientConfig.Namespace()
-	if err != nil {
-		return err
+	defaultFlag(c, "format", &o.format, o.defaults().Format.Blame)
+	if format, ok := o.printFlags.Alias(); ok {
+		o.format = format
 	}
 
-	return nil
+	// the paths may be given in the gNMI and RESTCONF notations
+	var err error
+	o.filterPath, err = normalizePaths("filter-path", o.filterPath)
+	return err
 }
 
 // Validate validates the options
 func (o *BlameOptions) Validate() error {
+	var err error
+	if o.printer, err = o.printFlags.ToPrinter(); err != nil {
+		return err
+	}
+	if o.fromFile != "" {
+		if o.export != "" {
+			return fmt.Errorf("--export cannot be combined with --from-file")
+		}
+		return nil
+	}
 	if o.target == "" {
 		return fmt.Errorf("target not set")
 	}
@@ -115,13 +134,25 @@ func (o *BlameOptions) Validate() error {
 	return nil
 }
 
-func (o *BlameOptions) Run(_ *cobra.Command) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (o *BlameOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
 	defer cancel()
 
-	cl, err := client.NewConfigClient(o.restConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create config client: %w", err)
+	var cl blame.BlameFilterClient
+	var recorder *client.BlameRecorder
+	if o.fromFile != "" {
+		fileClient, err := client.NewBlameFileClient(o.fromFile)
+		if err != nil {
+			return fmt.Errorf("failed to read blame tree: %w", err)
+		}
+		cl = fileClient
+	} else {
+		configClient, err := client.NewConfigClient(o.restConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create config client: %w", err)
+		}
+		recorder = client.NewBlameRecorder(configClient)
+		cl = recorder
 	}
 
 	// Parse the output format
@@ -145,18 +176,40 @@ func (o *BlameOptions) Run(_ *cobra.Command) error {
 		return fmt.Errorf("failed to run blame: %w", err)
 	}
 
+	// save the unfiltered blame tree
+	if o.export != "" {
+		err := writeFile(o.export, func(w io.Writer) error {
+			return client.WriteBlameTree(w, recorder.Tree())
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export blame tree: %w", err)
+		}
+	}
+
 	if out == nil {
 		return fmt.Errorf("blame returned no output")
 	}
 
+	if o.printer != nil {
+		entries, err := output.BlameEntries(o.namespace, o.target, out)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(entries, o.Out)
+	}
+
 	// generate the output based on the format
 	var result string
+	r := render.Default()
 	switch format {
 	case blame.BlameFormatTree:
-		result = out.ToString()
+		result = r.BlameTree(out)
 	case blame.BlameFormatXPath:
-
-		result, err = selectXPathResult(out.StringSliceXPath(), o.interactive)
+		// the fuzzy finder shows the lines as they are
+		if o.interactive {
+			r = r.Plain()
+		}
+		result, err = selectXPathResult(r.BlameXPaths(out), o.interactive)
 		if err != nil {
 			return fmt.Errorf("failed to select xpath result: %w", err)
 		}
@@ -165,6 +218,10 @@ func (o *BlameOptions) Run(_ *cobra.Command) error {
 	}
 
 	_, _ = fmt.Fprintln(o.Out, result)
+	// the legend explains the colors, so it is left out of uncolored output such as pipes
+	if r.Color && result != "" {
+		_, _ = fmt.Fprintf(o.Out, "\n%s\n", r.BlameLegend())
+	}
 	return nil
 }
 
@@ -194,19 +251,22 @@ func NewCmdBlame(streams genericiooptions.IOStreams) (*cobra.Command, error) {
 	}
 
 	cmd.Flags().StringVar(&o.target, "target", "", "target to get the blame config for")
-	err := cmd.MarkFlagRequired("target")
+	cmd.Flags().StringVar(&o.fromFile, "from-file", "", "read the blame tree from a JSON file (e.g. saved with --export) instead of the cluster")
+	cmd.Flags().StringVar(&o.export, "export", "", "save the fetched blame tree, before filtering, as JSON to the given file")
+	cmd.MarkFlagsOneRequired("target", "from-file")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "export")
 
 	// filter flags
 	cmd.Flags().StringSliceVar(&o.filterLeaf, "filter-leaf", nil, "filter by leaf name (supports wildcards, can be specified multiple times)")
 	cmd.Flags().StringSliceVar(&o.filterOwner, "filter-owner", nil, "filter by owner name (supports wildcards, can be specified multiple times)")
-	cmd.Flags().StringSliceVar(&o.filterPath, "filter-path", nil, "filter by full path (supports wildcards, can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&o.filterPath, "filter-path", nil, "filter by full path (supports wildcards, can be specified multiple times)"+pathFlagHelp)
 	cmd.Flags().StringVar(&o.format, "format", "tree", fmt.Sprintf("output format (%s)", blame.FormatOptionsString()))
 	cmd.Flags().BoolVar(&o.interactive, "interactive", false, "use interactive selector for xpath output")
 	cmd.Flags().BoolVar(&o.filterDeviation, "filter-deviation", false, "filter deviations only")
-
-	if err != nil {
+	if err := o.printFlags.AddFlags(cmd); err != nil {
 		return nil, err
 	}
+	cmd.MarkFlagsMutuallyExclusive("format", "output")
 
 	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
 		return nil, err
@@ -214,6 +274,21 @@ func NewCmdBlame(streams genericiooptions.IOStreams) (*cobra.Command, error) {
 	if err := cmd.RegisterFlagCompletionFunc("format", formatCompletionFunc()); err != nil {
 		return nil, err
 	}
+	targets := func() []string {
+		if o.target == "" {
+			return nil
+		}
+		return []string{o.target}
+	}
+	if err := cmd.RegisterFlagCompletionFunc("filter-path", pathCompletionFunc(o, targets)); err != nil {
+		return nil, err
+	}
+	if err := cmd.RegisterFlagCompletionFunc("filter-owner", ownerCompletionFunc(o, targets)); err != nil {
+		return nil, err
+	}
+	if err := cmd.RegisterFlagCompletionFunc("filter-leaf", leafCompletionFunc(o, targets)); err != nil {
+		return nil, err
+	}
 
 	o.configFlags.AddFlags(cmd.Flags())
 
@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	fuzzyfinder "github.com/ktr0731/go-fuzzyfinder"
@@ -89,19 +92,53 @@ func TestBlameFormat_DefaultAndCompletion(t *testing.T) {
 	}
 }
 
-func TestNewCmdBlameRequiresTarget(t *testing.T) {
-	cmd, err := NewCmdBlame(genericiooptions.NewTestIOStreamsDiscard())
-	if err != nil {
-		t.Fatalf("NewCmdBlame() unexpected error: %v", err)
+func TestNewCmdBlameRequiresTargetOrFromFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "requires one flag",
+			wantErr: "at least one of the flags in the group [target from-file] is required",
+		},
+		{
+			name: "accepts target",
+			args: []string{"--target=srl1"},
+		},
+		{
+			name: "accepts from-file",
+			args: []string{"--from-file=blame.json"},
+		},
+		{
+			name:    "from-file cannot be exported",
+			args:    []string{"--from-file=blame.json", "--export=out.json"},
+			wantErr: "if any flags in the group [from-file export] are set none of the others can be; [export from-file] were all set",
+		},
 	}
 
-	if err := cmd.ParseFlags(nil); err != nil {
-		t.Fatalf("ParseFlags() unexpected error: %v", err)
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := NewCmdBlame(genericiooptions.NewTestIOStreamsDiscard())
+			if err != nil {
+				t.Fatalf("NewCmdBlame() unexpected error: %v", err)
+			}
 
-	err = cmd.ValidateRequiredFlags()
-	if err == nil || err.Error() != `required flag(s) "target" not set` {
-		t.Fatalf("ValidateRequiredFlags() error = %v, want %q", err, `required flag(s) "target" not set`)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() unexpected error: %v", err)
+			}
+
+			err = cmd.ValidateFlagGroups()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateFlagGroups() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("ValidateFlagGroups() error = %v, want %q", err, tt.wantErr)
+			}
+		})
 	}
 }
 
@@ -197,3 +234,39 @@ func TestSelectXPathResult(t *testing.T) {
 		})
 	}
 }
+
+func TestBlameRunFromFile(t *testing.T) {
+	tree := `{
+  "name": "root",
+  "childs": [
+    {"name": "system", "childs": [
+      {"name": "name", "owner": "default.intent-a", "value": {"stringVal": "srl1"}},
+      {"name": "description", "owner": "running", "value": {"stringVal": "lab"}}
+    ]}
+  ]
+}`
+	path := filepath.Join(t.TempDir(), "blame.json")
+	if err := os.WriteFile(path, []byte(tree), 0o600); err != nil {
+		t.Fatalf("write blame tree: %v", err)
+	}
+
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	o := NewBlameOptions(streams)
+	o.fromFile = path
+	o.format = "xpath"
+	o.filterOwner = []string{"default.*"}
+	if err := o.Complete(nil, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := o.Run(nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "/system/name") || strings.Contains(got, "/system/description") {
+		t.Fatalf("Run() output = %q, want only /system/name", got)
+	}
+}
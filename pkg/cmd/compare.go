@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/commands/compare"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type CompareOptions struct {
+	targets     []string
+	paths       []string
+	ignorePaths []string
+	replace     []string
+	rulesFile   string
+	rules       compare.Rules
+	printFlags  *output.PrintFlags
+	printer     printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewCompareOptions provides an instance of CompareOptions with default values
+func NewCompareOptions(streams genericiooptions.IOStreams) *CompareOptions {
+	return &CompareOptions{
+		printFlags: output.NewPrintFlags(),
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *CompareOptions) Complete(c *cobra.Command, _ []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	// the paths may be given in the gNMI and RESTCONF notations
+	for flag, paths := range map[string]*[]string{
+		"path":        &o.paths,
+		"ignore-path": &o.ignorePaths,
+	} {
+		normalized, err := normalizePaths(flag, *paths)
+		if err != nil {
+			return err
+		}
+		*paths = normalized
+	}
+	return nil
+}
+
+// Validate validates the options
+func (o *CompareOptions) Validate() error {
+	if len(o.targets) != 2 {
+		return fmt.Errorf("--target must be set twice, got %d target(s)", len(o.targets))
+	}
+	if o.targets[0] == o.targets[1] {
+		return fmt.Errorf("cannot compare the target %s with itself", o.targets[0])
+	}
+	if o.namespace == "" {
+		return fmt.Errorf("namespace not set")
+	}
+	var err error
+	if o.printer, err = o.printFlags.ToPrinter(); err != nil {
+		return err
+	}
+
+	o.rules = compare.Rules{IgnorePaths: o.ignorePaths}
+	for _, s := range o.replace {
+		rule, err := compare.ParseRule(s)
+		if err != nil {
+			return err
+		}
+		o.rules.Replace = append(o.rules.Replace, rule)
+	}
+	return nil
+}
+
+func (o *CompareOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	rules := o.rules
+	if o.rulesFile != "" {
+		fileRules, err := compare.LoadRules(o.rulesFile)
+		if err != nil {
+			return err
+		}
+		// the rules of the file apply before those of the flags
+		rules = fileRules.Merge(rules)
+	}
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	res, err := compare.Compare(ctx, dataClient, o.namespace, o.targets[0], o.targets[1], o.paths, rules)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		changes, err := output.LeafChanges(o.namespace, res.From, res.To, "", res.Changes)
+		if err != nil {
+			return err
+		}
+		if err := o.printer.PrintObj(output.NewList(changes...), o.Out); err != nil {
+			return err
+		}
+	} else {
+		compare.WriteResult(o.Out, res, render.Default())
+	}
+	if len(res.Changes) > 0 {
+		// a non-zero exit makes compare usable as a CI gate
+		return fmt.Errorf("%w: %s and %s have %d difference(s)", errDifferences, o.targets[0], o.targets[1], len(res.Changes))
+	}
+	return nil
+}
+
+// NewCmdCompare provides a cobra command comparing the running config of two targets
+func NewCmdCompare(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewCompareOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "compare --target A --target B",
+		Short: "Compare the running config of two targets",
+		Long: `Compare the running config of two targets leaf by leaf, e.g. to check that a new
+device matches its twin. The leaves only on A are shown with -, those only on B
+with + and those with different values with ~.
+
+The values that differ by design between the devices, like host names, system
+IPs and MACs, are left out with --ignore-path, or normalized with --replace
+rules REGEX=>REPLACEMENT applied to the paths and values of both targets. The
+rules can be kept in a --rules file:
+
+  ignorePaths:
+  - /system/name/host-name
+  replace:
+  - regex: '10\.0\.0\.\d+/32'
+    replacement: <system-ip>
+  - regex: '(?i)([0-9a-f]{2}:){5}[0-9a-f]{2}'
+    replacement: <mac>
+
+The command exits with 2 when the targets differ, 0 when they match.`,
+		Example: `  # compare a new leaf with its twin, ignoring their host names and system IPs
+  kubectl sdc compare --target leaf1 --target leaf2 --ignore-path /system/name/host-name --replace '10\.0\.0\.\d+/32=><system-ip>'
+
+  # compare the interface configuration only, with the rules of a file
+  kubectl sdc compare --target leaf1 --target leaf2 --path /interface --rules twin-rules.yaml`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run(c)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.targets, "target", nil, "target to compare, must be specified twice")
+	if err := cmd.MarkFlagRequired("target"); err != nil {
+		return nil, err
+	}
+	cmd.Flags().StringSliceVar(&o.paths, "path", nil, "only compare the leaves below this path prefix, can be specified multiple times"+pathFlagHelp)
+	cmd.Flags().StringSliceVar(&o.ignorePaths, "ignore-path", nil, "leave out the leaves below this path prefix, can be specified multiple times"+pathFlagHelp)
+	// a regex may contain commas, the rules are not split like the paths
+	cmd.Flags().StringArrayVar(&o.replace, "replace", nil, "replace the matches of REGEX in the paths and values with REPLACEMENT, as REGEX=>REPLACEMENT, can be specified multiple times")
+	cmd.Flags().StringVar(&o.rulesFile, "rules", "", "YAML file with the ignorePaths and replace rules, applied before those of the flags")
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
+		return nil, err
+	}
+	targets := func() []string { return o.targets }
+	for _, flag := range []string{"path", "ignore-path"} {
+		if err := cmd.RegisterFlagCompletionFunc(flag, pathCompletionFunc(o, targets)); err != nil {
+			return nil, err
+		}
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
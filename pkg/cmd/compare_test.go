@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCompareOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		targets   []string
+		replace   []string
+		wantRules int
+		wantErr   string
+	}{
+		{name: "two targets", targets: []string{"leaf1", "leaf2"}},
+		{name: "replace rules", targets: []string{"leaf1", "leaf2"}, replace: []string{`leaf\d=><leaf>`, `10\.0\.0\.\d{1,3}=><system-ip>`}, wantRules: 2},
+		{name: "one target", targets: []string{"leaf1"}, wantErr: "--target must be set twice, got 1 target(s)"},
+		{name: "three targets", targets: []string{"leaf1", "leaf2", "leaf3"}, wantErr: "--target must be set twice, got 3 target(s)"},
+		{name: "same target", targets: []string{"leaf1", "leaf1"}, wantErr: "cannot compare the target leaf1 with itself"},
+		{name: "invalid rule", targets: []string{"leaf1", "leaf2"}, replace: []string{"leaf1=leaf2"}, wantErr: `invalid rule "leaf1=leaf2", must be REGEX=>REPLACEMENT`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &CompareOptions{targets: tt.targets, replace: tt.replace, GenericOptions: GenericOptions{namespace: "default"}}
+			err := o.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				if len(o.rules.Replace) != tt.wantRules {
+					t.Fatalf("rules = %d, want %d", len(o.rules.Replace), tt.wantRules)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sdcio/kubectl-sdc/pkg/commands/blame"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+// the format defaults are checked against the formats of their command
+func init() {
+	config.RegisterValidator("format.blame", func(v string) error {
+		_, err := blame.ParseFormat(v)
+		return err
+	})
+	config.RegisterValidator("format.deviation", func(v string) error {
+		_, err := parseDeviationOutputFormat(v)
+		return err
+	})
+	config.RegisterValidator("format.runningconfig", func(v string) error {
+		_, err := runningconfig.ParseFormat(v)
+		return err
+	})
+}
+
+// ConfigOptions defines the options of the config commands
+type ConfigOptions struct {
+	path   string
+	minify bool
+	key    string
+	value  string
+	GenericOptions
+}
+
+// NewConfigOptions provides an instance of ConfigOptions with default values
+func NewConfigOptions(streams genericiooptions.IOStreams) *ConfigOptions {
+	return &ConfigOptions{
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *ConfigOptions) Complete(_ *cobra.Command, args []string) error {
+	var err error
+	o.path, err = config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		o.key = args[0]
+	}
+	if len(args) > 1 {
+		o.value = args[1]
+	}
+	return nil
+}
+
+func (o *ConfigOptions) RunView() error {
+	cfg, err := config.Load(o.path)
+	if err != nil {
+		return err
+	}
+
+	if o.minify {
+		kubeContext, err := currentContext(o.configFlags)
+		if err != nil {
+			return err
+		}
+		cfg = &config.Config{Contexts: map[string]*config.Context{kubeContext: cfg.Context(kubeContext)}}
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+func (o *ConfigOptions) RunSet() error {
+	cfg, err := config.Load(o.path)
+	if err != nil {
+		return err
+	}
+	kubeContext, err := currentContext(o.configFlags)
+	if err != nil {
+		return err
+	}
+	if kubeContext == "" {
+		return fmt.Errorf("no current kube context, select one with --context")
+	}
+
+	if err := cfg.Set(kubeContext, o.key, o.value); err != nil {
+		return err
+	}
+	if err := cfg.Save(o.path); err != nil {
+		return err
+	}
+
+	if o.value == "" {
+		_, err = fmt.Fprintf(o.Out, "Unset %s for context %q in %s\n", o.key, kubeContext, o.path)
+	} else {
+		_, err = fmt.Fprintf(o.Out, "Set %s to %q for context %q in %s\n", o.key, o.value, kubeContext, o.path)
+	}
+	return err
+}
+
+// NewCmdConfig provides a cobra command grouping the config subcommands
+func NewCmdConfig(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and set the kubectl-sdc defaults of the kube contexts",
+		Long: `View and set the kubectl-sdc defaults of the kube contexts.
+
+The defaults are stored in $XDG_CONFIG_HOME/kubectl-sdc/config.yaml, by default
+~/.config/kubectl-sdc/config.yaml, or in the file set with $KUBECTL_SDC_CONFIG.
+Flags given on the command line take precedence over the defaults.`,
+	}
+
+	cmd.AddCommand(newCmdConfigView(streams), newCmdConfigSet(streams))
+
+	return cmd, nil
+}
+
+func newCmdConfigView(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewConfigOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "view",
+		Short:        "Show the kubectl-sdc defaults",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			return o.RunView()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.minify, "minify", false, "only show the defaults of the current kube context")
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func newCmdConfigSet(streams genericiooptions.IOStreams) *cobra.Command {
+	o := NewConfigOptions(streams)
+
+	long := "Set a kubectl-sdc default of the current kube context, or of the one given with --context.\nAn empty VALUE unsets the default.\n\nKeys:\n"
+	for _, key := range config.Keys() {
+		long += fmt.Sprintf("  %-22s %s\n", key, config.KeyDescription(key))
+	}
+
+	cmd := &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a kubectl-sdc default of the kube context",
+		Long:  long,
+		Example: `  # show the blame tree as xpaths by default
+  kubectl sdc config set format.blame xpath
+
+  # ignore the deviations of the system information
+  kubectl sdc config set deviation.ignorePaths /system/information,/system/clock
+
+  # unset the default namespace
+  kubectl sdc config set namespace ""`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			var keys []string
+			for _, key := range config.Keys() {
+				keys = append(keys, key+"\t"+config.KeyDescription(key))
+			}
+			return keys, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			return o.RunSet()
+		},
+	}
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
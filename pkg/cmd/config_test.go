@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+)
+
+func TestConfigSet_ValidatesFormats(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid string
+	}{
+		{"format.blame", "xpath"},
+		{"format.deviation", "gnmi-json"},
+		{"format.runningconfig", "cli"},
+	}
+	for _, tt := range tests {
+		cfg := &config.Config{}
+		if err := cfg.Set("kind-sdc", tt.key, "graph"); err == nil || !strings.Contains(err.Error(), tt.key+": invalid format") {
+			t.Errorf("Set(%s, graph) error = %v, want invalid format", tt.key, err)
+		}
+		if err := cfg.Set("kind-sdc", tt.key, tt.valid); err != nil {
+			t.Errorf("Set(%s, %s) error = %v", tt.key, tt.valid, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/commands/datastore"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type DatastoreOptions struct {
+	target        string
+	allNamespaces bool
+	printFlags    *output.PrintFlags
+	printer       printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewDatastoreOptions provides an instance of DatastoreOptions with default values
+func NewDatastoreOptions(streams genericiooptions.IOStreams) *DatastoreOptions {
+	return &DatastoreOptions{
+		printFlags: output.NewPrintFlags(),
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *DatastoreOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.target = args[0]
+	}
+	return nil
+}
+
+func (o *DatastoreOptions) Validate() error {
+	var err error
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
+}
+
+func (o *DatastoreOptions) RunList(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	namespace := o.namespace
+	if o.allNamespaces {
+		namespace = ""
+	}
+
+	summaries, err := datastore.List(ctx, dataClient, namespace)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		objs := make([]*unstructured.Unstructured, 0, len(summaries))
+		for _, s := range summaries {
+			obj, err := datastoreSummaryObject(s)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+		}
+		return o.printer.PrintObj(output.NewList(objs...), o.Out)
+	}
+	if len(summaries) == 0 {
+		if o.allNamespaces {
+			_, _ = fmt.Fprintln(o.ErrOut, "No datastores found.")
+		} else {
+			_, _ = fmt.Fprintf(o.ErrOut, "No datastores found in %s namespace.\n", o.namespace)
+		}
+		return nil
+	}
+	return datastore.WriteList(o.Out, summaries)
+}
+
+func (o *DatastoreOptions) RunGet(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	summary, err := datastore.Get(ctx, dataClient, o.namespace, o.target)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		obj, err := datastoreSummaryObject(summary)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(obj, o.Out)
+	}
+	return datastore.WriteGet(o.Out, summary)
+}
+
+// datastoreSummaryObject returns the DatastoreSummary object of a datastore, in the
+// namespace its name starts with
+func datastoreSummaryObject(s *datastore.Summary) (*unstructured.Unstructured, error) {
+	namespace, _, _ := strings.Cut(s.Name, ".")
+	return output.NewObject(output.KindDatastoreSummary, namespace, s.Name, s)
+}
+
+// NewCmdDatastore provides a cobra command grouping the datastore subcommands
+func NewCmdDatastore(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "datastore",
+		Short: "Inspect the datastores of the data-server",
+		Long: `Inspect the datastores of the data-server.
+
+The status is the connection state of the target as reported by the data-server.
+The data-server API exposes neither the candidates nor the sync state of a datastore,
+use "kubectl sdc target list" for the sync state of the targets.`,
+	}
+
+	listCmd, err := newCmdDatastoreList(streams)
+	if err != nil {
+		return nil, err
+	}
+	getCmd, err := newCmdDatastoreGet(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(listCmd, getCmd)
+
+	return cmd, nil
+}
+
+func newCmdDatastoreList(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewDatastoreOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the datastores with their target, schema and connection status",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunList(c)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "list the datastores of all namespaces")
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func newCmdDatastoreGet(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewDatastoreOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:               "get TARGET",
+		Short:             "Show the datastore of a target including its intents",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: targetCompletionFunc(o),
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunGet(c)
+		},
+	}
+
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
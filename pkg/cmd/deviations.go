@@ -17,20 +17,22 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/deviations"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 // DeviationOptions defines raw options for the deviation command as provided by the user via cobra flags
 type DeviationOptions struct {
-	target                     string
+	targets                    []string
 	deviation                  string
 	format                     string
 	interactive                bool
@@ -39,45 +41,79 @@ type DeviationOptions struct {
 	initialQuery               string
 	selectPathPrefix           []string
 	filterPath                 []string
+	ignorePaths                []string
 	autoAcceptSelectPathPrefix bool
+	fromFiles                  []string
+	export                     string
+	printFlags                 *output.PrintFlags
+	printer                    printers.ResourcePrinter
 	GenericOptions
 }
 
 // NewDeviationOptions provides an instance of DeviationOptions with default values
 func NewDeviationOptions(streams genericiooptions.IOStreams) *DeviationOptions {
 	return &DeviationOptions{
+		printFlags: output.NewPrintFlags(deviationOutputFormatStrings()...),
 		GenericOptions: GenericOptions{
-			configFlags: genericclioptions.NewConfigFlags(true),
+			configFlags: newConfigFlags(),
 			IOStreams:   streams,
 		},
 	}
 }
 
-func (o *DeviationOptions) Complete(_ *cobra.Command, _ []string) error {
-	var err error
-	clientConfig := o.configFlags.ToRawKubeConfigLoader()
-
-	o.restConfig, err = o.configFlags.ToRESTConfig()
-	if err != nil {
+func (o *DeviationOptions) Complete(c *cobra.Command, _ []string) error {
+	// the deviations are read from files, the cluster is not needed. Without
+	// an explicit --namespace the deviations of all namespaces are used.
+	if len(o.fromFiles) > 0 {
+		if o.configFlags.Namespace != nil {
+			o.namespace = *o.configFlags.Namespace
+		}
+		if err := o.loadSettings(c); err != nil {
+			return err
+		}
+	} else if err := o.complete(c); err != nil {
 		return err
 	}
 
-	// retrieve the actual namespace from clientConfig
-	o.namespace, _, err = clientConfig.Namespace()
-	if err != nil {
-		return err
+	defaultFlag(c, "format", &o.format, o.defaults().Format.Deviation)
+	if format, ok := o.printFlags.Alias(); ok {
+		o.format = format
+	}
+	if c != nil && !c.Flags().Changed("ignore-path") {
+		o.ignorePaths = o.defaults().Deviation.IgnorePaths
 	}
 
+	// the paths may be given in the gNMI and RESTCONF notations
+	for flag, paths := range map[string]*[]string{
+		"filter-path":        &o.filterPath,
+		"select-path-prefix": &o.selectPathPrefix,
+		"ignore-path":        &o.ignorePaths,
+	} {
+		normalized, err := normalizePaths(flag, *paths)
+		if err != nil {
+			return err
+		}
+		*paths = normalized
+	}
 	return nil
 }
 
 // Validate validates the options
 func (o *DeviationOptions) Validate() error {
-	if o.deviation == "" && o.target == "" {
-		return fmt.Errorf("deviation or target not set")
-	}
-	if o.namespace == "" {
-		return fmt.Errorf("namespace not set")
+	if len(o.fromFiles) > 0 {
+		if o.export != "" {
+			return fmt.Errorf("--export cannot be combined with --from-file")
+		}
+		if o.revert {
+			return fmt.Errorf("--revert cannot be combined with --from-file")
+		}
+	} else {
+		if o.deviation == "" && len(o.targets) == 0 {
+			return fmt.Errorf("deviation or target not set")
+		}
+		if o.namespace == "" {
+			return fmt.Errorf("namespace not set")
+		}
 	}
 	if !o.interactive && len(o.selectPathPrefix) > 0 {
 		return fmt.Errorf("--select-path-prefix requires --interactive")
@@ -88,14 +124,35 @@ func (o *DeviationOptions) Validate() error {
 	if _, err := parseDeviationOutputFormat(o.format); err != nil {
 		return err
 	}
-	return nil
+	var err error
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
 }
 
-func (o *DeviationOptions) Run(_ *cobra.Command) error {
-	ctx := context.Background()
-	cl, err := client.NewConfigClient(o.restConfig)
-	if err != nil {
-		return err
+func (o *DeviationOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	var cl deviations.DeviationClient
+	var recorder *client.DeviationRecorder
+	targets := o.targets
+	if len(o.fromFiles) > 0 {
+		fileClient, err := client.NewDeviationFileClient(o.fromFiles)
+		if err != nil {
+			return fmt.Errorf("failed to read deviations: %w", err)
+		}
+		// without a deviation or target, all the deviations of the files are used
+		if o.deviation == "" && len(targets) == 0 {
+			targets = fileClient.Targets()
+		}
+		cl = fileClient
+	} else {
+		configClient, err := client.NewConfigClient(o.restConfig)
+		if err != nil {
+			return err
+		}
+		recorder = client.NewDeviationRecorder(configClient)
+		cl = recorder
 	}
 
 	opts := []deviations.DeviationOptionSetter{
@@ -103,15 +160,27 @@ func (o *DeviationOptions) Run(_ *cobra.Command) error {
 		deviations.WithPreview(o.preview),
 		deviations.WithRevert(o.revert),
 		deviations.WithDeviationName(o.deviation),
-		deviations.WithTarget(o.target),
+		deviations.WithTargets(targets),
 		deviations.WithInitialQuery(o.initialQuery),
 		deviations.WithSelectPathPrefix(o.selectPathPrefix),
 		deviations.WithFilterPath(o.filterPath),
+		deviations.WithIgnorePath(o.ignorePaths),
 		deviations.WithAutoAcceptSelectPathPrefix(o.autoAcceptSelectPathPrefix),
+		deviations.WithOutput(o.Out),
 	}
 
 	// Run the deviation selection
 	selectedDeviations, err := deviations.Run(ctx, cl, deviations.NewDeviationOptions(o.namespace, opts...))
+
+	// save the fetched Deviation resources, also when none or none of the filtered ones was found
+	if o.export != "" && (err == nil || errors.Is(err, deviations.ErrNoDeviationsFound) || errors.Is(err, deviations.ErrNoDeviationsAfterPathFiltering)) {
+		exportErr := writeFile(o.export, func(w io.Writer) error {
+			return client.WriteDeviations(w, recorder.Deviations())
+		})
+		if exportErr != nil {
+			return fmt.Errorf("failed to export deviations: %w", exportErr)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -120,12 +189,20 @@ func (o *DeviationOptions) Run(_ *cobra.Command) error {
 		return nil
 	}
 
+	if o.printer != nil {
+		entries, err := output.DeviationEntries(selectedDeviations)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(entries, o.Out)
+	}
+
 	format, err := parseDeviationOutputFormat(o.format)
 	if err != nil {
 		return err
 	}
 
-	output, err := formatSelectedDeviations(selectedDeviations, format)
+	output, err := formatSelectedDeviations(selectedDeviations, o.namespace, format)
 	if err != nil {
 		return err
 	}
@@ -160,17 +237,26 @@ func NewCmdDeviation(streams genericiooptions.IOStreams) (*cobra.Command, error)
 		},
 	}
 
-	cmd.Flags().StringVar(&o.target, "target", "", "target to get the deviations for. This is simply to assist auto-completion of the deviation name")
+	cmd.Flags().StringSliceVar(&o.targets, "target", nil, "target to get the deviations for, can be specified multiple times. Also limits auto-completion of the deviation name")
 	cmd.Flags().StringVar(&o.deviation, "deviation", "", "deviation resource name to query")
 	cmd.Flags().BoolVar(&o.interactive, "interactive", false, "enable interactive fuzzy finder selection")
-	cmd.Flags().StringSliceVar(&o.selectPathPrefix, "select-path-prefix", nil, "mark matching path prefixes as selected in interactive mode")
-	cmd.Flags().StringSliceVar(&o.filterPath, "filter-path", nil, "filter deviation paths by prefix before selection")
+	cmd.Flags().StringSliceVar(&o.selectPathPrefix, "select-path-prefix", nil, "mark matching path prefixes as selected in interactive mode"+pathFlagHelp)
+	cmd.Flags().StringSliceVar(&o.filterPath, "filter-path", nil, "filter deviation paths by prefix before selection"+pathFlagHelp)
+	cmd.Flags().StringSliceVar(&o.ignorePaths, "ignore-path", nil, "ignore the deviations of paths with these prefixes, defaults to deviation.ignorePaths of the kubectl-sdc config"+pathFlagHelp)
 	cmd.Flags().BoolVar(&o.autoAcceptSelectPathPrefix, "auto-accept-select-path-prefix", false, "automatically confirm selected path prefixes in interactive mode")
 	cmd.Flags().StringVar(&o.format, "format", string(deviationOutputFormatText), fmt.Sprintf("output format (%s)", deviationOutputFormatListString()))
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	cmd.MarkFlagsMutuallyExclusive("format", "output")
 	cmd.Flags().BoolVar(&o.preview, "preview", false, "show preview of deviations")
 	cmd.Flags().BoolVar(&o.revert, "revert", false, "revert deviations")
 	cmd.Flags().StringVar(&o.initialQuery, "query", "", "initial query for interactive fuzzy finder")
-	cmd.MarkFlagsOneRequired("deviation", "target")
+	cmd.Flags().StringSliceVar(&o.fromFiles, "from-file", nil, "read the Deviation resources from YAML or JSON files (e.g. saved with --export) instead of the cluster, can be specified multiple times")
+	cmd.Flags().StringVar(&o.export, "export", "", "save the fetched Deviation resources as YAML to the given file")
+	cmd.MarkFlagsOneRequired("deviation", "target", "from-file")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "export")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "revert")
 
 	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
 		return nil, err
@@ -178,6 +264,12 @@ func NewCmdDeviation(streams genericiooptions.IOStreams) (*cobra.Command, error)
 	if err := cmd.RegisterFlagCompletionFunc("deviation", deviationCompletionFunc(o)); err != nil {
 		return nil, err
 	}
+	targets := func() []string { return o.targets }
+	for _, flag := range []string{"filter-path", "select-path-prefix", "ignore-path"} {
+		if err := cmd.RegisterFlagCompletionFunc(flag, pathCompletionFunc(o, targets)); err != nil {
+			return nil, err
+		}
+	}
 	if err := cmd.RegisterFlagCompletionFunc("format", deviationFormatCompletionFunc()); err != nil {
 		return nil, err
 	}
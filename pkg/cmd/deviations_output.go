@@ -3,9 +3,12 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
 	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/gnmi"
 	"github.com/sdcio/kubectl-sdc/pkg/types"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
@@ -17,12 +20,19 @@ const (
 	deviationOutputFormatText         deviationOutputFormat = "text"
 	deviationOutputFormatResourceYAML deviationOutputFormat = "resource-yaml"
 	deviationOutputFormatResourceJSON deviationOutputFormat = "resource-json"
+	// the gNMI formats write a SetRequest per target restoring the desired values
+	deviationOutputFormatGNMIPrototext deviationOutputFormat = deviationOutputFormat(gnmi.FormatPrototext)
+	deviationOutputFormatGNMIJSON      deviationOutputFormat = deviationOutputFormat(gnmi.FormatJSON)
+	deviationOutputFormatGNMISetFile   deviationOutputFormat = deviationOutputFormat(gnmi.FormatSetFile)
 )
 
 var deviationOutputFormats = []deviationOutputFormat{
 	deviationOutputFormatText,
 	deviationOutputFormatResourceYAML,
 	deviationOutputFormatResourceJSON,
+	deviationOutputFormatGNMIPrototext,
+	deviationOutputFormatGNMIJSON,
+	deviationOutputFormatGNMISetFile,
 }
 
 func deviationOutputFormatStrings() []string {
@@ -45,6 +55,12 @@ func parseDeviationOutputFormat(format string) (deviationOutputFormat, error) {
 		return deviationOutputFormatResourceYAML, nil
 	case deviationOutputFormatResourceJSON:
 		return deviationOutputFormatResourceJSON, nil
+	case deviationOutputFormatGNMIPrototext:
+		return deviationOutputFormatGNMIPrototext, nil
+	case deviationOutputFormatGNMIJSON:
+		return deviationOutputFormatGNMIJSON, nil
+	case deviationOutputFormatGNMISetFile:
+		return deviationOutputFormatGNMISetFile, nil
 	default:
 		return "", fmt.Errorf("invalid format %q, must be one of: %s", format, deviationOutputFormatListString())
 	}
@@ -56,7 +72,7 @@ func deviationFormatCompletionFunc() func(cmd *cobra.Command, args []string, toC
 	}
 }
 
-func formatSelectedDeviations(devs types.Deviations, format deviationOutputFormat) (string, error) {
+func formatSelectedDeviations(devs types.Deviations, namespace string, format deviationOutputFormat) (string, error) {
 	if devs == nil || !devs.HasDeviations() {
 		return "", nil
 	}
@@ -65,34 +81,77 @@ func formatSelectedDeviations(devs types.Deviations, format deviationOutputForma
 	case deviationOutputFormatText:
 		return strings.TrimSpace(devs.String()), nil
 	case deviationOutputFormatResourceYAML:
-		resource, err := selectedDeviationsResource(devs)
+		resources, err := selectedDeviationsResources(devs, namespace)
 		if err != nil {
 			return "", err
 		}
-		data, err := yaml.Marshal(resource)
-		if err != nil {
-			return "", err
+		docs := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			data, err := yaml.Marshal(resource)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, strings.TrimSpace(string(data)))
 		}
-		return strings.TrimSpace(string(data)), nil
+		return strings.Join(docs, "\n---\n"), nil
 	case deviationOutputFormatResourceJSON:
-		resource, err := selectedDeviationsResource(devs)
+		resources, err := selectedDeviationsResources(devs, namespace)
 		if err != nil {
 			return "", err
 		}
-		data, err := json.MarshalIndent(resource, "", "  ")
+		docs := make([]string, 0, len(resources))
+		for _, resource := range resources {
+			data, err := json.MarshalIndent(resource, "", "  ")
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, string(data))
+		}
+		return strings.Join(docs, "\n"), nil
+	case deviationOutputFormatGNMIPrototext, deviationOutputFormatGNMIJSON, deviationOutputFormatGNMISetFile:
+		reqs, err := gnmi.FromDeviations(devs)
 		if err != nil {
 			return "", err
 		}
-		return string(data), nil
+		sb := &strings.Builder{}
+		if err := gnmi.Write(sb, gnmi.Format(format), reqs); err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(sb.String(), "\n"), nil
 	default:
 		return "", fmt.Errorf("unsupported output format %q", format)
 	}
 }
 
-func selectedDeviationsResource(devs types.Deviations) (interface{}, error) {
-	first := devs.First()
-	if first == nil {
+// selectedDeviationsResources builds one TargetClearDeviation per selected target, sorted by target.
+// The deviations without a namespace fall back to the namespace of the command.
+func selectedDeviationsResources(devs types.Deviations, namespace string) ([]*v1alpha1.TargetClearDeviation, error) {
+	byTarget := devs.ByTarget()
+	if len(byTarget) == 0 {
 		return nil, fmt.Errorf("no deviations selected")
 	}
-	return client.NewTargetClearDeviation(first.Namespace(), first.Target(), devs), nil
+
+	groups := make(map[types.TargetKey]types.Deviations, len(byTarget))
+	keys := make([]types.TargetKey, 0, len(byTarget))
+	for key, targetDevs := range byTarget {
+		if key.Namespace == "" {
+			key.Namespace = namespace
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = types.Deviations{}
+			keys = append(keys, key)
+		}
+		for name, dev := range targetDevs {
+			group[name] = dev
+		}
+		groups[key] = group
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	resources := make([]*v1alpha1.TargetClearDeviation, 0, len(keys))
+	for _, key := range keys {
+		resources = append(resources, client.NewTargetClearDeviation(key.Namespace, key.Target, groups[key]))
+	}
+	return resources, nil
 }
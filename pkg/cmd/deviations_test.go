@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/pathconv"
 	"github.com/sdcio/kubectl-sdc/pkg/types"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
@@ -14,7 +19,7 @@ import (
 func TestDeviationOptionsValidate(t *testing.T) {
 	tests := []struct {
 		name                       string
-		target                     string
+		targets                    []string
 		deviation                  string
 		format                     string
 		interactive                bool
@@ -36,13 +41,13 @@ func TestDeviationOptionsValidate(t *testing.T) {
 		},
 		{
 			name:      "accepts target only",
-			target:    "target-1",
+			targets:   []string{"target-1"},
 			format:    string(deviationOutputFormatText),
 			namespace: "default",
 		},
 		{
 			name:      "accepts both",
-			target:    "target-1",
+			targets:   []string{"target-1"},
 			deviation: "dev-1",
 			format:    string(deviationOutputFormatText),
 			namespace: "default",
@@ -58,7 +63,7 @@ func TestDeviationOptionsValidate(t *testing.T) {
 			deviation: "dev-1",
 			format:    "bogus",
 			namespace: "default",
-			wantErr:   "invalid format \"bogus\", must be one of: text, resource-yaml, resource-json",
+			wantErr:   "invalid format \"bogus\", must be one of: text, resource-yaml, resource-json, gnmi-prototext, gnmi-json, gnmic-setfile",
 		},
 		{
 			name:             "select path prefix requires interactive",
@@ -81,7 +86,7 @@ func TestDeviationOptionsValidate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			o := &DeviationOptions{
-				target:                     tt.target,
+				targets:                    tt.targets,
 				deviation:                  tt.deviation,
 				format:                     tt.format,
 				interactive:                tt.interactive,
@@ -197,7 +202,7 @@ func TestFormatSelectedDeviations(t *testing.T) {
 	devs.AddDeviation(intent)
 
 	t.Run("text", func(t *testing.T) {
-		out, err := formatSelectedDeviations(devs, deviationOutputFormatText)
+		out, err := formatSelectedDeviations(devs, "default", deviationOutputFormatText)
 		if err != nil {
 			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
 		}
@@ -207,7 +212,7 @@ func TestFormatSelectedDeviations(t *testing.T) {
 	})
 
 	t.Run("resource yaml", func(t *testing.T) {
-		out, err := formatSelectedDeviations(devs, deviationOutputFormatResourceYAML)
+		out, err := formatSelectedDeviations(devs, "default", deviationOutputFormatResourceYAML)
 		if err != nil {
 			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
 		}
@@ -228,7 +233,7 @@ func TestFormatSelectedDeviations(t *testing.T) {
 	})
 
 	t.Run("resource json", func(t *testing.T) {
-		out, err := formatSelectedDeviations(devs, deviationOutputFormatResourceJSON)
+		out, err := formatSelectedDeviations(devs, "default", deviationOutputFormatResourceJSON)
 		if err != nil {
 			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
 		}
@@ -242,8 +247,71 @@ func TestFormatSelectedDeviations(t *testing.T) {
 		}
 	})
 
+	t.Run("resource yaml multiple targets", func(t *testing.T) {
+		multi := types.Deviations{}
+		for _, target := range []string{"target-2", "target-1"} {
+			intent := types.NewDeviations(target, "dev-"+target, types.DeviationTypeConfig, 1).SetNamespace("default")
+			intent.AddDeviation(types.NewDeviation("/system/name", "router-1", "router-2", "mismatch"))
+			multi.AddDeviation(intent)
+		}
+
+		out, err := formatSelectedDeviations(multi, "default", deviationOutputFormatResourceYAML)
+		if err != nil {
+			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
+		}
+
+		docs := strings.Split(out, "\n---\n")
+		if len(docs) != 2 {
+			t.Fatalf("document count = %d, want 2", len(docs))
+		}
+		for i, want := range []string{"target-1", "target-2"} {
+			var resource v1alpha1.TargetClearDeviation
+			if err := yaml.Unmarshal([]byte(docs[i]), &resource); err != nil {
+				t.Fatalf("yaml.Unmarshal() unexpected error: %v", err)
+			}
+			if resource.Name != want {
+				t.Fatalf("resource[%d] name = %q, want %q", i, resource.Name, want)
+			}
+		}
+	})
+
+	t.Run("resource yaml namespace fallback", func(t *testing.T) {
+		noNamespace := types.Deviations{}
+		intent := types.NewDeviations("target-1", "dev-1", types.DeviationTypeConfig, 1)
+		intent.AddDeviation(types.NewDeviation("/system/name", "router-1", "router-2", "mismatch"))
+		noNamespace.AddDeviation(intent)
+
+		out, err := formatSelectedDeviations(noNamespace, "lab", deviationOutputFormatResourceYAML)
+		if err != nil {
+			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
+		}
+
+		var resource v1alpha1.TargetClearDeviation
+		if err := yaml.Unmarshal([]byte(out), &resource); err != nil {
+			t.Fatalf("yaml.Unmarshal() unexpected error: %v", err)
+		}
+		if resource.Namespace != "lab" {
+			t.Fatalf("resource namespace = %q, want %q", resource.Namespace, "lab")
+		}
+	})
+
+	t.Run("gnmic set file", func(t *testing.T) {
+		out, err := formatSelectedDeviations(devs, "default", deviationOutputFormatGNMISetFile)
+		if err != nil {
+			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
+		}
+		want := `# target: target-1
+updates:
+- encoding: json_ietf
+  path: /system/name
+  value: router-1`
+		if out != want {
+			t.Fatalf("output =\n%s\nwant\n%s", out, want)
+		}
+	})
+
 	t.Run("nil selected deviations", func(t *testing.T) {
-		out, err := formatSelectedDeviations(nil, deviationOutputFormatText)
+		out, err := formatSelectedDeviations(nil, "default", deviationOutputFormatText)
 		if err != nil {
 			t.Fatalf("formatSelectedDeviations() unexpected error: %v", err)
 		}
@@ -262,7 +330,7 @@ func TestNewCmdDeviationRequiresOneOfTargetOrDeviation(t *testing.T) {
 		{
 			name:    "requires one flag",
 			args:    nil,
-			wantErr: "at least one of the flags in the group [deviation target] is required",
+			wantErr: "at least one of the flags in the group [deviation target from-file] is required",
 		},
 		{
 			name: "accepts target",
@@ -276,6 +344,15 @@ func TestNewCmdDeviationRequiresOneOfTargetOrDeviation(t *testing.T) {
 			name: "accepts both",
 			args: []string{"--target=target-1", "--deviation=dev-1"},
 		},
+		{
+			name: "accepts from-file",
+			args: []string{"--from-file=deviations.yaml"},
+		},
+		{
+			name:    "from-file cannot be reverted",
+			args:    []string{"--from-file=deviations.yaml", "--revert"},
+			wantErr: "if any flags in the group [from-file revert] are set none of the others can be; [from-file revert] were all set",
+		},
 	}
 
 	for _, tt := range tests {
@@ -303,3 +380,77 @@ func TestNewCmdDeviationRequiresOneOfTargetOrDeviation(t *testing.T) {
 		})
 	}
 }
+
+func TestDeviationRunFromFile(t *testing.T) {
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Deviation
+metadata:
+  name: srl1
+  namespace: default
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  deviationType: target
+  deviations:
+    - path: /system/name/host-name
+      desiredValue: 'string_val:"srl1"'
+      actualValue: 'string_val:"leaf1"'
+      reason: NOT_APPLIED
+    - path: /interface[name=ethernet-1/1]/admin-state
+      desiredValue: 'string_val:"enable"'
+      actualValue: 'string_val:"disable"'
+      reason: NOT_APPLIED
+`
+	path := filepath.Join(t.TempDir(), "deviations.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatalf("write deviations: %v", err)
+	}
+
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	o := NewDeviationOptions(streams)
+	o.fromFiles = []string{path}
+	o.format = string(deviationOutputFormatText)
+	o.filterPath = []string{"/system"}
+	if err := o.Complete(nil, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := o.Run(nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "/system/name/host-name") || strings.Contains(got, "admin-state") {
+		t.Fatalf("Run() output = %q, want only /system/name/host-name", got)
+	}
+}
+
+func TestDeviationCompleteNormalizesPaths(t *testing.T) {
+	streams, _, _, _ := genericiooptions.NewTestIOStreams()
+	o := NewDeviationOptions(streams)
+	o.fromFiles = []string{"deviations.yaml"}
+	o.filterPath = []string{`elem: {name: "system"} elem: {name: "name"}`}
+	o.ignorePaths = []string{"/restconf/data/srl_nokia-system:system/information", "/interface[name=mgmt"}
+	if err := o.Complete(nil, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if got := strings.Join(o.filterPath, ","); got != "/system/name" {
+		t.Fatalf("filterPath = %q, want /system/name", got)
+	}
+	if got := strings.Join(o.ignorePaths, ","); got != "/system/information,/interface[name=mgmt" {
+		t.Fatalf("ignorePaths = %q, want /system/information,/interface[name=mgmt", got)
+	}
+}
+
+func TestDeviationCompleteRefusesRESTCONFListKeys(t *testing.T) {
+	streams, _, _, _ := genericiooptions.NewTestIOStreams()
+	o := NewDeviationOptions(streams)
+	o.fromFiles = []string{"deviations.yaml"}
+	o.filterPath = []string{"/restconf/data/interface=ethernet-1%2F1"}
+	err := o.Complete(nil, nil)
+	if !errors.Is(err, pathconv.ErrSchemaRequired) || !strings.Contains(err.Error(), "invalid --filter-path") || !strings.Contains(err.Error(), "path convert --target") {
+		t.Fatalf("Complete() error = %v, want the --filter-path to need the schema", err)
+	}
+}
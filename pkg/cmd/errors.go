@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Exit codes of the plugin, so scripts can tell the failures that need a different reaction apart
+const (
+	ExitCodeError = 1
+	// ExitCodeDifferences is returned when compare finds differences between the targets
+	ExitCodeDifferences = 2
+	// ExitCodeUnavailable is returned when the data-server cannot be reached
+	ExitCodeUnavailable = 3
+	// ExitCodeForbidden is returned when the kubeconfig user lacks permissions
+	ExitCodeForbidden = 4
+	// ExitCodeNotFound is returned when a datastore or intent does not exist on the data-server
+	ExitCodeNotFound = 5
+	// ExitCodeInterrupted is returned when the command was cancelled by SIGINT or SIGTERM
+	ExitCodeInterrupted = 130
+)
+
+// errDifferences is returned by compare when the targets differ
+var errDifferences = errors.New("the targets differ")
+
+// ExitCode returns the exit code for the error returned by a command
+func ExitCode(err error) int {
+	code, _ := classifyError(err)
+	return code
+}
+
+// ErrorHint returns an actionable hint for the error returned by a command, if any
+func ErrorHint(err error) string {
+	_, hint := classifyError(err)
+	return hint
+}
+
+func classifyError(err error) (int, string) {
+	if err == nil {
+		return 0, ""
+	}
+
+	var connErr *client.ConnectionError
+	var fetchErr *client.DataFetchError
+
+	switch {
+	case errors.Is(err, errDifferences):
+		return ExitCodeDifferences, ""
+	case errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled:
+		return ExitCodeInterrupted, ""
+	case errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded:
+		return ExitCodeError, fmt.Sprintf("the command did not complete in time, retry with a larger --%s", RequestTimeoutFlag)
+	case apierrors.IsForbidden(err) && errors.As(err, &connErr):
+		return ExitCodeForbidden, fmt.Sprintf("your user lacks the permissions to reach the data-server, check with: kubectl auth can-i create pods/portforward -n %s", dataServerNamespace(err))
+	case apierrors.IsForbidden(err):
+		return ExitCodeForbidden, "your user lacks the permissions for this request, check your RBAC with: kubectl auth can-i --list"
+	case errors.Is(err, client.ErrNoReadyPod):
+		return ExitCodeUnavailable, fmt.Sprintf("the data-server has no ready pod, check with: kubectl get pods -n %s", dataServerNamespace(err))
+	case status.Code(err) == codes.Unavailable:
+		return ExitCodeUnavailable, fmt.Sprintf("the data-server is not serving, check its pod and logs with: kubectl get pods -n %s", dataServerNamespace(err))
+	case errors.As(err, &fetchErr) && status.Code(err) == codes.NotFound:
+		switch {
+		case fetchErr.DatastoreName == "":
+			return ExitCodeNotFound, ""
+		case fetchErr.IntentName == "":
+			return ExitCodeNotFound, fmt.Sprintf("datastore %s does not exist, check that the target exists and is ready with: kubectl sdc target list", fetchErr.DatastoreName)
+		}
+		return ExitCodeNotFound, fmt.Sprintf("datastore %s or its intent %s does not exist, check that the target exists and is ready with: kubectl sdc target list", fetchErr.DatastoreName, fetchErr.IntentName)
+	case errors.As(err, &connErr):
+		return ExitCodeUnavailable, ""
+	}
+	return ExitCodeError, ""
+}
+
+// dataServerNamespace returns the namespace of the data-server the error was
+// returned for, the default namespace when the error does not tell
+func dataServerNamespace(err error) string {
+	var connErr *client.ConnectionError
+	if errors.As(err, &connErr) && connErr.Namespace != "" {
+		return connErr.Namespace
+	}
+	return defaultDataServerNamespace
+}
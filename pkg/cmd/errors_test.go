@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyError(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("RBAC"))
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantHint string
+	}{
+		{
+			name:     "no ready pod",
+			err:      fmt.Errorf("failed to connect to data-server: %w", &client.ConnectionError{Component: "kubernetes", Reason: "service sdc-system/data-server", Err: client.ErrNoReadyPod}),
+			wantCode: ExitCodeUnavailable,
+			wantHint: "kubectl get pods -n sdc-system",
+		},
+		{
+			name:     "no ready pod in configured namespace",
+			err:      &client.ConnectionError{Component: "kubernetes", Namespace: "sdc", Reason: "service sdc/data-server", Err: client.ErrNoReadyPod},
+			wantCode: ExitCodeUnavailable,
+			wantHint: "kubectl get pods -n sdc",
+		},
+		{
+			name:     "grpc unavailable after reconnecting",
+			err:      &client.DataFetchError{DatastoreName: "default.srl1", Reason: "get datastore", Err: &client.ConnectionError{Component: "grpc", Namespace: "sdc", Reason: "data-server unavailable after reconnecting", Err: status.Error(codes.Unavailable, "connection refused")}},
+			wantCode: ExitCodeUnavailable,
+			wantHint: "kubectl get pods -n sdc",
+		},
+		{
+			name:     "port-forward forbidden",
+			err:      &client.ConnectionError{Component: "kubernetes", Reason: "failed to list pods", Err: forbidden},
+			wantCode: ExitCodeForbidden,
+			wantHint: "kubectl auth can-i create pods/portforward -n sdc-system",
+		},
+		{
+			name:     "config api forbidden",
+			err:      fmt.Errorf("failed to run blame: %w", forbidden),
+			wantCode: ExitCodeForbidden,
+			wantHint: "kubectl auth can-i --list",
+		},
+		{
+			name:     "grpc unavailable",
+			err:      &client.DataFetchError{DatastoreName: "default.srl1", IntentName: "running", Reason: "get intent", Err: status.Error(codes.Unavailable, "connection refused")},
+			wantCode: ExitCodeUnavailable,
+			wantHint: "the data-server is not serving",
+		},
+		{
+			name:     "datastore not found",
+			err:      &client.DataFetchError{DatastoreName: "default.srl3", Reason: "get datastore", Err: status.Error(codes.NotFound, "unknown datastore")},
+			wantCode: ExitCodeNotFound,
+			wantHint: "datastore default.srl3 does not exist",
+		},
+		{
+			name:     "intent not found",
+			err:      &client.DataFetchError{DatastoreName: "default.srl1", IntentName: "running", Reason: "get intent", Err: status.Error(codes.NotFound, "unknown intent")},
+			wantCode: ExitCodeNotFound,
+			wantHint: "datastore default.srl1 or its intent running does not exist",
+		},
+		{
+			name:     "schema not found",
+			err:      &client.DataFetchError{Reason: "get schema", Err: status.Error(codes.NotFound, "unknown schema")},
+			wantCode: ExitCodeNotFound,
+		},
+		{
+			name:     "other connection error",
+			err:      &client.ConnectionError{Component: "port-forward", Reason: "failed to parse host", Err: errors.New("bad url")},
+			wantCode: ExitCodeUnavailable,
+		},
+		{
+			name:     "interrupted",
+			err:      &client.ConnectionError{Component: "port-forward", Reason: "failed to forward to pod sdc-system/data-server-0", Err: context.Canceled},
+			wantCode: ExitCodeInterrupted,
+		},
+		{
+			name:     "request timeout",
+			err:      &client.DataFetchError{DatastoreName: "default.srl1", IntentName: "running", Reason: "get intent", Err: status.Error(codes.DeadlineExceeded, "context deadline exceeded")},
+			wantCode: ExitCodeError,
+			wantHint: "retry with a larger --request-timeout",
+		},
+		{
+			name:     "targets differ",
+			err:      fmt.Errorf("%w: leaf1 and leaf2 have 3 difference(s)", errDifferences),
+			wantCode: ExitCodeDifferences,
+		},
+		{
+			name:     "generic error",
+			err:      errors.New("target not set"),
+			wantCode: ExitCodeError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.wantCode {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.wantCode)
+			}
+			hint := ErrorHint(tt.err)
+			if tt.wantHint == "" && hint != "" || !strings.Contains(hint, tt.wantHint) {
+				t.Errorf("ErrorHint() = %q, want it to contain %q", hint, tt.wantHint)
+			}
+		})
+	}
+}
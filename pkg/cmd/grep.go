@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/grep"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type GrepOptions struct {
+	pattern    string
+	targets    []string
+	selector   string
+	fieldStr   string
+	regex      bool
+	ignoreCase bool
+	count      bool
+	matcher    *grep.Matcher
+	printFlags *output.PrintFlags
+	printer    printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewGrepOptions provides an instance of GrepOptions with default values
+func NewGrepOptions(streams genericiooptions.IOStreams) *GrepOptions {
+	return &GrepOptions{
+		printFlags: output.NewPrintFlags(),
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *GrepOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.pattern = args[0]
+	}
+	return nil
+}
+
+// Validate validates the options
+func (o *GrepOptions) Validate() error {
+	if o.pattern == "" {
+		return fmt.Errorf("pattern not set")
+	}
+	if o.namespace == "" {
+		return fmt.Errorf("namespace not set")
+	}
+	if len(o.targets) > 0 && o.selector != "" {
+		return fmt.Errorf("--target cannot be combined with --selector")
+	}
+	var err error
+	if o.printer, err = o.printFlags.ToPrinter(); err != nil {
+		return err
+	}
+	if o.printer != nil && o.count {
+		return fmt.Errorf("--count cannot be combined with --output")
+	}
+	field, err := grep.ParseField(o.fieldStr)
+	if err != nil {
+		return err
+	}
+	o.matcher, err = grep.NewMatcher(o.pattern, field, o.regex, o.ignoreCase)
+	return err
+}
+
+func (o *GrepOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	targets := o.targets
+	if len(targets) == 0 {
+		cl, err := client.NewConfigClient(o.restConfig)
+		if err != nil {
+			return err
+		}
+		if targets, err = cl.SelectTargetNames(ctx, o.namespace, o.selector); err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			_, _ = fmt.Fprintf(o.ErrOut, "No targets found in %s namespace.\n", o.namespace)
+			return nil
+		}
+		sort.Strings(targets)
+	}
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	results := grep.Search(ctx, dataClient, o.namespace, targets, o.matcher)
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			_, _ = fmt.Fprintf(o.ErrOut, "warning: failed to search %s: %v\n", r.Target, r.Err)
+		}
+	}
+
+	switch {
+	case o.printer != nil:
+		matches, err := o.matchObjects(results)
+		if err != nil {
+			return err
+		}
+		if err := o.printer.PrintObj(matches, o.Out); err != nil {
+			return err
+		}
+	case o.count:
+		if err := grep.WriteCounts(o.Out, results); err != nil {
+			return err
+		}
+	default:
+		if err := grep.WriteMatches(o.Out, results, render.Default()); err != nil {
+			return err
+		}
+		// the counts go to stderr, the matches stay pipeable
+		if err := grep.WriteCounts(o.ErrOut, results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to search %d of %d target(s)", failed, len(results))
+	}
+	return nil
+}
+
+// matchObjects returns the matching leaves as IntentUpdate objects of the running intent
+func (o *GrepOptions) matchObjects(results []grep.Result) (*unstructured.UnstructuredList, error) {
+	var objs []*unstructured.Unstructured
+	for _, r := range results {
+		for _, m := range r.Matches {
+			obj, err := output.NewObject(output.KindIntentUpdate, o.namespace, m.Path, output.IntentUpdate{
+				Target: r.Target,
+				Intent: runningconfig.RunningIntentName,
+				Path:   m.Path,
+				Value:  m.Value,
+			})
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return output.NewList(objs...), nil
+}
+
+// NewCmdGrep provides a cobra command searching the running config of targets
+func NewCmdGrep(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewGrepOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "grep PATTERN",
+		Short: "Search the running config of targets for leaves matching a pattern",
+		Long: `Search the running config of all targets of the namespace, the targets of a
+label selector or the given targets for leaves whose path or value matches
+PATTERN. Each matching leaf is printed with its target, path and value, followed
+by the number of matches per target.
+
+PATTERN is a wildcard pattern matching the whole path or value, * matches any
+characters and ? a single one. With --regex it is a regular expression matching
+anywhere in the path or value.`,
+		Example: `  # which targets still have the NTP server 10.1.1.1
+  kubectl sdc grep --match path '*/ntp/server[address=10.1.1.1]*'
+
+  # the leaves set to 10.1.1.1 on the targets labelled role=leaf
+  kubectl sdc grep 10.1.1.1 --match value -l role=leaf
+
+  # count the NTP leaves of each target, with a regular expression
+  kubectl sdc grep --regex '/ntp/' --match path --count`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run(c)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.targets, "target", nil, "target to search, can be specified multiple times. All targets of the namespace if not set")
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", "label selector of the targets to search, e.g. role=leaf")
+	cmd.Flags().StringVar(&o.fieldStr, "match", string(grep.FieldBoth), fmt.Sprintf("what PATTERN is matched against (%s)", strings.Join(grep.FieldStrings(), ", ")))
+	cmd.Flags().BoolVarP(&o.regex, "regex", "E", false, "PATTERN is a regular expression instead of a wildcard pattern")
+	cmd.Flags().BoolVarP(&o.ignoreCase, "ignore-case", "i", false, "match PATTERN case insensitively")
+	cmd.Flags().BoolVarP(&o.count, "count", "c", false, "only print the number of matches per target")
+	cmd.MarkFlagsMutuallyExclusive("target", "selector")
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.RegisterFlagCompletionFunc("match", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return grep.FieldStrings(), cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		return nil, err
+	}
+	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
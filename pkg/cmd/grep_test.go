@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestGrepOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		targets  []string
+		selector string
+		field    string
+		regex    bool
+		wantErr  string
+	}{
+		{name: "all targets", pattern: "10.1.1.1", field: "both"},
+		{name: "selector", pattern: "10.1.1.1", selector: "role=leaf", field: "value"},
+		{name: "requires pattern", field: "both", wantErr: "pattern not set"},
+		{name: "target with selector", pattern: "x", targets: []string{"srl1"}, selector: "role=leaf", field: "both", wantErr: "--target cannot be combined with --selector"},
+		{name: "invalid field", pattern: "x", field: "key", wantErr: `invalid match field "key", must be one of: path, value, both`},
+		{name: "invalid regex", pattern: "(", field: "both", regex: true, wantErr: "invalid pattern \"(\": error parsing regexp: missing closing ): `(`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &GrepOptions{
+				pattern:        tt.pattern,
+				targets:        tt.targets,
+				selector:       tt.selector,
+				fieldStr:       tt.field,
+				regex:          tt.regex,
+				GenericOptions: GenericOptions{namespace: "default"},
+			}
+			err := o.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				if o.matcher == nil {
+					t.Fatal("Validate() did not set the matcher")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
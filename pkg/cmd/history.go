@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/history"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type HistoryOptions struct {
+	target  string
+	store   string
+	message string
+	from    string
+	to      string
+	// printFlags is only set for the subcommands taking -o
+	printFlags *output.PrintFlags
+	printer    printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewHistoryOptions provides an instance of HistoryOptions with default values
+func NewHistoryOptions(streams genericiooptions.IOStreams) *HistoryOptions {
+	return &HistoryOptions{
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *HistoryOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	defaultFlag(c, "store", &o.store, o.defaults().History.Store)
+	if len(args) > 0 {
+		o.from = args[0]
+	}
+	if len(args) > 1 {
+		o.to = args[1]
+	}
+	return nil
+}
+
+// Validate validates the options
+func (o *HistoryOptions) Validate() error {
+	if o.target == "" {
+		return fmt.Errorf("target not set")
+	}
+	if o.namespace == "" {
+		return fmt.Errorf("namespace not set")
+	}
+	if o.store != history.StoreLocal && o.store != history.StoreConfigMap {
+		return fmt.Errorf("invalid store %q, must be %s or %s", o.store, history.StoreLocal, history.StoreConfigMap)
+	}
+	var err error
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
+}
+
+// historyClient takes snapshots from the data-server and the Config resources of a target
+type historyClient struct {
+	*client.DataClient
+	*client.ConfigClient
+}
+
+func (o *HistoryOptions) newStore(cl *client.ConfigClient) (history.Store, error) {
+	if o.store == history.StoreConfigMap {
+		return history.NewConfigMapStore(cl), nil
+	}
+	dir, err := history.DefaultFileStoreDir()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewFileStore(dir), nil
+}
+
+// take takes a snapshot of the current state of the target
+func (o *HistoryOptions) take(ctx context.Context, cl *client.ConfigClient) (*history.Snapshot, error) {
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDataClient()
+
+	return history.Take(ctx, historyClient{DataClient: dataClient, ConfigClient: cl}, o.namespace, o.target, o.message, time.Now())
+}
+
+func (o *HistoryOptions) RunSnapshot(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+	store, err := o.newStore(cl)
+	if err != nil {
+		return err
+	}
+
+	s, err := o.take(ctx, cl)
+	if err != nil {
+		return err
+	}
+	if err := store.Save(ctx, s); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(o.Out, "snapshot %s of %s/%s saved to %s\n", s.ID, s.Namespace, s.Target, store)
+	return err
+}
+
+func (o *HistoryOptions) RunList(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+	store, err := o.newStore(cl)
+	if err != nil {
+		return err
+	}
+	snapshots, err := store.List(ctx, o.namespace, o.target)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		objs := make([]*unstructured.Unstructured, 0, len(snapshots))
+		for _, s := range snapshots {
+			obj, err := output.NewObject(output.KindSnapshot, s.Namespace, s.ID, s)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+		}
+		return o.printer.PrintObj(output.NewList(objs...), o.Out)
+	}
+	if len(snapshots) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "No snapshots of %s/%s found in %s.\n", o.namespace, o.target, store)
+		return nil
+	}
+	return history.WriteList(o.Out, snapshots)
+}
+
+// find returns the stored snapshot referenced by an ID or a unique ID prefix
+func (o *HistoryOptions) find(ctx context.Context, store history.Store, ref string) (*history.Snapshot, error) {
+	snapshots, err := store.List(ctx, o.namespace, o.target)
+	if err != nil {
+		return nil, err
+	}
+	return history.Find(snapshots, ref)
+}
+
+func (o *HistoryOptions) RunDiff(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+	store, err := o.newStore(cl)
+	if err != nil {
+		return err
+	}
+
+	from, err := o.find(ctx, store, o.from)
+	if err != nil {
+		return err
+	}
+	toName := "now"
+	var to *history.Snapshot
+	if o.to != "" {
+		if to, err = o.find(ctx, store, o.to); err != nil {
+			return err
+		}
+		toName = to.ID
+	} else if to, err = o.take(ctx, cl); err != nil {
+		return err
+	}
+
+	diffs := history.Diff(from, to)
+	if o.printer != nil {
+		var objs []*unstructured.Unstructured
+		for _, d := range diffs {
+			changes, err := output.LeafChanges(o.namespace, from.ID, toName, d.Name, d.Changes)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, changes...)
+		}
+		return o.printer.PrintObj(output.NewList(objs...), o.Out)
+	}
+	if len(diffs) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "no changes between %s and %s\n", from.ID, toName)
+		return nil
+	}
+	history.WriteDiff(o.Out, diffs, render.Default())
+	return nil
+}
+
+func (o *HistoryOptions) RunRollback(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+	store, err := o.newStore(cl)
+	if err != nil {
+		return err
+	}
+
+	s, err := o.find(ctx, store, o.from)
+	if err != nil {
+		return err
+	}
+	current, err := cl.ListConfigs(ctx, o.namespace, map[string]string{client.TargetLabel: o.target})
+	if err != nil {
+		return err
+	}
+	return history.WritePlan(o.Out, o.ErrOut, s, history.Rollback(s, current))
+}
+
+// NewCmdHistory provides a cobra command grouping the history subcommands
+func NewCmdHistory(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Record snapshots of the configuration of a target, compare and roll back to them",
+	}
+
+	for _, newCmd := range []func(genericiooptions.IOStreams) (*cobra.Command, error){
+		newCmdHistorySnapshot,
+		newCmdHistoryList,
+		newCmdHistoryDiff,
+		newCmdHistoryRollback,
+	} {
+		subCmd, err := newCmd(streams)
+		if err != nil {
+			return nil, err
+		}
+		cmd.AddCommand(subCmd)
+	}
+
+	return cmd, nil
+}
+
+// newCmdHistoryCommand returns a history subcommand with the flags they share
+func newCmdHistoryCommand(o *HistoryOptions, cmd *cobra.Command, run func(*cobra.Command) error) (*cobra.Command, error) {
+	cmd.SilenceUsage = true
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		if err := o.Complete(c, args); err != nil {
+			return err
+		}
+		if err := o.Validate(); err != nil {
+			return err
+		}
+		return run(c)
+	}
+
+	cmd.Flags().StringVar(&o.target, "target", "", "target of the snapshots")
+	if err := cmd.MarkFlagRequired("target"); err != nil {
+		return nil, err
+	}
+	cmd.Flags().StringVar(&o.store, "store", history.StoreLocal, fmt.Sprintf("where the snapshots are stored, %s in the user data directory or %s in the namespace of the target", history.StoreLocal, history.StoreConfigMap))
+	if err := cmd.RegisterFlagCompletionFunc("store", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{history.StoreLocal, history.StoreConfigMap}, cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		return nil, err
+	}
+	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func newCmdHistorySnapshot(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewHistoryOptions(streams)
+
+	cmd, err := newCmdHistoryCommand(o, &cobra.Command{
+		Use:   "snapshot",
+		Short: "Record the running config and the intents of a target",
+		Example: `  # record a snapshot before a maintenance
+  kubectl sdc history snapshot --target srl1 -m "before the upgrade"`,
+		Args: cobra.NoArgs,
+	}, o.RunSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Flags().StringVarP(&o.message, "message", "m", "", "message describing the snapshot")
+
+	return cmd, nil
+}
+
+func newCmdHistoryList(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewHistoryOptions(streams)
+	o.printFlags = output.NewPrintFlags()
+
+	cmd, err := newCmdHistoryCommand(o, &cobra.Command{
+		Use:   "list",
+		Short: "List the snapshots of a target, oldest first",
+		Args:  cobra.NoArgs,
+	}, o.RunList)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func newCmdHistoryDiff(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewHistoryOptions(streams)
+	o.printFlags = output.NewPrintFlags()
+
+	cmd, err := newCmdHistoryCommand(o, &cobra.Command{
+		Use:   "diff FROM [TO]",
+		Short: "Show the changes of the running config and the intents between snapshots",
+		Long: `Show the changes of the running config and the intents of a target between
+two snapshots, or between a snapshot and the current state if TO is not set.
+Snapshots are referenced by their ID or a unique prefix of it.`,
+		Example: `  # what changed on srl1 since yesterday
+  kubectl sdc history diff --target srl1 20261018
+
+  # compare two snapshots
+  kubectl sdc history diff --target srl1 20261018-080000 20261019-080000`,
+		Args: cobra.RangeArgs(1, 2),
+	}, o.RunDiff)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
+
+func newCmdHistoryRollback(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewHistoryOptions(streams)
+
+	return newCmdHistoryCommand(o, &cobra.Command{
+		Use:   "rollback ID",
+		Short: "Print the Config changes returning the intents of a target to a snapshot",
+		Long: `Print the Config resources of the snapshot that are missing or differ now as
+manifests, to review and apply. The Configs created since the snapshot are
+listed with the command deleting them. Nothing is changed on the cluster.`,
+		Example: `  # roll the intents of srl1 back to a snapshot
+  kubectl sdc history rollback --target srl1 20261018-080000 | kubectl sdc apply -f -`,
+		Args: cobra.ExactArgs(1),
+	}, o.RunRollback)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestHistoryOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		namespace string
+		store     string
+		wantErr   string
+	}{
+		{name: "requires target", namespace: "default", store: "local", wantErr: "target not set"},
+		{name: "requires namespace", target: "srl1", store: "local", wantErr: "namespace not set"},
+		{name: "invalid store", target: "srl1", namespace: "default", store: "s3", wantErr: `invalid store "s3", must be local or configmap`},
+		{name: "configmap store", target: "srl1", namespace: "default", store: "configmap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &HistoryOptions{target: tt.target, store: tt.store, GenericOptions: GenericOptions{namespace: tt.namespace}}
+			err := o.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewCmdHistory_DefaultStore(t *testing.T) {
+	cmd, err := NewCmdHistory(genericiooptions.NewTestIOStreamsDiscard())
+	if err != nil {
+		t.Fatalf("NewCmdHistory() unexpected error: %v", err)
+	}
+	for _, sub := range cmd.Commands() {
+		flag := sub.Flags().Lookup("store")
+		if flag == nil || flag.DefValue != "local" {
+			t.Fatalf("%s: store flag = %v, want the default local", sub.Name(), flag)
+		}
+	}
+
+	// the configured store applies unless --store is set
+	o := &HistoryOptions{store: "local", GenericOptions: GenericOptions{settings: &config.Context{History: config.History{Store: "configmap"}}}}
+	c := &cobra.Command{}
+	c.Flags().StringVar(&o.store, "store", "local", "")
+	defaultFlag(c, "store", &o.store, o.defaults().History.Store)
+	if o.store != "configmap" {
+		t.Fatalf("store = %s, want the configured configmap", o.store)
+	}
+}
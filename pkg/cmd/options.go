@@ -1,9 +1,26 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
 	"k8s.io/client-go/rest"
+
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+)
+
+const (
+	// RequestTimeoutFlag is the name of the root flag bounding the run time of a command
+	RequestTimeoutFlag = "request-timeout"
+	// ColorFlag is the name of the root flag selecting when colors are used
+	ColorFlag = "color"
+	// ASCIIFlag is the name of the root flag drawing trees with ASCII characters only
+	ASCIIFlag = "ascii"
 )
 
 // GenericOptions holds common options for all commands and is embedded in specific command options structs
@@ -11,6 +28,8 @@ type GenericOptions struct {
 	restConfig  *rest.Config
 	configFlags *genericclioptions.ConfigFlags
 	namespace   string
+	// settings are the kubectl-sdc defaults of the kube context, set by complete
+	settings *config.Context
 	genericiooptions.IOStreams
 }
 
@@ -21,3 +40,159 @@ func (o *GenericOptions) RESTConfig() *rest.Config {
 func (o *GenericOptions) GetNamespace() string {
 	return o.namespace
 }
+
+// complete loads the REST config and namespace of the kube context, as well as the
+// kubectl-sdc defaults of that context. An explicit --namespace takes precedence over
+// the configured namespace, which takes precedence over the namespace of the context.
+func (o *GenericOptions) complete(c *cobra.Command) error {
+	var err error
+	clientConfig := o.configFlags.ToRawKubeConfigLoader()
+
+	o.restConfig, err = o.configFlags.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := o.loadSettings(c); err != nil {
+		return err
+	}
+
+	// retrieve the actual namespace from clientConfig
+	namespace, explicit, err := clientConfig.Namespace()
+	if err != nil {
+		return err
+	}
+	if !explicit && o.settings.Namespace != "" {
+		namespace = o.settings.Namespace
+	}
+	o.namespace = namespace
+
+	return nil
+}
+
+// loadSettings loads the kubectl-sdc defaults of the kube context selected by the
+// config flags and sets up the rendering
+func (o *GenericOptions) loadSettings(c *cobra.Command) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	kubeContext, err := currentContext(o.configFlags)
+	if err != nil {
+		return err
+	}
+	o.settings = cfg.Context(kubeContext)
+
+	return o.setupRendering(c)
+}
+
+// setupRendering sets up the colors and tree style of the output. An explicit
+// --color takes precedence over the configured color, which takes precedence
+// over the terminal detection.
+func (o *GenericOptions) setupRendering(c *cobra.Command) error {
+	mode := render.ColorAuto
+	if o.settings.Color != nil {
+		mode = render.ColorNever
+		if *o.settings.Color {
+			mode = render.ColorAlways
+		}
+	}
+
+	var ascii bool
+	if c != nil {
+		if f := c.Flags().Lookup(ColorFlag); f != nil && f.Changed {
+			var err error
+			if mode, err = render.ParseColorMode(f.Value.String()); err != nil {
+				return err
+			}
+		}
+		ascii, _ = c.Flags().GetBool(ASCIIFlag)
+	}
+
+	render.Setup(mode, ascii, o.Out)
+	return nil
+}
+
+// defaults returns the kubectl-sdc defaults of the kube context, empty before complete
+func (o *GenericOptions) defaults() *config.Context {
+	if o.settings == nil {
+		return &config.Context{}
+	}
+	return o.settings
+}
+
+// dataServer returns the location of the data-server service, as configured for the kube context
+func (o *GenericOptions) dataServer() dataServer {
+	ds := dataServer{namespace: defaultDataServerNamespace, service: defaultDataServerService}
+	if s := o.defaults().DataServer; s.Namespace != "" {
+		ds.namespace = s.Namespace
+	}
+	if s := o.defaults().DataServer; s.Service != "" {
+		ds.service = s.Service
+	}
+	return ds
+}
+
+// currentContext returns the name of the kube context selected by --context or the kubeconfig
+func currentContext(flags *genericclioptions.ConfigFlags) (string, error) {
+	if flags.Context != nil && *flags.Context != "" {
+		return *flags.Context, nil
+	}
+	raw, err := flags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return "", err
+	}
+	return raw.CurrentContext, nil
+}
+
+// defaultFlag sets value to the configured default def, unless def is empty or the
+// flag was set on the command line
+func defaultFlag(c *cobra.Command, name string, value *string, def string) {
+	if def == "" || c == nil || c.Flags().Changed(name) {
+		return
+	}
+	*value = def
+}
+
+// newConfigFlags returns the kubectl config flags of a command. The kubectl
+// --request-timeout is left out as it is replaced by the root RequestTimeoutFlag.
+func newConfigFlags() *genericclioptions.ConfigFlags {
+	flags := genericclioptions.NewConfigFlags(true)
+	flags.Timeout = nil
+	return flags
+}
+
+// addRequestTimeoutFlag adds the RequestTimeoutFlag to the root command
+func addRequestTimeoutFlag(root *cobra.Command) {
+	root.PersistentFlags().Duration(RequestTimeoutFlag, 0, "the length of time to wait before giving up on a command, including the data-server connection (e.g. 30s, 2m), zero means no timeout")
+}
+
+// addRenderFlags adds the ColorFlag and ASCIIFlag to the root command
+func addRenderFlags(root *cobra.Command) error {
+	root.PersistentFlags().String(ColorFlag, string(render.ColorAuto), fmt.Sprintf("when to color the output, one of: %s. auto colors a terminal unless NO_COLOR is set", strings.Join(render.ColorModeStrings(), ", ")))
+	root.PersistentFlags().Bool(ASCIIFlag, false, "draw trees with ASCII characters only, the default on dumb terminals")
+	return root.RegisterFlagCompletionFunc(ColorFlag, func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return render.ColorModeStrings(), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// commandContext returns the context a command runs with. It is cancelled when the
+// command context is (e.g. on SIGINT) or when the RequestTimeoutFlag expires.
+func commandContext(c *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := context.Background()
+	if c == nil {
+		return context.WithCancel(ctx)
+	}
+	if c.Context() != nil {
+		ctx = c.Context()
+	}
+	timeout, err := c.Flags().GetDuration(RequestTimeoutFlag)
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
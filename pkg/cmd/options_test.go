@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+)
+
+// TestMain keeps the configuration file and history snapshots of the user out
+// of the tests, the options load them on complete
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "kubectl-sdc-cmd-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for env, value := range map[string]string{
+		config.EnvConfig: filepath.Join(dir, "config.yaml"),
+		"XDG_DATA_HOME":  filepath.Join(dir, "data"),
+	} {
+		if err := os.Setenv(env, value); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestCommandContext(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		root := &cobra.Command{Use: "sdc"}
+		addRequestTimeoutFlag(root)
+		sub := &cobra.Command{Use: "blame", RunE: func(*cobra.Command, []string) error { return nil }}
+		root.AddCommand(sub)
+		root.SetArgs(append([]string{"blame"}, args...))
+		parent, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		if err := root.ExecuteContext(parent); err != nil {
+			t.Fatalf("execute: %v", err)
+		}
+		return sub
+	}
+
+	t.Run("no timeout", func(t *testing.T) {
+		ctx, cancel := commandContext(newCmd())
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Fatal("commandContext() has a deadline, want none")
+		}
+	})
+
+	t.Run("request timeout", func(t *testing.T) {
+		ctx, cancel := commandContext(newCmd("--request-timeout", "1m"))
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Fatalf("commandContext() deadline = %v, want within 1m", deadline)
+		}
+	})
+
+	t.Run("cancelled with the command context", func(t *testing.T) {
+		c := newCmd()
+		parent, cancelParent := context.WithCancel(context.Background())
+		c.SetContext(parent)
+		ctx, cancel := commandContext(c)
+		defer cancel()
+		cancelParent()
+		<-ctx.Done()
+	})
+
+	t.Run("without command", func(t *testing.T) {
+		ctx, cancel := commandContext(nil)
+		cancel()
+		<-ctx.Done()
+	})
+}
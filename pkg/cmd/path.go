@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/schema"
+	"github.com/sdcio/kubectl-sdc/pkg/pathconv"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+type PathOptions struct {
+	path     string
+	fromStr  string
+	from     pathconv.Notation
+	toStr    string
+	to       pathconv.Notation
+	target   string
+	vendor   string
+	version  string
+	document string
+	GenericOptions
+}
+
+// NewPathOptions provides an instance of PathOptions with default values
+func NewPathOptions(streams genericiooptions.IOStreams) *PathOptions {
+	return &PathOptions{
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *PathOptions) Complete(c *cobra.Command, args []string) error {
+	// the cluster is only needed to resolve the path from the schema
+	if o.target != "" || o.vendor != "" || o.version != "" {
+		if err := o.complete(c); err != nil {
+			return err
+		}
+	} else if err := o.loadSettings(c); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.path = args[0]
+	}
+	return nil
+}
+
+func (o *PathOptions) Validate() error {
+	if o.target != "" && (o.vendor != "" || o.version != "") {
+		return fmt.Errorf("--target cannot be combined with --vendor and --version")
+	}
+	if (o.vendor == "") != (o.version == "") {
+		return fmt.Errorf("--vendor and --version must be set together")
+	}
+	var err error
+	if o.fromStr != "" {
+		if o.from, err = pathconv.ParseNotation(o.fromStr); err != nil {
+			return err
+		}
+	}
+	if o.toStr != "" {
+		if o.to, err = pathconv.ParseNotation(o.toStr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *PathOptions) RunConvert(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	opts := pathconv.Options{}
+	if o.document != "" {
+		data, err := os.ReadFile(o.document)
+		if err != nil {
+			return fmt.Errorf("failed to read the document: %w", err)
+		}
+		if opts.Document, err = pathconv.ReadDocument(bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+	if o.target != "" || o.vendor != "" {
+		closeDataClient, err := o.resolve(ctx, &opts)
+		if err != nil {
+			return err
+		}
+		defer closeDataClient()
+	}
+
+	p, err := pathconv.Parse(ctx, o.path, o.from, opts)
+	if err != nil {
+		return convertError(err)
+	}
+	if o.to != "" {
+		s, err := p.Format(o.to, opts)
+		if err != nil {
+			return convertError(err)
+		}
+		_, err = fmt.Fprintln(o.Out, s)
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NOTATION\tPATH")
+	for _, n := range pathconv.Notations {
+		s, err := p.Format(n, opts)
+		if errors.Is(err, pathconv.ErrDocumentRequired) {
+			// list entries have no JSON pointer without the configuration
+			continue
+		}
+		if err != nil {
+			return convertError(err)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", n, s)
+	}
+	return w.Flush()
+}
+
+// resolve sets the schema resolver of the options and, for the JSON pointers of
+// a target without --document, its running configuration
+func (o *PathOptions) resolve(ctx context.Context, opts *pathconv.Options) (func(), error) {
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	s, err := schema.ResolveSchema(ctx, cl, o.namespace, o.target, o.vendor, o.version)
+	if err != nil {
+		return nil, err
+	}
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return nil, err
+	}
+	opts.Resolver = &pathconv.SchemaResolver{Client: dataClient, Schema: s}
+
+	usesJSONPointer := o.from == pathconv.NotationJSONPointer || o.to == pathconv.NotationJSONPointer || o.to == ""
+	if opts.Document == nil && o.target != "" && usesJSONPointer {
+		intent, err := runningconfig.Get(ctx, dataClient, o.namespace, o.target, client.FormatJSONIETF)
+		if err != nil {
+			closeDataClient()
+			return nil, err
+		}
+		if opts.Document, err = pathconv.ReadDocument(bytes.NewReader(intent.GetBlob())); err != nil {
+			closeDataClient()
+			return nil, err
+		}
+	}
+	return closeDataClient, nil
+}
+
+// convertError tells which flags provide what a conversion is missing
+func convertError(err error) error {
+	switch {
+	case errors.Is(err, pathconv.ErrSchemaRequired):
+		return fmt.Errorf("%w, set --target or --vendor and --version", err)
+	case errors.Is(err, pathconv.ErrDocumentRequired):
+		return fmt.Errorf("%w, set --document or --target", err)
+	default:
+		return err
+	}
+}
+
+// NewCmdPath provides a cobra command grouping the path subcommands
+func NewCmdPath(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Work with the path notations of SDC, gNMI and RESTCONF",
+	}
+
+	convertCmd, err := newCmdPathConvert(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(convertCmd)
+
+	return cmd, nil
+}
+
+func newCmdPathConvert(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewPathOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "convert PATH",
+		Short: "Convert a path between XPath, gNMI prototext and JSON, JSON pointer and RESTCONF",
+		Example: `  # show a path in all notations
+  kubectl sdc path convert '/interface[name=ethernet-1/1]/mtu'
+
+  # resolve the modules and key names of a RESTCONF path from the schema of a target
+  kubectl sdc path convert /restconf/data/srl_nokia-interfaces:interface=ethernet-1%2F1/mtu --target srl1 --to xpath
+
+  # point into the running configuration of a target
+  kubectl sdc path convert '/interface[name=ethernet-1/1]/mtu' --target srl1 --to json-pointer`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunConvert(c)
+		},
+	}
+
+	notations := strings.Join(pathconv.NotationStrings(), ", ")
+	cmd.Flags().StringVar(&o.fromStr, "from", "", fmt.Sprintf("notation of PATH (%s), detected if not set, a json-pointer is never detected", notations))
+	cmd.Flags().StringVar(&o.toStr, "to", "", fmt.Sprintf("notation to convert to (%s), all notations if not set", notations))
+	cmd.Flags().StringVar(&o.target, "target", "", "target whose schema resolves the modules and keys, and whose running config the JSON pointers point into")
+	cmd.Flags().StringVar(&o.vendor, "vendor", "", "schema vendor, used with --version instead of --target")
+	cmd.Flags().StringVar(&o.version, "version", "", "schema version, used with --vendor instead of --target")
+	cmd.Flags().StringVar(&o.document, "document", "", "JSON IETF configuration file the JSON pointers point into")
+
+	notationCompletion := func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return pathconv.NotationStrings(), cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, flag := range []string{"from", "to"} {
+		if err := cmd.RegisterFlagCompletionFunc(flag, notationCompletion); err != nil {
+			return nil, err
+		}
+	}
+	// the targets are completed from the cluster, which Complete skips without --target
+	schemaOptions := &SchemaOptions{GenericOptions: GenericOptions{configFlags: o.configFlags, IOStreams: streams}}
+	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(schemaOptions)); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
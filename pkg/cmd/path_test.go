@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/pathconv"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+func TestPathOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		vendor  string
+		version string
+		from    string
+		to      string
+		want    pathconv.Notation
+		wantErr string
+	}{
+		{name: "offline", to: "RESTCONF", want: pathconv.NotationRESTCONF},
+		{name: "vendor and version", vendor: "Nokia", version: "24.10.1", to: "xpath", want: pathconv.NotationXPath},
+		{name: "target with vendor", target: "srl1", vendor: "Nokia", wantErr: "--target cannot be combined with --vendor and --version"},
+		{name: "vendor without version", vendor: "Nokia", wantErr: "--vendor and --version must be set together"},
+		{name: "invalid notation", from: "yang", wantErr: `invalid path notation "yang", must be one of: xpath, gnmi-prototext, gnmi-json, json-pointer, restconf`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &PathOptions{target: tt.target, vendor: tt.vendor, version: tt.version, fromStr: tt.from, toStr: tt.to}
+			err := o.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				if o.to != tt.want {
+					t.Fatalf("to = %q, want %q", o.to, tt.want)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPathRunConvert_Document(t *testing.T) {
+	document := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(document, []byte(`{"srl_nokia-system:system":{"name":{"host-name":"srl1"}}}`), 0o600); err != nil {
+		t.Fatalf("write document: %v", err)
+	}
+
+	streams, _, out, _ := genericiooptions.NewTestIOStreams()
+	o := NewPathOptions(streams)
+	o.path = `elem: {name: "system"} elem: {name: "name"} elem: {name: "host-name"}`
+	o.toStr = "json-pointer"
+	o.document = document
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := o.RunConvert(nil); err != nil {
+		t.Fatalf("RunConvert() error = %v", err)
+	}
+	if got, want := out.String(), "/srl_nokia-system:system/name/host-name\n"; got != want {
+		t.Fatalf("RunConvert() output = %q, want %q", got, want)
+	}
+}
+
+func TestPathRunConvert_SchemaRequired(t *testing.T) {
+	streams, _, _, _ := genericiooptions.NewTestIOStreams()
+	o := NewPathOptions(streams)
+	o.path = "/restconf/data/interface=mgmt0"
+	if err := o.RunConvert(nil); err == nil || err.Error() != "the key names of the list interface are unknown: the schema is required, set --target or --vendor and --version" {
+		t.Fatalf("RunConvert() error = %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+// NewCmdRoot provides the kubectl sdc root command with all the sub commands
+func NewCmdRoot(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	root := &cobra.Command{
+		Use: "sdc",
+		Annotations: map[string]string{
+			cobra.CommandDisplayNameAnnotation: "kubectl sdc",
+		},
+	}
+
+	for _, newCmd := range []func(genericiooptions.IOStreams) (*cobra.Command, error){
+		NewCmdBlame,
+		NewCmdDeviation,
+		NewCmdApply,
+		NewCmdValidate,
+		NewCmdRunningConfig,
+		NewCmdTarget,
+		NewCmdSchema,
+		NewCmdPath,
+		NewCmdHistory,
+		NewCmdGrep,
+		NewCmdCompare,
+		NewCmdDatastore,
+		NewCmdConfig,
+	} {
+		cmd, err := newCmd(streams)
+		if err != nil {
+			return nil, err
+		}
+		root.AddCommand(cmd)
+	}
+
+	root.AddCommand(newCmdCompletion(streams))
+	addRequestTimeoutFlag(root)
+	if err := addRenderFlags(root); err != nil {
+		return nil, err
+	}
+	root.Version = "v0.0.0"
+	root.CompletionOptions.DisableDefaultCmd = false
+
+	return root, nil
+}
+
+func newCmdCompletion(streams genericiooptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate completion script",
+		Long: `To load completions:
+
+Bash:
+
+$ source <(kubectl sdc completion bash)
+
+Zsh:
+
+$ source <(kubectl sdc completion zsh)
+
+Fish:
+
+$ kubectl sdc completion fish | source
+`,
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+
+		Args: cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			switch args[0] {
+			case "bash":
+				err = cmd.Root().GenBashCompletion(streams.Out)
+			case "zsh":
+				err = cmd.Root().GenZshCompletion(streams.Out)
+			case "fish":
+				err = cmd.Root().GenFishCompletion(streams.Out, true)
+			case "powershell":
+				err = cmd.Root().GenPowerShellCompletionWithDesc(streams.Out)
+			}
+			return err
+		},
+	}
+}
@@ -17,51 +17,61 @@ limitations under the License.
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"github.com/sdcio/kubectl-sdc/pkg/gnmi"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 type RunningConfigOptions struct {
-	target    string
-	formatStr string
-	format    client.Format
+	target      string
+	path        string
+	cliStyleStr string
+	cliStyle    runningconfig.CLIStyle
+	formatStr   string
+	format      client.Format
+	printFlags  *output.PrintFlags
+	printer     printers.ResourcePrinter
 	GenericOptions
 }
 
 // NewRunningConfigOptions provides an instance of RunningConfigOptions with default values
 func NewRunningConfigOptions(streams genericiooptions.IOStreams) *RunningConfigOptions {
 	return &RunningConfigOptions{
+		// json and yaml select the IntentUpdate objects, --format json|yaml the configuration
+		printFlags: output.NewPrintFlags(append([]string{string(client.FormatJSONIETF), string(client.FormatXML), string(client.FormatXPath), string(runningconfig.FormatCLI)}, gnmi.FormatStrings()...)...),
 		GenericOptions: GenericOptions{
-			configFlags: genericclioptions.NewConfigFlags(true),
+			configFlags: newConfigFlags(),
 			IOStreams:   streams,
 		},
 	}
 }
 
-func (o *RunningConfigOptions) Complete(_ *cobra.Command, _ []string) error {
-	var err error
-	clientConfig := o.configFlags.ToRawKubeConfigLoader()
-
-	o.restConfig, err = o.configFlags.ToRESTConfig()
-	if err != nil {
+func (o *RunningConfigOptions) Complete(c *cobra.Command, _ []string) error {
+	if err := o.complete(c); err != nil {
 		return err
 	}
 
-	// retrieve the actual namespace from clientConfig
-	o.namespace, _, err = clientConfig.Namespace()
-	if err != nil {
-		return err
+	defaultFlag(c, "format", &o.formatStr, o.defaults().Format.RunningConfig)
+	if format, ok := o.printFlags.Alias(); ok {
+		o.formatStr = format
 	}
 
+	// the path may be given in the gNMI and RESTCONF notations
+	if o.path != "" {
+		paths, err := normalizePaths("path", []string{o.path})
+		if err != nil {
+			return err
+		}
+		o.path = paths[0]
+	}
 	return nil
 }
 
@@ -79,36 +89,29 @@ func (o *RunningConfigOptions) Validate() error {
 		return err
 	}
 	o.format = format
-	return nil
-}
-
-func (o *RunningConfigOptions) Run(_ *cobra.Command) error {
-	ctx := context.Background()
-
-	// Get the data-server service port from Kubernetes
-	clientset, err := kubernetes.NewForConfig(o.restConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create kubernetes clientset: %w", err)
-	}
-
-	svc, err := clientset.CoreV1().Services("sdc-system").Get(ctx, "data-server", metav1.GetOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to get data-server service: %w", err)
+	if o.path != "" && !runningconfig.IsGNMIFormat(o.format) {
+		return fmt.Errorf("--path requires one of the formats: %s", strings.Join(gnmi.FormatStrings(), ", "))
 	}
-
-	if len(svc.Spec.Ports) == 0 {
-		return fmt.Errorf("data-server service has no ports")
+	if o.cliStyleStr != "" {
+		if o.format != runningconfig.FormatCLI {
+			return fmt.Errorf("--cli-style requires the format %s", runningconfig.FormatCLI)
+		}
+		if o.cliStyle, err = runningconfig.ParseCLIStyle(o.cliStyleStr); err != nil {
+			return err
+		}
 	}
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
+}
 
-	port, err := runningconfig.ResolveDataServicePort(svc)
-	if err != nil {
-		return err
-	}
+func (o *RunningConfigOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
 
 	// Create data client to fetch running config from data-server
-	dataClient, err := client.NewDataClient(o.restConfig, "sdc-system", "data-server", port)
+	dataClient, err := newDataClient(ctx, o.restConfig, o.dataServer())
 	if err != nil {
-		return fmt.Errorf("failed to create data client: %w", err)
+		return err
 	}
 	defer func() {
 		if err := dataClient.Close(); err != nil {
@@ -116,13 +119,33 @@ func (o *RunningConfigOptions) Run(_ *cobra.Command) error {
 		}
 	}()
 
-	output, err := runningconfig.Run(ctx, dataClient, o.namespace, o.target, o.format)
+	if o.printer != nil {
+		intent, err := runningconfig.Get(ctx, dataClient, o.namespace, o.target, client.FormatXPath)
+		if err != nil {
+			return err
+		}
+		updates, err := output.IntentUpdates(o.namespace, o.target, runningconfig.RunningIntentName, intent.GetProto())
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(updates, o.Out)
+	}
+
+	var result string
+	switch {
+	case o.path != "":
+		result, err = runningconfig.RunSetRequest(ctx, dataClient, o.namespace, o.target, o.path, o.format)
+	case o.cliStyle != "":
+		result, err = runningconfig.RunCLI(ctx, dataClient, o.namespace, o.target, o.cliStyle)
+	default:
+		result, err = runningconfig.Run(ctx, dataClient, o.namespace, o.target, o.format)
+	}
 	if err != nil {
 		return err
 	}
 
 	// Display the formatted output
-	_, err = fmt.Fprintln(o.Out, output)
+	_, err = fmt.Fprintln(o.Out, result)
 	return err
 }
 
@@ -159,6 +182,12 @@ func NewCmdRunningConfig(streams genericiooptions.IOStreams) (*cobra.Command, er
 	// Build format help text dynamically
 	formatHelp := fmt.Sprintf("output format (%s)", runningconfig.FormatListString())
 	cmd.Flags().StringVar(&o.formatStr, "format", "xpath", formatHelp)
+	cmd.Flags().StringVar(&o.cliStyleStr, "cli-style", "", fmt.Sprintf("style of the cli format (%s), defaults to %s", strings.Join(runningconfig.CLIStyleStrings(), ", "), runningconfig.CLIStyleHierarchy))
+	cmd.Flags().StringVar(&o.path, "path", "", fmt.Sprintf("subtree replaced by the gNMI SetRequest of the formats %s, defaults to the whole configuration", strings.Join(gnmi.FormatStrings(), ", "))+pathFlagHelp)
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	cmd.MarkFlagsMutuallyExclusive("format", "output")
 
 	// Format flag completion
 	if err := cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -167,9 +196,17 @@ func NewCmdRunningConfig(streams genericiooptions.IOStreams) (*cobra.Command, er
 		return nil, err
 	}
 
+	if err := cmd.RegisterFlagCompletionFunc("cli-style", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return runningconfig.CLIStyleStrings(), cobra.ShellCompDirectiveNoFileComp
+	}); err != nil {
+		return nil, err
+	}
 	if err := cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o)); err != nil {
 		return nil, err
 	}
+	if err := cmd.RegisterFlagCompletionFunc("path", pathCompletionFunc(o, func() []string { return []string{o.target} })); err != nil {
+		return nil, err
+	}
 	o.configFlags.AddFlags(cmd.Flags())
 
 	return cmd, nil
@@ -1,17 +1,22 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
 	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 func TestRunningConfigOptionsValidate(t *testing.T) {
 	tests := []struct {
 		name      string
 		target    string
+		path      string
+		cliStyle  string
 		formatStr string
 		namespace string
 		want      client.Format
@@ -19,15 +24,22 @@ func TestRunningConfigOptionsValidate(t *testing.T) {
 	}{
 		{name: "requires target", namespace: "default", wantErr: "target not set"},
 		{name: "requires namespace", target: "srl1", wantErr: "namespace not set"},
-		{name: "invalid format", target: "srl1", namespace: "default", formatStr: "bogus", wantErr: `invalid format "bogus", must be one of: json, json-ietf, xml, xpath, yaml`},
+		{name: "invalid format", target: "srl1", namespace: "default", formatStr: "bogus", wantErr: `invalid format "bogus", must be one of: json, json-ietf, xml, xpath, yaml, cli, gnmi-prototext, gnmi-json, gnmic-setfile`},
 		{name: "valid format", target: "srl1", namespace: "default", formatStr: "yaml", want: client.FormatYAML},
+		{name: "path with gnmi format", target: "srl1", namespace: "default", path: "/system", formatStr: "gnmic-setfile", want: runningconfig.FormatGNMISetFile},
+		{name: "cli style", target: "srl1", namespace: "default", cliStyle: "SET", formatStr: "cli", want: runningconfig.FormatCLI},
+		{name: "cli style without cli format", target: "srl1", namespace: "default", cliStyle: "set", formatStr: "xpath", wantErr: "--cli-style requires the format cli"},
+		{name: "invalid cli style", target: "srl1", namespace: "default", cliStyle: "junos", formatStr: "cli", wantErr: `invalid cli style "junos", must be one of: hierarchy, set`},
+		{name: "path without gnmi format", target: "srl1", namespace: "default", path: "/system", formatStr: "json", wantErr: "--path requires one of the formats: gnmi-prototext, gnmi-json, gnmic-setfile"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			o := &RunningConfigOptions{
-				target:    tt.target,
-				formatStr: tt.formatStr,
+				target:      tt.target,
+				path:        tt.path,
+				cliStyleStr: tt.cliStyle,
+				formatStr:   tt.formatStr,
 				GenericOptions: GenericOptions{
 					namespace: tt.namespace,
 				},
@@ -88,3 +100,33 @@ func TestNewCmdRunningConfigRequiresTarget(t *testing.T) {
 		t.Fatalf("ValidateRequiredFlags() error = %v, want %q", err, `required flag(s) "target" not set`)
 	}
 }
+
+func TestRunningConfigCompleteNormalizesPath(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: default
+current-context: test
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(clientcmd.RecommendedConfigPathEnvVar, kubeconfig)
+
+	streams, _, _, _ := genericiooptions.NewTestIOStreams()
+	o := NewRunningConfigOptions(streams)
+	o.path = "/restconf/data/srl_nokia-system:system/name"
+	if err := o.Complete(nil, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if o.path != "/system/name" {
+		t.Fatalf("path = %q, want /system/name", o.path)
+	}
+}
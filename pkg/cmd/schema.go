@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/schema"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type SchemaOptions struct {
+	path       string
+	target     string
+	vendor     string
+	version    string
+	depth      int
+	printFlags *output.PrintFlags
+	printer    printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewSchemaOptions provides an instance of SchemaOptions with default values
+func NewSchemaOptions(streams genericiooptions.IOStreams) *SchemaOptions {
+	return &SchemaOptions{
+		printFlags: output.NewPrintFlags(),
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *SchemaOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.path = args[0]
+	}
+	return nil
+}
+
+// validateOutput sets up the printer of the -o output format
+func (o *SchemaOptions) validateOutput() error {
+	var err error
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
+}
+
+// Validate validates the options selecting the schema to browse
+func (o *SchemaOptions) Validate() error {
+	if err := o.validateOutput(); err != nil {
+		return err
+	}
+	if o.target == "" && (o.vendor == "" || o.version == "") {
+		return fmt.Errorf("either --target or both --vendor and --version must be set")
+	}
+	if o.target != "" && (o.vendor != "" || o.version != "") {
+		return fmt.Errorf("--target cannot be combined with --vendor and --version")
+	}
+	if o.depth < 0 {
+		return fmt.Errorf("--depth must not be negative")
+	}
+	return nil
+}
+
+func (o *SchemaOptions) RunList(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	schemas, err := schema.List(ctx, dataClient)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		objs, err := output.Schemas(schemas)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(objs, o.Out)
+	}
+	if len(schemas) == 0 {
+		_, _ = fmt.Fprintln(o.ErrOut, "No schemas found.")
+		return nil
+	}
+	return schema.WriteList(o.Out, schemas)
+}
+
+func (o *SchemaOptions) RunShow(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	s, dataClient, closeDataClient, err := o.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	node, err := schema.Show(ctx, dataClient, s, o.path)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		obj, err := output.NewObject(output.KindSchemaNode, "", node.Path, node)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(obj, o.Out)
+	}
+	return schema.WriteNode(o.Out, node)
+}
+
+func (o *SchemaOptions) RunTree(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	s, dataClient, closeDataClient, err := o.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	tree, err := schema.Tree(ctx, dataClient, s, o.path, o.depth)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		obj, err := output.NewObject(output.KindSchemaTree, "", tree.Name, tree)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(obj, o.Out)
+	}
+	_, err = fmt.Fprintln(o.Out, tree.Render(render.Default()))
+	return err
+}
+
+// connect resolves the schema to browse and connects to the data-server
+func (o *SchemaOptions) connect(ctx context.Context) (*sdcpb.Schema, *client.DataClient, func(), error) {
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	s, err := schema.ResolveSchema(ctx, cl, o.namespace, o.target, o.vendor, o.version)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s, dataClient, closeDataClient, nil
+}
+
+// NewCmdSchema provides a cobra command grouping the schema subcommands
+func NewCmdSchema(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Browse the YANG schemas loaded in the schema-server",
+	}
+
+	listCmd, err := newCmdSchemaList(streams)
+	if err != nil {
+		return nil, err
+	}
+	showCmd, err := newCmdSchemaShow(streams)
+	if err != nil {
+		return nil, err
+	}
+	treeCmd, err := newCmdSchemaTree(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(listCmd, showCmd, treeCmd)
+
+	return cmd, nil
+}
+
+func newCmdSchemaList(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewSchemaOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List the schemas loaded in the schema-server",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validateOutput(); err != nil {
+				return err
+			}
+			return o.RunList(c)
+		},
+	}
+
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func newCmdSchemaShow(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewSchemaOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "show PATH",
+		Short:        "Show the type, default, description, enums, keys and mandatory state of a schema node",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunShow(c)
+		},
+	}
+
+	if err := addSchemaFlags(cmd, o); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func newCmdSchemaTree(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewSchemaOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "tree [PATH]",
+		Short:        "Expand the children of a schema path as a tree",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunTree(c)
+		},
+	}
+
+	cmd.Flags().IntVar(&o.depth, "depth", 2, "number of levels to expand, 0 expands the whole subtree")
+	if err := addSchemaFlags(cmd, o); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func addSchemaFlags(cmd *cobra.Command, o *SchemaOptions) error {
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return err
+	}
+	cmd.Flags().StringVar(&o.target, "target", "", "target whose schema to use")
+	cmd.Flags().StringVar(&o.vendor, "vendor", "", "schema vendor, used with --version instead of --target")
+	cmd.Flags().StringVar(&o.version, "version", "", "schema version, used with --vendor instead of --target")
+	return cmd.RegisterFlagCompletionFunc("target", targetCompletionFunc(o))
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/target"
+	"github.com/sdcio/kubectl-sdc/pkg/output"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+type TargetOptions struct {
+	name       string
+	format     target.OutputFormat
+	printFlags *output.PrintFlags
+	printer    printers.ResourcePrinter
+	GenericOptions
+}
+
+// NewTargetOptions provides an instance of TargetOptions with default values
+func NewTargetOptions(streams genericiooptions.IOStreams) *TargetOptions {
+	return &TargetOptions{
+		printFlags: output.NewPrintFlags(string(target.OutputFormatWide)),
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *TargetOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.name = args[0]
+	}
+	return nil
+}
+
+func (o *TargetOptions) Validate() error {
+	var err error
+	if format, ok := o.printFlags.Alias(); ok {
+		o.format, err = target.ParseOutputFormat(format)
+		return err
+	}
+	o.printer, err = o.printFlags.ToPrinter()
+	return err
+}
+
+func (o *TargetOptions) RunList(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+
+	summaries, err := target.List(ctx, cl, o.namespace, o.ErrOut)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		objs := make([]*unstructured.Unstructured, 0, len(summaries))
+		for _, s := range summaries {
+			obj, err := targetSummaryObject(s)
+			if err != nil {
+				return err
+			}
+			objs = append(objs, obj)
+		}
+		return o.printer.PrintObj(output.NewList(objs...), o.Out)
+	}
+	if len(summaries) == 0 {
+		_, _ = fmt.Fprintf(o.ErrOut, "No targets found in %s namespace.\n", o.namespace)
+		return nil
+	}
+	return target.WriteList(o.Out, summaries, o.format)
+}
+
+func (o *TargetOptions) RunDescribe(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+
+	summary, err := target.Describe(ctx, cl, o.namespace, o.name)
+	if err != nil {
+		return err
+	}
+	if o.printer != nil {
+		obj, err := targetSummaryObject(summary)
+		if err != nil {
+			return err
+		}
+		return o.printer.PrintObj(obj, o.Out)
+	}
+	return target.WriteDescribe(o.Out, summary, o.format)
+}
+
+// targetSummaryObject returns the TargetSummary object of the output model
+func targetSummaryObject(s *target.Summary) (*unstructured.Unstructured, error) {
+	return output.NewObject(output.KindTargetSummary, s.Namespace, s.Name, s)
+}
+
+// NewCmdTarget provides a cobra command grouping the target subcommands
+func NewCmdTarget(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "target",
+		Short: "Show the status of SDC targets",
+	}
+
+	listCmd, err := newCmdTargetList(streams)
+	if err != nil {
+		return nil, err
+	}
+	describeCmd, err := newCmdTargetDescribe(streams)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(listCmd, describeCmd)
+
+	return cmd, nil
+}
+
+func newCmdTargetList(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewTargetOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List targets with their connection, sync and schema state",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunList(c)
+		},
+	}
+
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
+
+func newCmdTargetDescribe(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewTargetOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:               "describe TARGET",
+		Short:             "Show the details of a target",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: targetCompletionFunc(o),
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.RunDescribe(c)
+		},
+	}
+
+	if err := o.printFlags.AddFlags(cmd); err != nil {
+		return nil, err
+	}
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
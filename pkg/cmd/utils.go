@@ -2,13 +2,111 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	"github.com/sdcio/kubectl-sdc/pkg/commands/blame"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/completion"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/pathconv"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
+const (
+	defaultDataServerNamespace = "sdc-system"
+	defaultDataServerService   = "data-server"
+
+	// pathFlagHelp completes the help of the path flags with the notations they accept
+	pathFlagHelp = "; XPath, gNMI, or RESTCONF paths without list keys"
+
+	// completionTimeout bounds a blame tree or schema based completion, including the data-server port-forward
+	completionTimeout = 10 * time.Second
+)
+
+// dataServer is the location of the data-server service
+type dataServer struct {
+	namespace string
+	service   string
+}
+
+// newDataClient creates a data client for the data-server service, resolving the
+// data-service port from the Kubernetes service. The client is not yet connected.
+func newDataClient(ctx context.Context, restConfig *rest.Config, ds dataServer) (*client.DataClient, error) {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	svc, err := clientset.CoreV1().Services(ds.namespace).Get(ctx, ds.service, metav1.GetOptions{})
+	if err != nil {
+		return nil, &client.ConnectionError{Component: "kubernetes", Namespace: ds.namespace, Reason: "failed to get data-server service", Err: err}
+	}
+
+	port, err := runningconfig.ResolveDataServicePort(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	dataClient, err := client.NewDataClient(restConfig, ds.namespace, ds.service, port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data client: %w", err)
+	}
+	return dataClient, nil
+}
+
+// connectDataClient creates and connects a data client. The returned function closes
+// the client, reporting a failure as a warning on errOut. A client that failed to
+// connect is closed before returning, so no port-forward is left behind.
+func connectDataClient(ctx context.Context, restConfig *rest.Config, ds dataServer, errOut io.Writer) (*client.DataClient, func(), error) {
+	dataClient, err := newDataClient(ctx, restConfig, ds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := dataClient.Connect(ctx); err != nil {
+		_ = dataClient.Close()
+		return nil, nil, fmt.Errorf("failed to connect to data-server: %w", err)
+	}
+	return dataClient, func() {
+		if err := dataClient.Close(); err != nil {
+			_, _ = fmt.Fprintf(errOut, "warning: failed to close data client: %v\n", err)
+		}
+	}, nil
+}
+
+// writeFile creates or truncates the file at path and writes it with write
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// normalizePaths normalizes the paths of a path flag to XPaths. The key names of
+// a RESTCONF path are only known from the schema, such paths are refused.
+func normalizePaths(flag string, paths []string) ([]string, error) {
+	normalized, err := pathconv.NormalizeAll(paths)
+	if errors.Is(err, pathconv.ErrSchemaRequired) {
+		return nil, fmt.Errorf("invalid --%s: %w, convert it with kubectl sdc path convert --target TARGET --to xpath", flag, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flag, err)
+	}
+	return normalized, nil
+}
+
 func compError(err error) ([]string, cobra.ShellCompDirective) {
 	cobra.CompError(err.Error())
 	return nil, cobra.ShellCompDirectiveError
@@ -49,12 +147,7 @@ func deviationCompletionFunc(o *DeviationOptions) func(cmd *cobra.Command, args
 			return compError(err)
 		}
 
-		var selectLabels map[string]string
-		if o.target != "" {
-			selectLabels = map[string]string{client.TargetLabel: o.target}
-		}
-
-		comps, err := cl.ListDeviationNames(context.Background(), o.GetNamespace(), selectLabels)
+		comps, err := cl.ListDeviationNames(context.Background(), o.GetNamespace(), o.targets)
 		if err != nil {
 			return compError(err)
 		}
@@ -74,8 +167,146 @@ func formatCompletionFunc() func(cmd *cobra.Command, args []string, toComplete s
 	}
 }
 
+// pathCompletionFunc is a completion function that completes the next element or list key
+// of a path from the blame tree and the schema of the targets.
+func pathCompletionFunc(o k8sCompletion, targets func() []string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		comps, err := completeForTargets(o, targets(), func(ctx context.Context, c *completion.Completer, target string) ([]string, error) {
+			return c.Paths(ctx, o.GetNamespace(), target, toComplete)
+		})
+		if err != nil {
+			return compError(err)
+		}
+		return comps, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ownerCompletionFunc is a completion function that completes the owners of the leaves
+// in the blame tree of the targets, as well as the intent names of their Config resources.
+func ownerCompletionFunc(o k8sCompletion, targets func() []string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		comps, err := completeForTargets(o, targets(), func(ctx context.Context, c *completion.Completer, target string) ([]string, error) {
+			owners, err := c.Owners(ctx, o.GetNamespace(), target)
+			if err != nil {
+				return nil, err
+			}
+			intents, err := c.Intents(ctx, o.GetNamespace(), target)
+			if err != nil {
+				return nil, err
+			}
+			return append(owners, intents...), nil
+		})
+		if err != nil {
+			return compError(err)
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// leafCompletionFunc is a completion function that completes the leaf names in the
+// blame tree of the targets.
+func leafCompletionFunc(o k8sCompletion, targets func() []string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		comps, err := completeForTargets(o, targets(), func(ctx context.Context, c *completion.Completer, target string) ([]string, error) {
+			return c.Leaves(ctx, o.GetNamespace(), target)
+		})
+		if err != nil {
+			return compError(err)
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeForTargets runs complete for each target and merges the distinct results
+func completeForTargets(o k8sCompletion, targets []string, complete func(context.Context, *completion.Completer, string) ([]string, error)) ([]string, error) {
+	if err := o.Complete(nil, nil); err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("--target must be set for completion")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.RESTConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	dataClient := &lazyDataClient{restConfig: o.RESTConfig(), dataServer: o.dataServer()}
+	defer dataClient.Close()
+
+	// without a cache directory completion still works, just slower
+	dir, _ := completion.DefaultCacheDir()
+	completer := completion.NewCompleter(cl, dataClient, completion.NewCache(dir), o.RESTConfig().Host)
+
+	seen := map[string]struct{}{}
+	var comps []string
+	for _, target := range targets {
+		c, err := complete(ctx, completer, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, comp := range c {
+			if _, ok := seen[comp]; ok {
+				continue
+			}
+			seen[comp] = struct{}{}
+			comps = append(comps, comp)
+		}
+	}
+	return comps, nil
+}
+
+// lazyDataClient connects to the data-server on first use, so that completions
+// answered from the cache do not set up a port-forward.
+type lazyDataClient struct {
+	restConfig *rest.Config
+	dataServer dataServer
+	dataClient *client.DataClient
+	err        error
+}
+
+func (l *lazyDataClient) connect(ctx context.Context) (*client.DataClient, error) {
+	if l.dataClient == nil && l.err == nil {
+		l.dataClient, l.err = newDataClient(ctx, l.restConfig, l.dataServer)
+		if l.err == nil {
+			if l.err = l.dataClient.Connect(ctx); l.err != nil {
+				_ = l.dataClient.Close()
+				l.dataClient = nil
+			}
+		}
+	}
+	return l.dataClient, l.err
+}
+
+func (l *lazyDataClient) ListSchemas(ctx context.Context) ([]*sdcpb.Schema, error) {
+	dataClient, err := l.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataClient.ListSchemas(ctx)
+}
+
+func (l *lazyDataClient) GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	dataClient, err := l.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dataClient.GetSchemaElem(ctx, schema, path)
+}
+
+// Close closes the data client if it was connected
+func (l *lazyDataClient) Close() {
+	if l.dataClient != nil {
+		_ = l.dataClient.Close()
+	}
+}
+
 type k8sCompletion interface {
 	RESTConfig() *rest.Config
+	dataServer() dataServer
 	Complete(*cobra.Command, []string) error
 	GetNamespace() string
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/apply"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/validate"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+)
+
+type ValidateOptions struct {
+	files     []string
+	recursive bool
+	GenericOptions
+}
+
+// NewValidateOptions provides an instance of ValidateOptions with default values
+func NewValidateOptions(streams genericiooptions.IOStreams) *ValidateOptions {
+	return &ValidateOptions{
+		GenericOptions: GenericOptions{
+			configFlags: newConfigFlags(),
+			IOStreams:   streams,
+		},
+	}
+}
+
+func (o *ValidateOptions) Complete(c *cobra.Command, args []string) error {
+	if err := o.complete(c); err != nil {
+		return err
+	}
+
+	// Remaining positional args are treated as file paths (alongside --filename).
+	o.files = append(o.files, args...)
+	return nil
+}
+
+func (o *ValidateOptions) Validate() error {
+	if len(o.files) == 0 {
+		return fmt.Errorf("must provide at least one filename (use -f or pass paths as arguments)")
+	}
+	return nil
+}
+
+func (o *ValidateOptions) Run(c *cobra.Command) error {
+	ctx, cancel := commandContext(c)
+	defer cancel()
+
+	cl, err := client.NewConfigClient(o.restConfig)
+	if err != nil {
+		return err
+	}
+
+	dataClient, closeDataClient, err := connectDataClient(ctx, o.restConfig, o.dataServer(), o.ErrOut)
+	if err != nil {
+		return err
+	}
+	defer closeDataClient()
+
+	return apply.Validate(ctx, validate.NewValidator(cl, dataClient), o.namespace, o.files, o.Out, apply.WithRecursive(o.recursive))
+}
+
+// NewCmdValidate provides a cobra command wrapping ValidateOptions
+func NewCmdValidate(streams genericiooptions.IOStreams) (*cobra.Command, error) {
+	o := NewValidateOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:          "validate",
+		Short:        "Validate Config manifests against the schema of their target",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.Complete(c, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run(c)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&o.files, "filename", "f", nil, "filename, directory, glob pattern, or URL to files to validate")
+	cmd.Flags().BoolVarP(&o.recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd, nil
+}
@@ -6,42 +6,125 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"strings"
 
 	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/validate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // ApplyClient defines the interface for apply operations.
 type ApplyClient interface {
 	ClearTargetDeviations(ctx context.Context, resource *v1alpha1.TargetClearDeviation) error
+	ServerSideApply(ctx context.Context, obj *unstructured.Unstructured, dryRun bool) (client.ApplyResult, error)
+	WatchStatus(ctx context.Context, kind, namespace, name string) (watch.Interface, error)
 }
 
-// Apply reads each file (or stdin when path is "-"), decodes all YAML/JSON
-// documents inside, and applies each one via the appropriate client method.
-func Apply(ctx context.Context, cl ApplyClient, namespace string, filePaths []string, out io.Writer) error {
-	for _, filePath := range filePaths {
-		var data []byte
-		var err error
-		if filePath == "-" {
-			data, err = io.ReadAll(os.Stdin)
-		} else {
-			data, err = os.ReadFile(filePath) // #nosec G304 – user-supplied path is intentional
+// Apply reads each file, directory, glob pattern, URL (or stdin when path is "-"),
+// decodes all YAML/JSON documents inside, and applies each one via the appropriate
+// client method. With the validate option, nothing is applied unless all manifests
+// are valid. With the wait option, the applied Config and ConfigSet resources are
+// watched afterwards until they are ready or failed.
+func Apply(ctx context.Context, cl ApplyClient, namespace string, filePaths []string, out io.Writer, opts ...ApplyOptionSetter) error {
+	ao := NewApplyOptions(opts...)
+
+	manifests, err := readManifests(ctx, filePaths, ao)
+	if err != nil {
+		return err
+	}
+
+	if ao.validator != nil {
+		if err := validateManifests(ctx, ao.validator, namespace, manifests, out); err != nil {
+			return err
 		}
+	}
+
+	var applied []resourceRef
+	for _, m := range manifests {
+		refs, err := applyDocuments(ctx, cl, namespace, m.data, out, ao)
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.source, err)
+		}
+		applied = append(applied, refs...)
+	}
+
+	if !ao.Wait() {
+		return nil
+	}
+	return waitForResources(ctx, cl, applied, out, ao)
+}
+
+// Validate reads the manifests like Apply and validates the Config resources against
+// the schema of their target without applying anything.
+func Validate(ctx context.Context, v *validate.Validator, namespace string, filePaths []string, out io.Writer, opts ...ApplyOptionSetter) error {
+	ao := NewApplyOptions(opts...)
+
+	manifests, err := readManifests(ctx, filePaths, ao)
+	if err != nil {
+		return err
+	}
+
+	if err := validateManifests(ctx, v, namespace, manifests, out); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "%d manifest(s) valid\n", len(manifests))
+	return nil
+}
+
+// manifest is the content of a single input source.
+type manifest struct {
+	source string
+	data   []byte
+}
+
+// readManifests expands and reads all the inputs upfront, so that a failing source
+// does not leave the inputs partially applied.
+func readManifests(ctx context.Context, filePaths []string, ao *ApplyOptions) ([]manifest, error) {
+	var manifests []manifest
+	for _, filePath := range filePaths {
+		sources, err := expandSource(filePath, ao.Recursive())
 		if err != nil {
-			return fmt.Errorf("reading %s: %w", filePath, err)
+			return nil, fmt.Errorf("reading %s: %w", filePath, err)
 		}
 
-		if err := applyDocuments(ctx, cl, namespace, data, out); err != nil {
-			return fmt.Errorf("%s: %w", filePath, err)
+		for _, source := range sources {
+			data, err := readSource(ctx, source, ao.URLTimeout())
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", source, err)
+			}
+			manifests = append(manifests, manifest{source: source, data: data})
 		}
 	}
+	return manifests, nil
+}
+
+// validateManifests writes every validation finding to out and fails if there is any.
+func validateManifests(ctx context.Context, v *validate.Validator, namespace string, manifests []manifest, out io.Writer) error {
+	count := 0
+	for _, m := range manifests {
+		findings, err := v.ValidateDocuments(ctx, namespace, m.source, m.data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.source, err)
+		}
+		for _, f := range findings {
+			_, _ = fmt.Fprintln(out, f.String())
+		}
+		count += len(findings)
+	}
+	if count > 0 {
+		return fmt.Errorf("validation failed with %d error(s)", count)
+	}
 	return nil
 }
 
-// applyDocuments handles multi-document YAML/JSON files.
-func applyDocuments(ctx context.Context, cl ApplyClient, namespace string, data []byte, out io.Writer) error {
+// applyDocuments handles multi-document YAML/JSON files. It returns the applied
+// resources that can be waited for.
+func applyDocuments(ctx context.Context, cl ApplyClient, namespace string, data []byte, out io.Writer, ao *ApplyOptions) ([]resourceRef, error) {
+	var refs []resourceRef
 	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
 	for {
 		var raw json.RawMessage
@@ -49,31 +132,75 @@ func applyDocuments(ctx context.Context, cl ApplyClient, namespace string, data
 			if err == io.EOF {
 				break
 			}
-			return fmt.Errorf("decoding document: %w", err)
+			return nil, fmt.Errorf("decoding document: %w", err)
 		}
 
 		var tm metav1.TypeMeta
 		if err := json.Unmarshal(raw, &tm); err != nil {
-			return fmt.Errorf("reading type metadata: %w", err)
+			return nil, fmt.Errorf("reading type metadata: %w", err)
 		}
 
-		if err := applyResource(ctx, cl, namespace, tm.Kind, raw, out); err != nil {
-			return err
+		ref, err := applyResource(ctx, cl, namespace, tm, raw, out, ao)
+		if err != nil {
+			return nil, err
+		}
+		if ref != nil && waitable(ref.kind) {
+			refs = append(refs, *ref)
 		}
 	}
-	return nil
+	return refs, nil
 }
 
-func applyResource(ctx context.Context, cl ApplyClient, namespace string, kind string, raw json.RawMessage, out io.Writer) error {
-	switch kind {
-	case v1alpha1.TargetClearDeviationKind:
-		return applyTargetClearDeviation(ctx, cl, namespace, raw, out)
-	default:
-		return fmt.Errorf("unsupported kind %q", kind)
+func applyResource(ctx context.Context, cl ApplyClient, namespace string, tm metav1.TypeMeta, raw json.RawMessage, out io.Writer, ao *ApplyOptions) (*resourceRef, error) {
+	if tm.Kind == v1alpha1.TargetClearDeviationKind {
+		return nil, applyTargetClearDeviation(ctx, cl, namespace, raw, out, ao)
+	}
+	if _, ok := client.ApplyResourceName(tm.Kind); ok && tm.GroupVersionKind().Group == v1alpha1.Group {
+		return applyConfigResource(ctx, cl, namespace, raw, out, ao)
 	}
+	return nil, fmt.Errorf("unsupported kind %q", tm.Kind)
 }
 
-func applyTargetClearDeviation(ctx context.Context, cl ApplyClient, namespace string, raw json.RawMessage, out io.Writer) error {
+// applyConfigResource server-side applies a config.sdcio.dev resource such as a Config or ConfigSet.
+func applyConfigResource(ctx context.Context, cl ApplyClient, namespace string, raw json.RawMessage, out io.Writer, ao *ApplyOptions) (*resourceRef, error) {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw); err != nil {
+		return nil, fmt.Errorf("decoding resource: %w", err)
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("%s is missing metadata.name", obj.GetKind())
+	}
+
+	// Prefer namespace from the resource manifest; fall back to the CLI namespace.
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+
+	ref := fmt.Sprintf("%s/%s", strings.ToLower(obj.GetKind()), obj.GetName())
+
+	if ao.Diff() && obj.GetKind() == v1alpha1.ConfigKind {
+		if err := writeIntentDiff(ctx, ao.intentClient, obj, ref, out); err != nil {
+			return nil, fmt.Errorf("%s: diff: %w", ref, err)
+		}
+	}
+
+	result, err := cl.ServerSideApply(ctx, obj, ao.DryRun())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+
+	_, _ = fmt.Fprintf(out, "%s %s%s\n", ref, result, dryRunSuffix(ao))
+	return &resourceRef{kind: obj.GetKind(), namespace: obj.GetNamespace(), name: obj.GetName()}, nil
+}
+
+func dryRunSuffix(ao *ApplyOptions) string {
+	if ao.DryRun() {
+		return " (server dry run)"
+	}
+	return ""
+}
+
+func applyTargetClearDeviation(ctx context.Context, cl ApplyClient, namespace string, raw json.RawMessage, out io.Writer, ao *ApplyOptions) error {
 	var resource v1alpha1.TargetClearDeviation
 	if err := json.Unmarshal(raw, &resource); err != nil {
 		return fmt.Errorf("decoding TargetClearDeviation: %w", err)
@@ -84,6 +211,12 @@ func applyTargetClearDeviation(ctx context.Context, cl ApplyClient, namespace st
 		resource.Namespace = namespace
 	}
 
+	// the cleardeviation subresource is an action without dry run support
+	if ao.DryRun() {
+		_, _ = fmt.Fprintf(out, "targetcleardeviation/%s skipped (dry run not supported)\n", resource.Name)
+		return nil
+	}
+
 	if err := cl.ClearTargetDeviations(ctx, &resource); err != nil {
 		return err
 	}
@@ -11,7 +11,12 @@ import (
 
 	"github.com/sdcio/config-server/apis/config/v1alpha1"
 	mockapply "github.com/sdcio/kubectl-sdc/mocks/apply"
+	mockvalidate "github.com/sdcio/kubectl-sdc/mocks/validate"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/validate"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	"go.uber.org/mock/gomock"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func writeManifest(t *testing.T, content string) string {
@@ -191,3 +196,125 @@ spec:
 		t.Fatalf("expected propagated client error, got %v", err)
 	}
 }
+
+func TestApply_ConfigResourcesServerSideApply(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  priority: 10
+  config:
+    - path: /
+      value:
+        system:
+          name: srl1
+---
+apiVersion: config.sdcio.dev/v1alpha1
+kind: ConfigSet
+metadata:
+  name: intent-b
+  namespace: from-manifest
+spec:
+  target:
+    targetSelector:
+      matchLabels:
+        sdcio.dev/region: us-east
+  config:
+    - path: /
+      value:
+        system:
+          name: srl1
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+
+	var got []*unstructured.Unstructured
+	gomock.InOrder(
+		cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, obj *unstructured.Unstructured, _ bool) (client.ApplyResult, error) {
+			got = append(got, obj)
+			return client.ApplyResultCreated, nil
+		}),
+		cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), false).DoAndReturn(func(_ context.Context, obj *unstructured.Unstructured, _ bool) (client.ApplyResult, error) {
+			got = append(got, obj)
+			return client.ApplyResultUnchanged, nil
+		}),
+	)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "from-cli", []string{writeManifest(t, manifest)}, out)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 applied resources, got %d", len(got))
+	}
+	if got[0].GetKind() != "Config" || got[0].GetNamespace() != "from-cli" {
+		t.Fatalf("unexpected first resource %s in namespace %q", got[0].GetKind(), got[0].GetNamespace())
+	}
+	if got[1].GetKind() != "ConfigSet" || got[1].GetNamespace() != "from-manifest" {
+		t.Fatalf("unexpected second resource %s in namespace %q", got[1].GetKind(), got[1].GetNamespace())
+	}
+	want := "config/intent-a created\nconfigset/intent-b unchanged"
+	if s := strings.TrimSpace(out.String()); s != want {
+		t.Fatalf("unexpected output: %q, want %q", s, want)
+	}
+}
+
+func TestApply_ConfigKindFromOtherGroupUnsupported(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: example.com/v1
+kind: Config
+metadata:
+  name: cfg
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, manifest)}, out)
+	if err == nil || !strings.Contains(err.Error(), `unsupported kind "Config"`) {
+		t.Fatalf("expected unsupported kind error, got %v", err)
+	}
+}
+
+func TestApply_ValidationFailureAppliesNothing(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  config:
+    - path: /system/unknown
+      value: x
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	targets := mockvalidate.NewMockTargetClient(ctrl)
+	targets.EXPECT().GetTargetSchema(gomock.Any(), "default", "srl1").Return(&sdcpb.Schema{Vendor: "nokia", Version: "24.10"}, nil)
+	schemas := mockvalidate.NewMockSchemaClient(ctrl)
+	schemas.EXPECT().GetSchemaElem(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("unknown element"))
+
+	path := writeManifest(t, manifest)
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{path}, out, WithValidator(validate.NewValidator(targets, schemas)))
+	if err == nil || err.Error() != "validation failed with 1 error(s)" {
+		t.Fatalf("Apply() error = %v, want %q", err, "validation failed with 1 error(s)")
+	}
+	if want := path + ":9:13: /system/unknown: unknown path: unknown element"; strings.TrimSpace(out.String()) != want {
+		t.Fatalf("unexpected output: %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,110 @@
+package apply
+
+import (
+	"time"
+
+	"github.com/sdcio/kubectl-sdc/pkg/commands/validate"
+)
+
+const defaultURLTimeout = 30 * time.Second
+
+type ApplyOptions struct {
+	// recursive walks directories given as input recursively
+	recursive bool
+	// urlTimeout bounds the time spent fetching a manifest from a URL
+	urlTimeout time.Duration
+	// dryRun submits the resources as server-side dry run
+	dryRun bool
+	// wait watches the applied Config and ConfigSet resources until they are ready or failed
+	wait bool
+	// timeout bounds the wait, zero waits without limit
+	timeout time.Duration
+	// validator validates the manifests against the target schemas before applying, nil disables it
+	validator *validate.Validator
+	// intentClient is used to show the intent diff of Config resources, nil disables the diff
+	intentClient DiffClient
+}
+
+type ApplyOptionSetter func(a *ApplyOptions)
+
+func NewApplyOptions(opts ...ApplyOptionSetter) *ApplyOptions {
+	ao := &ApplyOptions{
+		urlTimeout: defaultURLTimeout,
+	}
+
+	// apply options
+	for _, o := range opts {
+		o(ao)
+	}
+
+	return ao
+}
+
+// Getters
+func (a *ApplyOptions) Recursive() bool {
+	return a.recursive
+}
+
+func (a *ApplyOptions) URLTimeout() time.Duration {
+	return a.urlTimeout
+}
+
+func (a *ApplyOptions) DryRun() bool {
+	return a.dryRun
+}
+
+func (a *ApplyOptions) Wait() bool {
+	return a.wait
+}
+
+func (a *ApplyOptions) Timeout() time.Duration {
+	return a.timeout
+}
+
+func (a *ApplyOptions) Diff() bool {
+	return a.intentClient != nil
+}
+
+// Option setters
+func WithRecursive(b bool) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		a.recursive = b
+	}
+}
+
+func WithURLTimeout(d time.Duration) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		if d > 0 {
+			a.urlTimeout = d
+		}
+	}
+}
+
+func WithDryRun(b bool) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		a.dryRun = b
+	}
+}
+
+// WithWait waits for the applied resources to become ready, giving up after timeout.
+// A zero timeout waits without limit.
+func WithWait(b bool, timeout time.Duration) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		a.wait = b
+		a.timeout = timeout
+	}
+}
+
+// WithValidator validates all the manifests before anything is applied.
+func WithValidator(v *validate.Validator) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		a.validator = v
+	}
+}
+
+// WithDiff shows the intent change of each Config, fetching the current intent and the schema via cl.
+func WithDiff(cl DiffClient) ApplyOptionSetter {
+	return func(a *ApplyOptions) {
+		a.intentClient = cl
+	}
+}
@@ -0,0 +1,94 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/pathconv"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// DiffClient fetches the current intent and the schema elements needed to key the
+// lists of the desired config.
+type DiffClient interface {
+	client.IntentClient
+	pathconv.SchemaClient
+	GetDataStore(ctx context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error)
+}
+
+// writeIntentDiff writes the leaves the Config would add, change or remove on its target,
+// compared to the intent currently known by the data-server.
+func writeIntentDiff(ctx context.Context, cl DiffClient, obj *unstructured.Unstructured, ref string, out io.Writer) error {
+	target := obj.GetLabels()[client.TargetLabel]
+	if target == "" {
+		return fmt.Errorf("missing the target label %q", client.TargetLabel)
+	}
+
+	var config v1alpha1.Config
+	if err := runtimeFromUnstructured(obj, &config); err != nil {
+		return err
+	}
+
+	datastoreName := client.DatastoreName(obj.GetNamespace(), target)
+	intentName := client.IntentName(obj.GetNamespace(), obj.GetName())
+
+	current := types.Leaves{}
+	keys := types.ListKeys{}
+	intent, err := cl.GetIntent(ctx, client.FormatXPath, datastoreName, intentName)
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// new intent, everything is added
+	case err != nil:
+		return err
+	default:
+		current = types.LeavesFromIntent(intent.GetProto())
+		keys = types.ListKeysFromIntent(intent.GetProto())
+	}
+
+	desired, err := types.LeavesFromConfigBlobs(config.Spec.Config, keys, schemaListKeys(ctx, cl, datastoreName))
+	if err != nil {
+		return err
+	}
+
+	changes := types.DiffLeaves(current, desired)
+	_, _ = fmt.Fprintf(out, "%s: intent %s on %s, %d change(s)\n", ref, intentName, datastoreName, len(changes))
+	r := render.Default()
+	for _, c := range changes {
+		_, _ = fmt.Fprintln(out, r.LeafChange(c))
+	}
+	return nil
+}
+
+// schemaListKeys resolves list keys from the schema of the datastore, which is
+// only fetched once a list unknown to the current intent shows up
+func schemaListKeys(ctx context.Context, cl DiffClient, datastoreName string) types.ListKeyResolver {
+	var resolver *pathconv.SchemaResolver
+	return func(listPath *sdcpb.Path) ([]string, error) {
+		if resolver == nil {
+			ds, err := cl.GetDataStore(ctx, datastoreName)
+			if err != nil {
+				return nil, err
+			}
+			resolver = &pathconv.SchemaResolver{Client: cl, Schema: ds.GetSchema()}
+		}
+		_, keys, err := resolver.Resolve(ctx, pathconv.FromSDCPB(listPath))
+		return keys, err
+	}
+}
+
+func runtimeFromUnstructured(obj *unstructured.Unstructured, into any) error {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
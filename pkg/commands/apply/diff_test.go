@@ -0,0 +1,201 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	mockapply "github.com/sdcio/kubectl-sdc/mocks/apply"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/client/clienttest"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stubDiffClient serves the intents and the keys of the lists of the schema
+type stubDiffClient struct {
+	clienttest.IntentClient
+	// listKeys maps the key-less XPath of the lists known to the schema to their keys
+	listKeys map[string][]string
+}
+
+func (s *stubDiffClient) GetDataStore(_ context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error) {
+	return &sdcpb.GetDataStoreResponse{DatastoreName: datastoreName, Schema: &sdcpb.Schema{Name: "srl"}}, nil
+}
+
+func (s *stubDiffClient) GetSchemaElem(_ context.Context, _ *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	names, ok := s.listKeys[path.ToXPath(false)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown schema element")
+	}
+	keys := make([]*sdcpb.LeafSchema, 0, len(names))
+	for _, n := range names {
+		keys = append(keys, &sdcpb.LeafSchema{Name: n})
+	}
+	return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{Name: path.GetElem()[len(path.GetElem())-1].GetName(), Keys: keys}}}, nil
+}
+
+const diffManifest = `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  priority: 10
+  config:
+    - path: /
+      value:
+        system:
+          name:
+            host-name: srl1-new
+        interface:
+          - name: ethernet-1/1
+            admin-state: enable
+`
+
+func TestApply_DiffAndServerDryRun(t *testing.T) {
+	color.NoColor = true
+
+	// the intent is only found under the datastore and intent name of the Config
+	intents := &stubDiffClient{
+		IntentClient: clienttest.IntentClient{"default.srl1": {"default.intent-a": {Update: []*sdcpb.Update{
+			clienttest.NewUpdate(t, "/system/name/host-name", "srl1"),
+			clienttest.NewUpdate(t, "/system/information/location", "lab"),
+		}}}},
+		listKeys: map[string][]string{"/interface": {"name"}},
+	}
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), true).Return(client.ApplyResultConfigured, nil)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, diffManifest)}, out, WithDryRun(true), WithDiff(intents))
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"config/intent-a: intent default.intent-a on default.srl1, 4 change(s)",
+		"+ /interface[name=ethernet-1/1]/admin-state: enable",
+		"+ /interface[name=ethernet-1/1]/name: ethernet-1/1",
+		"- /system/information/location: lab",
+		"~ /system/name/host-name: srl1 -> srl1-new",
+		"config/intent-a configured (server dry run)",
+	}, "\n")
+	if s := strings.TrimSpace(out.String()); s != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", s, want)
+	}
+}
+
+func TestApply_DiffNewIntent(t *testing.T) {
+	color.NoColor = true
+
+	intents := &stubDiffClient{listKeys: map[string][]string{"/interface": {"name"}}}
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), false).Return(client.ApplyResultCreated, nil)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, diffManifest)}, out, WithDiff(intents))
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "3 change(s)") || !strings.Contains(out.String(), "+ /system/name/host-name: srl1-new") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestApply_DiffSchemaListKeys(t *testing.T) {
+	color.NoColor = true
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  priority: 10
+  config:
+    - path: /interface[name=ethernet-1/1]
+      value:
+        subinterface:
+          - index: 0
+            description: uplink
+        ipv4-route:
+          - prefix: 10.0.0.0/8
+            next-hop: 192.0.2.1
+            metric: 5
+`
+	intents := &stubDiffClient{
+		listKeys: map[string][]string{
+			"/interface/subinterface": {"index"},
+			"/interface/ipv4-route":   {"prefix", "next-hop"},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), true).Return(client.ApplyResultCreated, nil)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, manifest)}, out, WithDryRun(true), WithDiff(intents))
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	for _, want := range []string{
+		"+ /interface[name=ethernet-1/1]/subinterface[index=0]/description: uplink",
+		"+ /interface[name=ethernet-1/1]/ipv4-route[next-hop=192.0.2.1][prefix=10.0.0.0/8]/metric: 5",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestApply_DiffUnresolvedListKeys(t *testing.T) {
+	intents := &stubDiffClient{}
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, diffManifest)}, out, WithDryRun(true), WithDiff(intents))
+	if err == nil || !strings.Contains(err.Error(), "/interface: failed to resolve the list keys") {
+		t.Fatalf("Apply error = %v, want an unresolved list keys error", err)
+	}
+}
+
+func TestApply_DryRunSkipsTargetClearDeviation(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: TargetClearDeviation
+metadata:
+  name: srl1
+spec:
+  config:
+    - name: intent-a
+      paths:
+        - /system/name
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, manifest)}, out, WithDryRun(true))
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if s := strings.TrimSpace(out.String()); s != "targetcleardeviation/srl1 skipped (dry run not supported)" {
+		t.Fatalf("unexpected output: %q", s)
+	}
+}
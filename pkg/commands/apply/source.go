@@ -0,0 +1,129 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestExtensions are the file extensions picked up when walking a directory
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// expandSource resolves a single --filename value into the list of sources to read.
+// URLs and "-" are passed through, glob patterns are expanded and directories are
+// walked for manifest files (recursively if requested).
+func expandSource(source string, recursive bool) ([]string, error) {
+	if source == "-" || isURL(source) {
+		return []string{source}, nil
+	}
+
+	if strings.ContainsAny(source, "*?[") {
+		matches, err := filepath.Glob(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", source, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", source)
+		}
+		var result []string
+		for _, m := range matches {
+			expanded, err := expandPath(m, recursive)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, expanded...)
+		}
+		return result, nil
+	}
+
+	return expandPath(source, recursive)
+}
+
+// expandPath returns the path itself for files or the manifest files within a directory
+func expandPath(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var result []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if hasManifestExtension(p) {
+			result = append(result, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no manifest files (%s) found in directory %s", strings.Join(manifestExtensions, ", "), path)
+	}
+	return result, nil
+}
+
+func hasManifestExtension(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range manifestExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// readSource reads the content of a file, URL or stdin ("-")
+func readSource(ctx context.Context, source string, urlTimeout time.Duration) ([]byte, error) {
+	switch {
+	case source == "-":
+		return io.ReadAll(os.Stdin)
+	case isURL(source):
+		return readURL(ctx, source, urlTimeout)
+	default:
+		return os.ReadFile(source) // #nosec G304 – user-supplied path is intentional
+	}
+}
+
+// readURL fetches a manifest over http(s), bounded by the given timeout
+func readURL(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mockapply "github.com/sdcio/kubectl-sdc/mocks/apply"
+	"go.uber.org/mock/gomock"
+)
+
+const clearDeviationManifest = `apiVersion: config.sdcio.dev/v1alpha1
+kind: TargetClearDeviation
+metadata:
+  name: srl1
+spec:
+  config:
+    - name: intent-a
+      paths:
+        - /system/name
+`
+
+// writeTree creates the given relative files below a fresh temp dir and returns the dir.
+func writeTree(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(clearDeviationManifest), 0o600); err != nil {
+			t.Fatalf("write %s: %v", f, err)
+		}
+	}
+	return dir
+}
+
+func relPaths(t *testing.T, dir string, paths []string) []string {
+	t.Helper()
+	result := make([]string, 0, len(paths))
+	for _, p := range paths {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result
+}
+
+func TestExpandSource(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTree(t, "a.yaml", "b.json", "c.yml", "notes.txt", "sub/d.yaml", "sub/deeper/e.yaml")
+
+	tests := []struct {
+		name      string
+		source    string
+		recursive bool
+		want      []string
+		wantErr   string
+	}{
+		{name: "directory", source: dir, want: []string{"a.yaml", "b.json", "c.yml"}},
+		{name: "directory recursive", source: dir, recursive: true, want: []string{"a.yaml", "b.json", "c.yml", "sub/d.yaml", "sub/deeper/e.yaml"}},
+		{name: "file with any extension", source: filepath.Join(dir, "notes.txt"), want: []string{"notes.txt"}},
+		{name: "glob", source: filepath.Join(dir, "*.y*ml"), want: []string{"a.yaml", "c.yml"}},
+		{name: "glob matching directory", source: filepath.Join(dir, "su*"), want: []string{"sub/d.yaml"}},
+		{name: "glob without match", source: filepath.Join(dir, "*.xml"), wantErr: "no files match"},
+		{name: "missing file", source: filepath.Join(dir, "missing.yaml"), wantErr: "no such file or directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandSource(tt.source, tt.recursive)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandSource() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandSource() unexpected error: %v", err)
+			}
+			if rel := relPaths(t, dir, got); strings.Join(rel, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("expandSource() = %v, want %v", rel, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandSource_EmptyDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTree(t, "notes.txt")
+	if _, err := expandSource(dir, true); err == nil || !strings.Contains(err.Error(), "no manifest files") {
+		t.Fatalf("expandSource() error = %v, want no manifest files error", err)
+	}
+}
+
+func TestReadSource_URL(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/manifest.yaml":
+			_, _ = w.Write([]byte(clearDeviationManifest))
+		case "/slow.yaml":
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(clearDeviationManifest))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	data, err := readSource(context.Background(), srv.URL+"/manifest.yaml", time.Second)
+	if err != nil {
+		t.Fatalf("readSource() unexpected error: %v", err)
+	}
+	if string(data) != clearDeviationManifest {
+		t.Fatalf("readSource() = %q, want manifest", data)
+	}
+
+	if _, err := readSource(context.Background(), srv.URL+"/missing.yaml", time.Second); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("readSource() error = %v, want 404 status error", err)
+	}
+
+	if _, err := readSource(context.Background(), srv.URL+"/slow.yaml", 10*time.Millisecond); err == nil {
+		t.Fatal("readSource() expected timeout error, got nil")
+	}
+}
+
+func TestApply_DirectoryAndURL(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(clearDeviationManifest))
+	}))
+	defer srv.Close()
+
+	dir := writeTree(t, "a.yaml", "sub/b.yaml")
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ClearTargetDeviations(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{dir, srv.URL}, out, WithRecursive(true))
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if got := strings.Count(out.String(), "applied"); got != 3 {
+		t.Fatalf("applied count = %d, want 3", got)
+	}
+}
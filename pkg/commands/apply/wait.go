@@ -0,0 +1,281 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	condv1alpha1 "github.com/sdcio/config-server/apis/condition/v1alpha1"
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// resourceRef identifies an applied resource to wait for.
+type resourceRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (r resourceRef) String() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(r.kind), r.name)
+}
+
+// waitable reports whether the kind exposes a Ready condition that can be waited for.
+func waitable(kind string) bool {
+	return kind == v1alpha1.ConfigKind || kind == v1alpha1.ConfigSetKind
+}
+
+type resourceState string
+
+const (
+	statePending  resourceState = "pending"
+	stateReady    resourceState = "ready"
+	stateFailed   resourceState = "failed"
+	stateTimedOut resourceState = "timed out"
+)
+
+func (s resourceState) done() bool {
+	return s != statePending
+}
+
+// resourceStatus is the wait state of a resource derived from its conditions.
+type resourceStatus struct {
+	ref     resourceRef
+	state   resourceState
+	reason  string
+	message string
+}
+
+func (s resourceStatus) String() string {
+	var b strings.Builder
+	b.WriteString(s.ref.String())
+	b.WriteString(": ")
+	b.WriteString(string(s.state))
+	if s.reason != "" {
+		b.WriteString(" (")
+		b.WriteString(s.reason)
+		b.WriteString(")")
+	}
+	if s.message != "" {
+		b.WriteString(": ")
+		b.WriteString(s.message)
+	}
+	return b.String()
+}
+
+// configStatus derives the status of a Config. The overall Ready condition tells
+// whether the config is applied; the ConfigReady condition carries the reason and
+// the data-server validation error when the transaction failed.
+func configStatus(ref resourceRef, cfg *v1alpha1.Config) resourceStatus {
+	ready := cfg.GetCondition(condv1alpha1.ConditionTypeReady)
+	if stale(ready, cfg.GetGeneration()) {
+		return resourceStatus{ref: ref, state: statePending, reason: "waiting for the new generation"}
+	}
+	if ready.IsTrue() {
+		return resourceStatus{ref: ref, state: stateReady}
+	}
+	if ready.Reason == string(condv1alpha1.ConditionReasonUnrecoverable) {
+		return resourceStatus{ref: ref, state: stateFailed, reason: ready.Reason, message: unrecoverableMessage(ready.Message)}
+	}
+
+	applied := cfg.GetCondition(v1alpha1.ConditionTypeConfigReady)
+	if applied.Reason == string(condv1alpha1.ConditionReasonFailed) {
+		return resourceStatus{ref: ref, state: stateFailed, reason: applied.Reason, message: applied.Message}
+	}
+	if applied.Reason != "" {
+		return resourceStatus{ref: ref, state: statePending, reason: applied.Reason, message: applied.Message}
+	}
+	return resourceStatus{ref: ref, state: statePending, reason: ready.Reason, message: ready.Message}
+}
+
+// configSetStatus derives the status of a ConfigSet, failing as soon as the config
+// on one of its targets failed.
+func configSetStatus(ref resourceRef, cs *v1alpha1.ConfigSet) resourceStatus {
+	ready := cs.GetCondition(condv1alpha1.ConditionTypeReady)
+	if stale(ready, cs.GetGeneration()) {
+		return resourceStatus{ref: ref, state: statePending, reason: "waiting for the new generation"}
+	}
+	if ready.IsTrue() {
+		return resourceStatus{ref: ref, state: stateReady}
+	}
+
+	failed := []string{}
+	for _, t := range cs.Status.Targets {
+		if t.Reason == string(condv1alpha1.ConditionReasonFailed) {
+			failed = append(failed, fmt.Sprintf("%s: %s", t.Name, t.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return resourceStatus{ref: ref, state: stateFailed, reason: ready.Reason, message: strings.Join(failed, "; ")}
+	}
+	if ready.Reason == string(condv1alpha1.ConditionReasonUnrecoverable) {
+		return resourceStatus{ref: ref, state: stateFailed, reason: ready.Reason, message: unrecoverableMessage(ready.Message)}
+	}
+	return resourceStatus{ref: ref, state: statePending, reason: ready.Reason, message: ready.Message}
+}
+
+// stale reports whether the condition was set for an older generation of the resource.
+func stale(c condv1alpha1.Condition, generation int64) bool {
+	return c.ObservedGeneration != 0 && c.ObservedGeneration < generation
+}
+
+func unrecoverableMessage(msg string) string {
+	m := condv1alpha1.UnrecoverableMessage{}
+	if err := json.Unmarshal([]byte(msg), &m); err != nil {
+		return msg
+	}
+	return m.Message
+}
+
+// waitForResources watches the resources until all of them are ready or failed,
+// rendering a live status list to out. An error listing every failed or timed out
+// resource is returned.
+func waitForResources(ctx context.Context, cl ApplyClient, refs []resourceRef, out io.Writer, ao *ApplyOptions) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	if ao.Timeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ao.Timeout())
+		defer cancel()
+	}
+
+	list := newStatusList(out, refs)
+	updates := make(chan resourceStatus)
+	for _, ref := range refs {
+		go watchResource(ctx, cl, ref, updates)
+	}
+
+	remaining := len(refs)
+	for remaining > 0 {
+		s := <-updates
+		list.update(s)
+		if s.state.done() {
+			remaining--
+		}
+	}
+
+	var errs []error
+	for _, s := range list.statuses {
+		if s.state != stateReady {
+			errs = append(errs, errors.New(s.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// watchResource sends the status of the resource on every change until it is done.
+// The watch is re-established when the server closes it.
+func watchResource(ctx context.Context, cl ApplyClient, ref resourceRef, updates chan<- resourceStatus) {
+	last := resourceStatus{ref: ref, state: statePending}
+	for {
+		s, err := watchUntilDone(ctx, cl, ref, last, updates)
+		if err != nil {
+			last = resourceStatus{ref: ref, state: stateFailed, message: err.Error()}
+			break
+		}
+		last = s
+		if s.state.done() {
+			break
+		}
+		if ctx.Err() != nil {
+			last.state = stateTimedOut
+			break
+		}
+	}
+	updates <- last
+}
+
+func watchUntilDone(ctx context.Context, cl ApplyClient, ref resourceRef, last resourceStatus, updates chan<- resourceStatus) (resourceStatus, error) {
+	w, err := cl.WatchStatus(ctx, ref.kind, ref.namespace, ref.name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return last, nil
+		}
+		return last, err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return last, nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return last, nil
+			}
+			var s resourceStatus
+			switch obj := ev.Object.(type) {
+			case *v1alpha1.Config:
+				s = configStatus(ref, obj)
+			case *v1alpha1.ConfigSet:
+				s = configSetStatus(ref, obj)
+			default:
+				continue
+			}
+			if ev.Type == watch.Deleted {
+				s = resourceStatus{ref: ref, state: stateFailed, reason: "deleted"}
+			}
+			if s.state.done() {
+				return s, nil
+			}
+			if s != last {
+				updates <- s
+				last = s
+			}
+		}
+	}
+}
+
+// statusList renders the status of the waited resources. On a terminal the list is
+// redrawn in place, otherwise every change is written as a new line.
+type statusList struct {
+	out      io.Writer
+	tty      bool
+	drawn    bool
+	statuses []resourceStatus
+}
+
+func newStatusList(out io.Writer, refs []resourceRef) *statusList {
+	l := &statusList{out: out, statuses: make([]resourceStatus, 0, len(refs))}
+	if f, ok := out.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		l.tty = true
+	}
+	for _, ref := range refs {
+		l.statuses = append(l.statuses, resourceStatus{ref: ref, state: statePending})
+	}
+	if l.tty {
+		l.redraw()
+	}
+	return l
+}
+
+func (l *statusList) update(s resourceStatus) {
+	for i := range l.statuses {
+		if l.statuses[i].ref == s.ref {
+			l.statuses[i] = s
+		}
+	}
+	if l.tty {
+		l.redraw()
+		return
+	}
+	_, _ = fmt.Fprintln(l.out, s.String())
+}
+
+func (l *statusList) redraw() {
+	if l.drawn {
+		// move the cursor back to the first line of the list
+		_, _ = fmt.Fprintf(l.out, "\033[%dA", len(l.statuses))
+	}
+	for _, s := range l.statuses {
+		_, _ = fmt.Fprintf(l.out, "\033[2K%s\n", s.String())
+	}
+	l.drawn = true
+}
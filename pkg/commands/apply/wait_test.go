@@ -0,0 +1,186 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	condv1alpha1 "github.com/sdcio/config-server/apis/condition/v1alpha1"
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	mockapply "github.com/sdcio/kubectl-sdc/mocks/apply"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func newTestConfig(conditions ...condv1alpha1.Condition) *v1alpha1.Config {
+	cfg := &v1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: "intent-a", Generation: 1}}
+	cfg.SetConditions(conditions...)
+	return cfg
+}
+
+func TestConfigStatus(t *testing.T) {
+	t.Parallel()
+
+	ref := resourceRef{kind: v1alpha1.ConfigKind, name: "intent-a"}
+	tests := []struct {
+		name    string
+		cfg     *v1alpha1.Config
+		want    resourceState
+		message string
+	}{
+		{
+			name: "no status yet",
+			cfg:  newTestConfig(),
+			want: statePending,
+		},
+		{
+			name:    "transaction ongoing",
+			cfg:     newTestConfig(v1alpha1.Creating(), condv1alpha1.Failed("creating")),
+			want:    statePending,
+			message: "creating",
+		},
+		{
+			name: "ready",
+			cfg:  newTestConfig(v1alpha1.ConfigReady(""), v1alpha1.TargetForConfigReady(""), condv1alpha1.ReadyWithMsg("")),
+			want: stateReady,
+		},
+		{
+			name:    "validation failed",
+			cfg:     newTestConfig(v1alpha1.ConfigFailed("mandatory leaf /system/name missing"), condv1alpha1.Failed("mandatory leaf /system/name missing")),
+			want:    stateFailed,
+			message: "mandatory leaf /system/name missing",
+		},
+		{
+			name:    "unrecoverable",
+			cfg:     newTestConfig(condv1alpha1.Condition{Condition: metav1.Condition{Type: string(condv1alpha1.ConditionTypeReady), Status: metav1.ConditionFalse, Reason: string(condv1alpha1.ConditionReasonUnrecoverable), Message: `{"resourceVersion":"1","message":"schema not found"}`}}),
+			want:    stateFailed,
+			message: "schema not found",
+		},
+		{
+			name: "ready for an older generation",
+			cfg: func() *v1alpha1.Config {
+				c := newTestConfig(condv1alpha1.Condition{Condition: metav1.Condition{Type: string(condv1alpha1.ConditionTypeReady), Status: metav1.ConditionTrue, ObservedGeneration: 1}})
+				c.Generation = 2
+				return c
+			}(),
+			want: statePending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := configStatus(ref, tt.cfg)
+			if got.state != tt.want || got.message != tt.message {
+				t.Fatalf("configStatus() = %s, want state %s with message %q", got, tt.want, tt.message)
+			}
+		})
+	}
+}
+
+func TestConfigSetStatus_TargetFailure(t *testing.T) {
+	t.Parallel()
+
+	cs := &v1alpha1.ConfigSet{}
+	cs.SetConditions(condv1alpha1.Failed("not all targets ready"))
+	cs.Status.Targets = []v1alpha1.ConfigSetTargetStatus{
+		{Name: "srl1", Condition: condv1alpha1.Ready()},
+		{Name: "srl2", Condition: condv1alpha1.Failed("invalid value")},
+	}
+
+	got := configSetStatus(resourceRef{kind: v1alpha1.ConfigSetKind, name: "set"}, cs)
+	if got.state != stateFailed || got.message != "srl2: invalid value" {
+		t.Fatalf("configSetStatus() = %s", got)
+	}
+}
+
+func TestApply_WaitReportsProgressAndFailure(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  config:
+    - path: /
+      value:
+        system:
+          name: srl1
+---
+apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-b
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  config:
+    - path: /
+      value:
+        system:
+          name: srl1
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), false).Return(client.ApplyResultCreated, nil).Times(2)
+
+	watchA := watch.NewFakeWithChanSize(2, false)
+	watchA.Add(newTestConfig(v1alpha1.Creating()))
+	watchA.Modify(newTestConfig(v1alpha1.ConfigReady(""), v1alpha1.TargetForConfigReady(""), condv1alpha1.ReadyWithMsg("")))
+	watchB := watch.NewFakeWithChanSize(1, false)
+	watchB.Add(newTestConfig(v1alpha1.ConfigFailed("leaf /system/name: invalid")))
+
+	cl.EXPECT().WatchStatus(gomock.Any(), v1alpha1.ConfigKind, "default", "intent-a").Return(watchA, nil)
+	cl.EXPECT().WatchStatus(gomock.Any(), v1alpha1.ConfigKind, "default", "intent-b").Return(watchB, nil)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, manifest)}, out, WithWait(true, time.Minute))
+	if err == nil {
+		t.Fatal("expected error for failed config, got nil")
+	}
+	if want := "config/intent-b: failed (Failed): leaf /system/name: invalid"; err.Error() != want {
+		t.Fatalf("Apply() error = %v, want %q", err, want)
+	}
+	for _, want := range []string{
+		"config/intent-a: pending (creating): creating",
+		"config/intent-a: ready",
+		"config/intent-b: failed (Failed): leaf /system/name: invalid",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestApply_WaitTimeout(t *testing.T) {
+	t.Parallel()
+
+	manifest := `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+spec:
+  config: []
+`
+
+	ctrl := gomock.NewController(t)
+	cl := mockapply.NewMockApplyClient(ctrl)
+	cl.EXPECT().ServerSideApply(gomock.Any(), gomock.Any(), false).Return(client.ApplyResultUnchanged, nil)
+	w := watch.NewFakeWithChanSize(1, false)
+	w.Add(newTestConfig(v1alpha1.Updating()))
+	cl.EXPECT().WatchStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(w, nil)
+
+	out := &bytes.Buffer{}
+	err := Apply(context.Background(), cl, "default", []string{writeManifest(t, manifest)}, out, WithWait(true, 50*time.Millisecond))
+	if err == nil || !strings.Contains(err.Error(), "config/intent-a: timed out (updating)") {
+		t.Fatalf("Apply() error = %v, want timeout", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package compare diffs the running configuration of two targets, e.g. a new
+// device and the twin it should match. The values that differ by design between
+// devices, like host names, system IPs and MACs, are normalized with rules.
+package compare
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Rule replaces the matches of a regular expression in the paths and values
+type Rule struct {
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement"`
+	re          *regexp.Regexp
+}
+
+// ruleSeparator separates the regex from the replacement of a rule, the = of
+// the list keys in paths rule out a plain =
+const ruleSeparator = "=>"
+
+// ParseRule parses a rule written as REGEX=>REPLACEMENT
+func ParseRule(s string) (Rule, error) {
+	regex, replacement, ok := strings.Cut(s, ruleSeparator)
+	if !ok {
+		return Rule{}, fmt.Errorf("invalid rule %q, must be REGEX%sREPLACEMENT", s, ruleSeparator)
+	}
+	r := Rule{Regex: regex, Replacement: replacement}
+	return r, r.compile()
+}
+
+func (r *Rule) compile() error {
+	if r.Regex == "" {
+		return fmt.Errorf("invalid rule, the regex is empty")
+	}
+	var err error
+	if r.re, err = regexp.Compile(r.Regex); err != nil {
+		return fmt.Errorf("invalid rule regex %q: %w", r.Regex, err)
+	}
+	return nil
+}
+
+func (r Rule) apply(s string) string {
+	return r.re.ReplaceAllString(s, r.Replacement)
+}
+
+// Rules is the ignore list of a comparison
+type Rules struct {
+	// IgnorePaths are the path prefixes of the leaves left out
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
+	// Replace holds the replacements applied in order to the paths and values
+	Replace []Rule `json:"replace,omitempty"`
+}
+
+// LoadRules reads the rules from a YAML or JSON file
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	rules := Rules{}
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("failed to read the rules %s: %w", path, err)
+	}
+	for i := range rules.Replace {
+		if err := rules.Replace[i].compile(); err != nil {
+			return Rules{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return rules, nil
+}
+
+// Merge returns the rules followed by those of other
+func (r Rules) Merge(other Rules) Rules {
+	return Rules{
+		IgnorePaths: append(append([]string{}, r.IgnorePaths...), other.IgnorePaths...),
+		Replace:     append(append([]Rule{}, r.Replace...), other.Replace...),
+	}
+}
+
+// Normalize returns the leaves below the path prefixes, all leaves without
+// prefixes, without the ignored paths and with the replacements applied
+func (r Rules) Normalize(leaves types.Leaves, paths []string) types.Leaves {
+	leaves = leaves.FilterPathPrefixes(paths).ExcludePathPrefixes(r.IgnorePaths)
+	normalized := make(types.Leaves, len(leaves))
+	for p, v := range leaves {
+		for _, rule := range r.Replace {
+			p, v = rule.apply(p), rule.apply(v)
+		}
+		normalized[p] = v
+	}
+	return normalized
+}
+
+// Result is the comparison of the running config of two targets
+type Result struct {
+	From, To string
+	// Compared is the number of normalized leaves of both targets
+	Compared int
+	Changes  []types.LeafChange
+}
+
+// Compare diffs the normalized running config of target from to the one of to
+func Compare(ctx context.Context, cl client.IntentClient, namespace, from, to string, paths []string, rules Rules) (*Result, error) {
+	fromLeaves, err := runningLeaves(ctx, cl, namespace, from)
+	if err != nil {
+		return nil, err
+	}
+	toLeaves, err := runningLeaves(ctx, cl, namespace, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromLeaves, toLeaves = rules.Normalize(fromLeaves, paths), rules.Normalize(toLeaves, paths)
+	compared := len(fromLeaves)
+	for p := range toLeaves {
+		if _, ok := fromLeaves[p]; !ok {
+			compared++
+		}
+	}
+	return &Result{From: from, To: to, Compared: compared, Changes: types.DiffLeaves(fromLeaves, toLeaves)}, nil
+}
+
+func runningLeaves(ctx context.Context, cl client.IntentClient, namespace, target string) (types.Leaves, error) {
+	intent, err := cl.GetIntent(ctx, client.FormatXPath, client.DatastoreName(namespace, target), runningconfig.RunningIntentName)
+	if err != nil {
+		return nil, err
+	}
+	return types.LeavesFromIntent(intent.GetProto()), nil
+}
+
+// WriteResult writes the leaves only on From (-), only on To (+) and with
+// different values (~), the way apply --diff does
+func WriteResult(out io.Writer, res *Result, r render.Renderer) {
+	if len(res.Changes) == 0 {
+		_, _ = fmt.Fprintf(out, "%s and %s match, %d leaves compared\n", res.From, res.To, res.Compared)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "%s -> %s: %d difference(s) in %d leaves compared\n", res.From, res.To, len(res.Changes), res.Compared)
+	for _, c := range res.Changes {
+		_, _ = fmt.Fprintln(out, r.LeafChange(c))
+	}
+}
@@ -0,0 +1,144 @@
+package compare
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client/clienttest"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func testClient(t *testing.T) clienttest.IntentClient {
+	return clienttest.IntentClient{
+		"default.leaf1": {"running": {Update: []*sdcpb.Update{
+			clienttest.NewUpdate(t, "/system/name/host-name", "leaf1"),
+			clienttest.NewUpdate(t, "/interface[name=system0]/subinterface[index=0]/ipv4/address[ip-prefix=10.0.0.1/32]/primary", "true"),
+			clienttest.NewUpdate(t, "/interface[name=mgmt0]/ethernet/mac-address", "1A:2B:3C:4D:5E:01"),
+			clienttest.NewUpdate(t, "/interface[name=ethernet-1/1]/mtu", "9232"),
+			clienttest.NewUpdate(t, "/system/ntp/server[address=10.1.1.1]/admin-state", "enable"),
+		}}},
+		"default.leaf2": {"running": {Update: []*sdcpb.Update{
+			clienttest.NewUpdate(t, "/system/name/host-name", "leaf2"),
+			clienttest.NewUpdate(t, "/interface[name=system0]/subinterface[index=0]/ipv4/address[ip-prefix=10.0.0.2/32]/primary", "true"),
+			clienttest.NewUpdate(t, "/interface[name=mgmt0]/ethernet/mac-address", "1A:2B:3C:4D:5E:02"),
+			clienttest.NewUpdate(t, "/interface[name=ethernet-1/1]/mtu", "1500"),
+			clienttest.NewUpdate(t, "/system/ntp/server[address=10.1.1.2]/admin-state", "enable"),
+		}}},
+	}
+}
+
+func mustParseRule(t *testing.T, s string) Rule {
+	t.Helper()
+	r, err := ParseRule(s)
+	if err != nil {
+		t.Fatalf("ParseRule(%q) unexpected error: %v", s, err)
+	}
+	return r
+}
+
+func TestCompare(t *testing.T) {
+	rules := Rules{
+		IgnorePaths: []string{"/system/name/host-name"},
+		Replace: []Rule{
+			mustParseRule(t, `ip-prefix=10\.0\.0\.\d+/32=>ip-prefix=<system-ip>/32`),
+			mustParseRule(t, `(?i)([0-9a-f]{2}:){5}[0-9a-f]{2}=><mac>`),
+		},
+	}
+	res, err := Compare(context.Background(), testClient(t), "default", "leaf1", "leaf2", nil, rules)
+	if err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	WriteResult(out, res, render.Default().Plain())
+	want := `leaf1 -> leaf2: 3 difference(s) in 5 leaves compared
+~ /interface[name=ethernet-1/1]/mtu: 9232 -> 1500
+- /system/ntp/server[address=10.1.1.1]/admin-state: enable
++ /system/ntp/server[address=10.1.1.2]/admin-state: enable
+`
+	if out.String() != want {
+		t.Fatalf("WriteResult() =\n%s\nwant\n%s", out, want)
+	}
+
+	res, err = Compare(context.Background(), testClient(t), "default", "leaf1", "leaf2", []string{"/interface[name=system0]", "/interface[name=mgmt0]"}, rules)
+	if err != nil {
+		t.Fatalf("Compare() unexpected error: %v", err)
+	}
+	out.Reset()
+	WriteResult(out, res, render.Default().Plain())
+	if want := "leaf1 and leaf2 match, 2 leaves compared\n"; out.String() != want {
+		t.Fatalf("WriteResult() = %q, want %q", out, want)
+	}
+}
+
+func TestCompare_UnknownTarget(t *testing.T) {
+	_, err := Compare(context.Background(), testClient(t), "default", "leaf1", "leaf3", nil, Rules{})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Compare() error = %v, want NotFound", err)
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	r := mustParseRule(t, `\[name=leaf\d\]=>[name=<leaf>]`)
+	if r.Regex != `\[name=leaf\d\]` || r.Replacement != "[name=<leaf>]" {
+		t.Fatalf("ParseRule() = %q, %q", r.Regex, r.Replacement)
+	}
+	if got := mustParseRule(t, "leaf[12]=>").apply("leaf1-leaf2"); got != "-" {
+		t.Fatalf("apply() = %q, want -", got)
+	}
+
+	for s, want := range map[string]string{
+		"leaf1=x": `invalid rule "leaf1=x", must be REGEX=>REPLACEMENT`,
+		"=>x":     "invalid rule, the regex is empty",
+		"(leaf=>": "invalid rule regex \"(leaf\": error parsing regexp: missing closing ): `(leaf`",
+	} {
+		if _, err := ParseRule(s); err == nil || err.Error() != want {
+			t.Errorf("ParseRule(%q) error = %v, want %q", s, err, want)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	content := `ignorePaths:
+- /system/name/host-name
+replace:
+- regex: '10\.0\.0\.\d+'
+  replacement: <system-ip>
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules() unexpected error: %v", err)
+	}
+	rules = rules.Merge(Rules{Replace: []Rule{mustParseRule(t, "leaf[12]=><leaf>")}})
+	if len(rules.IgnorePaths) != 1 || len(rules.Replace) != 2 {
+		t.Fatalf("LoadRules() = %+v", rules)
+	}
+	if got := rules.Replace[0].apply("10.0.0.2/32"); got != "<system-ip>/32" {
+		t.Fatalf("apply() = %q, want <system-ip>/32", got)
+	}
+
+	if err := os.WriteFile(path, []byte("replace:\n- regex: '('\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil || !strings.Contains(err.Error(), "invalid rule regex") {
+		t.Fatalf("LoadRules() error = %v, want an invalid regex", err)
+	}
+	if err := os.WriteFile(path, []byte("ignore: [/a]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Fatal("LoadRules() of an unknown field expected an error")
+	}
+}
@@ -0,0 +1,97 @@
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is an on-disk cache of JSON encoded completion data, entries expire by
+// their modification time. A Cache without a directory caches nothing.
+type Cache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewCache returns a cache storing its entries in dir
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir, now: time.Now}
+}
+
+// DefaultCacheDir returns the completion cache directory in the user cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "kubectl-sdc", "completion"), nil
+}
+
+func (c *Cache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get decodes the entry of key into v, returns false if it is missing or older than ttl
+func (c *Cache) Get(key string, ttl time.Duration, v any) bool {
+	if c == nil || c.dir == "" {
+		return false
+	}
+	file := c.file(key)
+	fi, err := os.Stat(file)
+	if err != nil || c.now().Sub(fi.ModTime()) > ttl {
+		return false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// Set stores v as the entry of key
+func (c *Cache) Set(key string, v any) error {
+	if c == nil || c.dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	// write to a temporary file first, so concurrent completions never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.file(key))
+}
+
+// cached returns the entry of key, calling fetch and storing its result on a miss
+func cached[T any](c *Cache, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	var v T
+	if c.Get(key, ttl, &v) {
+		return v, nil
+	}
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	// a failure to cache must not fail the completion
+	_ = c.Set(key, v)
+	return v, nil
+}
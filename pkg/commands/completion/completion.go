@@ -0,0 +1,297 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/schema"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+const (
+	// BlameTTL is how long the content of a blame tree is cached, it changes with the configuration
+	BlameTTL = 30 * time.Second
+	// ConfigTTL is how long the Config names are cached
+	ConfigTTL = 30 * time.Second
+	// SchemaTTL is how long schema nodes are cached, they only change with a schema reload
+	SchemaTTL = 10 * time.Minute
+)
+
+// ConfigClient defines the config-server operations used for completion
+type ConfigClient interface {
+	GetBlameTree(ctx context.Context, namespace string, device string) (*sdcpb.BlameTreeElement, error)
+	GetTargetSchema(ctx context.Context, namespace string, targetName string) (*sdcpb.Schema, error)
+	ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error)
+}
+
+// Completer completes paths, owners, leaf and intent names of a target
+type Completer struct {
+	configs ConfigClient
+	schemas schema.SchemaClient
+	cache   *Cache
+	// scope separates the cache entries of different clusters
+	scope string
+}
+
+// NewCompleter returns a Completer, cache and schemas may be nil
+func NewCompleter(configs ConfigClient, schemas schema.SchemaClient, cache *Cache, scope string) *Completer {
+	return &Completer{
+		configs: configs,
+		schemas: schemas,
+		cache:   cache,
+		scope:   scope,
+	}
+}
+
+// Paths returns the paths extending toComplete by the next path element or list key.
+// Existing list entries are taken from the blame tree of the target, element and key
+// names from its schema. An error is only returned if both sources fail.
+func (p *Completer) Paths(ctx context.Context, namespace, target, toComplete string) ([]string, error) {
+	if !strings.HasPrefix(toComplete, "/") {
+		toComplete = "/" + toComplete
+	}
+
+	blameComps, blameErr := p.completeFromBlame(ctx, namespace, target, toComplete)
+	schemaComps, schemaErr := p.completeFromSchema(ctx, namespace, target, toComplete)
+	if blameErr != nil && schemaErr != nil {
+		return nil, errors.Join(blameErr, schemaErr)
+	}
+	return sortedUnique(append(blameComps, schemaComps...)), nil
+}
+
+// Owners returns the distinct owners of the leaves in the blame tree of the target
+func (p *Completer) Owners(ctx context.Context, namespace, target string) ([]string, error) {
+	summary, err := p.blameSummary(ctx, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+	return summary.Owners, nil
+}
+
+// Leaves returns the distinct leaf names in the blame tree of the target
+func (p *Completer) Leaves(ctx context.Context, namespace, target string) ([]string, error) {
+	summary, err := p.blameSummary(ctx, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+	return summary.Leaves, nil
+}
+
+// Intents returns the intent names, <namespace>.<name>, of the Config resources of the
+// namespace, limited to the target if set
+func (p *Completer) Intents(ctx context.Context, namespace, target string) ([]string, error) {
+	key := strings.Join([]string{"configs", p.scope, namespace, target}, "/")
+	return cached(p.cache, key, ConfigTTL, func() ([]string, error) {
+		var labels map[string]string
+		if target != "" {
+			labels = map[string]string{client.TargetLabel: target}
+		}
+		configs, err := p.configs.ListConfigs(ctx, namespace, labels)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(configs))
+		for _, c := range configs {
+			names = append(names, client.IntentName(c.Namespace, c.Name))
+		}
+		return sortedUnique(names), nil
+	})
+}
+
+// blameSummary is the content of a blame tree needed for completion
+type blameSummary struct {
+	Paths  []string
+	Owners []string
+	Leaves []string
+}
+
+func (p *Completer) blameSummary(ctx context.Context, namespace, target string) (*blameSummary, error) {
+	key := strings.Join([]string{"blame", p.scope, namespace, target}, "/")
+	return cached(p.cache, key, BlameTTL, func() (*blameSummary, error) {
+		tree, err := p.configs.GetBlameTree(ctx, namespace, target)
+		if err != nil {
+			return nil, err
+		}
+		summary := &blameSummary{}
+		for _, c := range tree.GetChilds() {
+			c.WalkPath(c.GetPath(nil), func(node *sdcpb.BlameTreeElement, path *sdcpb.Path) {
+				summary.Paths = append(summary.Paths, path.ToXPath(false))
+				// the blame filters only match leaves
+				if node.GetValue() == nil && node.GetDeviationValue() == nil {
+					return
+				}
+				summary.Leaves = append(summary.Leaves, node.GetName())
+				if node.GetOwner() != "" {
+					summary.Owners = append(summary.Owners, node.GetOwner())
+				}
+			})
+		}
+		summary.Owners = sortedUnique(summary.Owners)
+		summary.Leaves = sortedUnique(summary.Leaves)
+		return summary, nil
+	})
+}
+
+func (p *Completer) completeFromBlame(ctx context.Context, namespace, target, toComplete string) ([]string, error) {
+	summary, err := p.blameSummary(ctx, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+	depth := strings.Count(toComplete, "[") - strings.Count(toComplete, "]")
+	var comps []string
+	for _, path := range summary.Paths {
+		if !strings.HasPrefix(path, toComplete) {
+			continue
+		}
+		comps = append(comps, toComplete+nextElem(path[len(toComplete):], depth))
+	}
+	return comps, nil
+}
+
+// targetSchema resolves the schema of the target
+func (p *Completer) targetSchema(ctx context.Context, namespace, target string) (*sdcpb.Schema, error) {
+	key := strings.Join([]string{"target-schema", p.scope, namespace, target}, "/")
+	s, err := cached(p.cache, key, SchemaTTL, func() ([2]string, error) {
+		s, err := p.configs.GetTargetSchema(ctx, namespace, target)
+		if err != nil {
+			return [2]string{}, err
+		}
+		return [2]string{s.GetVendor(), s.GetVersion()}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sdcpb.Schema{Vendor: s[0], Version: s[1]}, nil
+}
+
+// schemaNode returns the schema node of a path
+func (p *Completer) schemaNode(ctx context.Context, s *sdcpb.Schema, path string) (*schema.Node, error) {
+	if path == "" {
+		path = "/"
+	}
+	// list keys are irrelevant for the schema, so all the entries of a list share a cache entry
+	parsed, err := sdcpb.ParsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	path = parsed.ToXPath(true)
+
+	key := strings.Join([]string{"schema", p.scope, s.GetVendor(), s.GetVersion(), path}, "/")
+	return cached(p.cache, key, SchemaTTL, func() (*schema.Node, error) {
+		return schema.Show(ctx, p.schemas, s, path)
+	})
+}
+
+func (p *Completer) completeFromSchema(ctx context.Context, namespace, target, toComplete string) ([]string, error) {
+	if p.schemas == nil {
+		return nil, fmt.Errorf("no schema client")
+	}
+	s, err := p.targetSchema(ctx, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, partial := splitLastElem(toComplete)
+
+	// a partial list key, complete the names of the missing keys
+	if idx := strings.Index(partial, "["); idx >= 0 {
+		last := strings.LastIndex(partial, "[")
+		if strings.Contains(partial[last:], "=") {
+			// key values are only known from the blame tree
+			return nil, nil
+		}
+		node, err := p.schemaNode(ctx, s, parent+"/"+partial[:idx])
+		if err != nil {
+			return nil, err
+		}
+		base := partial[:last]
+		var comps []string
+		for _, k := range node.Keys {
+			if !strings.Contains(base, "["+k+"=") {
+				comps = append(comps, parent+"/"+base+"["+k+"=")
+			}
+		}
+		return comps, nil
+	}
+
+	node, err := p.schemaNode(ctx, s, parent)
+	if err != nil {
+		return nil, err
+	}
+	var comps []string
+	for _, c := range node.Children {
+		if !strings.HasPrefix(c, partial) {
+			continue
+		}
+		comps = append(comps, parent+"/"+c)
+		// an exact match of a list, offer its keys
+		if c == partial {
+			child, err := p.schemaNode(ctx, s, parent+"/"+c)
+			if err != nil {
+				return nil, err
+			}
+			for _, k := range child.Keys {
+				comps = append(comps, parent+"/"+c+"["+k+"=")
+			}
+		}
+	}
+	return comps, nil
+}
+
+// splitLastElem splits a path into its parent and its last, possibly partial, element
+func splitLastElem(path string) (string, string) {
+	depth := 0
+	last := 0
+	for i, r := range path {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				last = i
+			}
+		}
+	}
+	return path[:last], path[last+1:]
+}
+
+// nextElem returns the beginning of rest up to the end of the element name or list key
+// it starts in, depth is the number of brackets left open before rest
+func nextElem(rest string, depth int) string {
+	for i, r := range rest {
+		switch r {
+		case '[':
+			if depth == 0 && i > 0 {
+				return rest[:i]
+			}
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 && i > 0 {
+				return rest[:i]
+			}
+		}
+	}
+	return rest
+}
+
+func sortedUnique(s []string) []string {
+	sort.Strings(s)
+	result := s[:0]
+	for i, v := range s {
+		if i > 0 && s[i-1] == v {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
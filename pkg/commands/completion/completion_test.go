@@ -0,0 +1,276 @@
+package completion
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	mockcompletion "github.com/sdcio/kubectl-sdc/mocks/completion"
+	mockschema "github.com/sdcio/kubectl-sdc/mocks/schema"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testSchema = &sdcpb.Schema{Vendor: "nokia", Version: "24.10"}
+
+func stringValue(s string) *sdcpb.TypedValue {
+	return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: s}}
+}
+
+func testBlameTree() *sdcpb.BlameTreeElement {
+	return &sdcpb.BlameTreeElement{
+		Name: "root",
+		Childs: []*sdcpb.BlameTreeElement{
+			{
+				Name: "interface",
+				Childs: []*sdcpb.BlameTreeElement{
+					{
+						Name:    "ethernet-1/1",
+						KeyName: "name",
+						Childs: []*sdcpb.BlameTreeElement{
+							{Name: "admin-state", Owner: "default.intent-a", Value: stringValue("enable")},
+							{Name: "mtu", Owner: "default.intent-a", Value: stringValue("9000")},
+						},
+					},
+					{
+						Name:    "ethernet-1/2",
+						KeyName: "name",
+						Childs: []*sdcpb.BlameTreeElement{
+							{Name: "admin-state", Owner: "running", Value: stringValue("disable"), DeviationValue: stringValue("enable")},
+						},
+					},
+				},
+			},
+			{
+				Name: "system",
+				Childs: []*sdcpb.BlameTreeElement{
+					{Name: "name", Owner: "default.intent-b", Value: stringValue("srl1")},
+				},
+			},
+		},
+	}
+}
+
+func containerElem(c *sdcpb.ContainerSchema) *sdcpb.SchemaElem {
+	return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Container{Container: c}}
+}
+
+func testSchemaElems() map[string]*sdcpb.SchemaElem {
+	return map[string]*sdcpb.SchemaElem{
+		"/": containerElem(&sdcpb.ContainerSchema{Children: []string{"interface", "system", "network-instance"}}),
+		"/interface": containerElem(&sdcpb.ContainerSchema{
+			Keys:     []*sdcpb.LeafSchema{{Name: "name"}},
+			Fields:   []*sdcpb.LeafSchema{{Name: "admin-state"}, {Name: "description"}, {Name: "mtu"}},
+			Children: []string{"subinterface"},
+		}),
+		"/interface/subinterface": containerElem(&sdcpb.ContainerSchema{
+			Keys: []*sdcpb.LeafSchema{{Name: "index"}},
+		}),
+		"/network-instance": containerElem(&sdcpb.ContainerSchema{
+			Keys: []*sdcpb.LeafSchema{{Name: "name"}},
+		}),
+		"/system": containerElem(&sdcpb.ContainerSchema{Fields: []*sdcpb.LeafSchema{{Name: "name"}}}),
+	}
+}
+
+func newTestCompleter(t *testing.T, cache *Cache) (*Completer, *mockcompletion.MockConfigClient, *mockschema.MockSchemaClient) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	configs := mockcompletion.NewMockConfigClient(ctrl)
+	configs.EXPECT().GetTargetSchema(gomock.Any(), "default", "srl1").Return(testSchema, nil).AnyTimes()
+	schemas := mockschema.NewMockSchemaClient(ctrl)
+	return NewCompleter(configs, schemas, cache, "cluster"), configs, schemas
+}
+
+func expectSchemaElems(schemas *mockschema.MockSchemaClient, elems map[string]*sdcpb.SchemaElem) *gomock.Call {
+	return schemas.EXPECT().GetSchemaElem(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *sdcpb.Schema, p *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+			elem, ok := elems[p.ToXPath(true)]
+			if !ok {
+				return nil, errors.New("unknown element")
+			}
+			return elem, nil
+		})
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{
+			name:       "top level elements",
+			toComplete: "",
+			want:       []string{"/interface", "/network-instance", "/system"},
+		},
+		{
+			name:       "partial element",
+			toComplete: "/int",
+			want:       []string{"/interface"},
+		},
+		{
+			name:       "list entries and keys",
+			toComplete: "/interface",
+			want:       []string{"/interface", "/interface[name=", "/interface[name=ethernet-1/1]", "/interface[name=ethernet-1/2]"},
+		},
+		{
+			name:       "partial key value",
+			toComplete: "/interface[name=ethernet-1/",
+			want:       []string{"/interface[name=ethernet-1/1]", "/interface[name=ethernet-1/2]"},
+		},
+		{
+			name:       "children of a list entry",
+			toComplete: "/interface[name=ethernet-1/1]/",
+			want: []string{
+				"/interface[name=ethernet-1/1]/admin-state",
+				"/interface[name=ethernet-1/1]/description",
+				"/interface[name=ethernet-1/1]/mtu",
+				"/interface[name=ethernet-1/1]/name",
+				"/interface[name=ethernet-1/1]/subinterface",
+			},
+		},
+		{
+			name:       "key of a list without entries",
+			toComplete: "/network-instance[",
+			want:       []string{"/network-instance[name="},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, configs, schemas := newTestCompleter(t, nil)
+			configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(testBlameTree(), nil)
+			expectSchemaElems(schemas, testSchemaElems()).AnyTimes()
+
+			got, err := p.Paths(context.Background(), "default", "srl1", tt.toComplete)
+			if err != nil {
+				t.Fatalf("Paths() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Paths() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaths_SchemaFailureFallsBackToBlame(t *testing.T) {
+	p, configs, schemas := newTestCompleter(t, nil)
+	configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(testBlameTree(), nil)
+	schemas.EXPECT().GetSchemaElem(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("port-forward failed"))
+
+	got, err := p.Paths(context.Background(), "default", "srl1", "/")
+	if err != nil {
+		t.Fatalf("Paths() error = %v", err)
+	}
+	if want := []string{"/interface", "/system"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Paths() = %q, want %q", got, want)
+	}
+}
+
+func TestPaths_BothSourcesFail(t *testing.T) {
+	p, configs, schemas := newTestCompleter(t, nil)
+	configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(nil, errors.New("not found"))
+	schemas.EXPECT().GetSchemaElem(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("port-forward failed"))
+
+	if _, err := p.Paths(context.Background(), "default", "srl1", "/"); err == nil {
+		t.Fatal("Paths() expected error")
+	}
+}
+
+func TestPaths_Cached(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	p, configs, schemas := newTestCompleter(t, cache)
+	// served once, the second completion is answered from the cache
+	configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(testBlameTree(), nil).Times(1)
+	expectSchemaElems(schemas, testSchemaElems()).Times(1)
+
+	for range 2 {
+		if _, err := p.Paths(context.Background(), "default", "srl1", "/sys"); err != nil {
+			t.Fatalf("Paths() error = %v", err)
+		}
+	}
+
+	// the blame tree expires before the schema
+	now = now.Add(BlameTTL + time.Second)
+	configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(testBlameTree(), nil).Times(1)
+	if _, err := p.Paths(context.Background(), "default", "srl1", "/sys"); err != nil {
+		t.Fatalf("Paths() error = %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(t.TempDir())
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var got []string
+	if cache.Get("key", time.Minute, &got) {
+		t.Fatal("Get() hit on an empty cache")
+	}
+	if err := cache.Set("key", []string{"a", "b"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !cache.Get("key", time.Minute, &got) || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("Get() = %q, want [a b]", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if cache.Get("key", time.Minute, &got) {
+		t.Fatal("Get() hit on an expired entry")
+	}
+
+	// a cache without directory caches nothing
+	disabled := NewCache("")
+	if err := disabled.Set("key", "v"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	var v string
+	if disabled.Get("key", time.Minute, &v) {
+		t.Fatal("Get() hit on a disabled cache")
+	}
+}
+
+func TestOwnersAndLeaves(t *testing.T) {
+	p, configs, _ := newTestCompleter(t, nil)
+	configs.EXPECT().GetBlameTree(gomock.Any(), "default", "srl1").Return(testBlameTree(), nil).Times(2)
+
+	owners, err := p.Owners(context.Background(), "default", "srl1")
+	if err != nil {
+		t.Fatalf("Owners() error = %v", err)
+	}
+	if want := []string{"default.intent-a", "default.intent-b", "running"}; !reflect.DeepEqual(owners, want) {
+		t.Fatalf("Owners() = %q, want %q", owners, want)
+	}
+
+	leaves, err := p.Leaves(context.Background(), "default", "srl1")
+	if err != nil {
+		t.Fatalf("Leaves() error = %v", err)
+	}
+	if want := []string{"admin-state", "mtu", "name"}; !reflect.DeepEqual(leaves, want) {
+		t.Fatalf("Leaves() = %q, want %q", leaves, want)
+	}
+}
+
+func TestIntents(t *testing.T) {
+	p, configs, _ := newTestCompleter(t, nil)
+	configs.EXPECT().ListConfigs(gomock.Any(), "default", map[string]string{client.TargetLabel: "srl1"}).Return([]v1alpha1.Config{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "intent-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "intent-a"}},
+	}, nil)
+
+	intents, err := p.Intents(context.Background(), "default", "srl1")
+	if err != nil {
+		t.Fatalf("Intents() error = %v", err)
+	}
+	if want := []string{"default.intent-a", "default.intent-b"}; !reflect.DeepEqual(intents, want) {
+		t.Fatalf("Intents() = %q, want %q", intents, want)
+	}
+}
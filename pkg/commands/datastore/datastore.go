@@ -0,0 +1,126 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// DatastoreClient defines the data-server datastore operations
+type DatastoreClient interface {
+	ListDataStores(ctx context.Context) ([]*sdcpb.GetDataStoreResponse, error)
+	GetDataStore(ctx context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error)
+}
+
+// Summary is the state of a datastore as reported by the data-server
+type Summary struct {
+	Name          string `json:"name"`
+	TargetType    string `json:"targetType"`
+	Address       string `json:"address"`
+	SchemaVendor  string `json:"schemaVendor,omitempty"`
+	SchemaVersion string `json:"schemaVersion,omitempty"`
+	// Status is the connection status of the target, which drives the sync of the datastore
+	Status        string   `json:"status"`
+	StatusDetails string   `json:"statusDetails,omitempty"`
+	Intents       []string `json:"intents"`
+}
+
+// Target returns the type and address of the target
+func (s *Summary) Target() string {
+	if s.Address == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s://%s", s.TargetType, s.Address)
+}
+
+// Schema returns the schema vendor and version, "-" if unknown
+func (s *Summary) Schema() string {
+	if s.SchemaVendor == "" && s.SchemaVersion == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%s", s.SchemaVendor, s.SchemaVersion)
+}
+
+// List returns the datastores of the namespace sorted by name, or of all namespaces
+// if namespace is empty
+func List(ctx context.Context, cl DatastoreClient, namespace string) ([]*Summary, error) {
+	datastores, err := cl.ListDataStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]*Summary, 0, len(datastores))
+	for _, ds := range datastores {
+		if namespace != "" && !strings.HasPrefix(ds.GetDatastoreName(), namespace+".") {
+			continue
+		}
+		summaries = append(summaries, newSummary(ds))
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+// Get returns the datastore of the target
+func Get(ctx context.Context, cl DatastoreClient, namespace, target string) (*Summary, error) {
+	ds, err := cl.GetDataStore(ctx, client.DatastoreName(namespace, target))
+	if err != nil {
+		return nil, err
+	}
+	return newSummary(ds), nil
+}
+
+func newSummary(ds *sdcpb.GetDataStoreResponse) *Summary {
+	s := &Summary{
+		Name:          ds.GetDatastoreName(),
+		TargetType:    ds.GetTarget().GetType(),
+		SchemaVendor:  ds.GetSchema().GetVendor(),
+		SchemaVersion: ds.GetSchema().GetVersion(),
+		Status:        ds.GetTarget().GetStatus().String(),
+		StatusDetails: ds.GetTarget().GetStatusDetails(),
+		Intents:       append([]string(nil), ds.GetIntents()...),
+	}
+	if addr := ds.GetTarget().GetAddress(); addr != "" {
+		s.Address = addr
+		if port := ds.GetTarget().GetPort(); port != 0 {
+			s.Address = fmt.Sprintf("%s:%d", addr, port)
+		}
+	}
+	sort.Strings(s.Intents)
+	return s
+}
+
+// WriteList writes the datastores as a table
+func WriteList(out io.Writer, summaries []*Summary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tTARGET\tSCHEMA\tSTATUS\tINTENTS")
+	for _, s := range summaries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", s.Name, s.Target(), s.Schema(), s.Status, len(s.Intents))
+	}
+	return w.Flush()
+}
+
+// WriteGet writes the details of a datastore
+func WriteGet(out io.Writer, s *Summary) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Name:\t%s\n", s.Name)
+	_, _ = fmt.Fprintf(w, "Target:\t%s\n", s.Target())
+	_, _ = fmt.Fprintf(w, "Schema:\t%s\n", s.Schema())
+	_, _ = fmt.Fprintf(w, "Status:\t%s\n", s.Status)
+	if s.StatusDetails != "" {
+		_, _ = fmt.Fprintf(w, "Status Details:\t%s\n", s.StatusDetails)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "Intents (%d):\n", len(s.Intents))
+	for _, intent := range s.Intents {
+		_, _ = fmt.Fprintf(out, "  %s\n", intent)
+	}
+	return nil
+}
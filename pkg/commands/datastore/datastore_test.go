@@ -0,0 +1,99 @@
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	mockdatastore "github.com/sdcio/kubectl-sdc/mocks/datastore"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+)
+
+func testDatastores() []*sdcpb.GetDataStoreResponse {
+	return []*sdcpb.GetDataStoreResponse{
+		{
+			DatastoreName: "default.srl2",
+			Intents:       []string{"running"},
+			Target: &sdcpb.Target{
+				Type:          "gnmi",
+				Address:       "172.21.0.12",
+				Port:          57400,
+				Status:        sdcpb.TargetStatus_NOT_CONNECTED,
+				StatusDetails: "connection refused",
+			},
+		},
+		{
+			DatastoreName: "default.srl1",
+			Intents:       []string{"running", "default.intent-b", "default.intent-a"},
+			Schema:        &sdcpb.Schema{Vendor: "srl.nokia.sdcio.dev", Version: "24.10.1"},
+			Target:        &sdcpb.Target{Type: "gnmi", Address: "172.21.0.11", Port: 57400, Status: sdcpb.TargetStatus_CONNECTED},
+		},
+		{
+			DatastoreName: "lab.srl1",
+			Intents:       []string{"running"},
+		},
+	}
+}
+
+func TestList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mockdatastore.NewMockDatastoreClient(ctrl)
+	cl.EXPECT().ListDataStores(gomock.Any()).Return(testDatastores(), nil).Times(2)
+
+	summaries, err := List(context.Background(), cl, "default")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := WriteList(out, summaries); err != nil {
+		t.Fatalf("WriteList() error = %v", err)
+	}
+	want := `NAME           TARGET                     SCHEMA                        STATUS          INTENTS
+default.srl1   gnmi://172.21.0.11:57400   srl.nokia.sdcio.dev/24.10.1   CONNECTED       3
+default.srl2   gnmi://172.21.0.12:57400   -                             NOT_CONNECTED   1
+`
+	if out.String() != want {
+		t.Fatalf("WriteList() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	summaries, err = List(context.Background(), cl, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(summaries) != 3 || summaries[2].Name != "lab.srl1" || summaries[2].Target() != "-" {
+		t.Fatalf("List() for all namespaces = %+v", summaries)
+	}
+}
+
+func TestGet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mockdatastore.NewMockDatastoreClient(ctrl)
+	cl.EXPECT().GetDataStore(gomock.Any(), "default.srl2").Return(testDatastores()[0], nil)
+	cl.EXPECT().GetDataStore(gomock.Any(), "default.srl3").Return(nil, errors.New("unknown datastore"))
+
+	summary, err := Get(context.Background(), cl, "default", "srl2")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := WriteGet(out, summary); err != nil {
+		t.Fatalf("WriteGet() error = %v", err)
+	}
+	want := `Name:            default.srl2
+Target:          gnmi://172.21.0.12:57400
+Schema:          -
+Status:          NOT_CONNECTED
+Status Details:  connection refused
+Intents (1):
+  running
+`
+	if out.String() != want {
+		t.Fatalf("WriteGet() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if _, err := Get(context.Background(), cl, "default", "srl3"); err == nil {
+		t.Fatal("Get() expected error")
+	}
+}
@@ -1,7 +1,10 @@
 package deviations
 
+import "io"
+
 type DeviationOptions struct {
-	target    string
+	// targets to load the deviations for
+	targets   []string
 	namespace string
 	// deviationName name which equals the config name
 	deviationName string
@@ -13,7 +16,10 @@ type DeviationOptions struct {
 	initialQuery               string
 	selectPathPrefix           []string
 	filterPath                 []string
+	ignorePath                 []string
 	autoAcceptSelectPathPrefix bool
+	// out receives the per-target revert report
+	out io.Writer
 }
 
 type DeviationOptionSetter func(d *DeviationOptions)
@@ -21,6 +27,7 @@ type DeviationOptionSetter func(d *DeviationOptions)
 func NewDeviationOptions(namespace string, opts ...DeviationOptionSetter) *DeviationOptions {
 	do := &DeviationOptions{
 		namespace: namespace,
+		out:       io.Discard,
 	}
 
 	// apply options
@@ -32,8 +39,8 @@ func NewDeviationOptions(namespace string, opts ...DeviationOptionSetter) *Devia
 }
 
 // Getters
-func (d *DeviationOptions) Target() string {
-	return d.target
+func (d *DeviationOptions) Targets() []string {
+	return d.targets
 }
 
 func (d *DeviationOptions) DeviationName() string {
@@ -60,6 +67,10 @@ func (d *DeviationOptions) FilterPath() []string {
 	return d.filterPath
 }
 
+func (d *DeviationOptions) IgnorePath() []string {
+	return d.ignorePath
+}
+
 func (d *DeviationOptions) InitialQuery() string {
 	return d.initialQuery
 }
@@ -83,7 +94,17 @@ func WithRevert(b bool) DeviationOptionSetter {
 
 func WithTarget(target string) DeviationOptionSetter {
 	return func(d *DeviationOptions) {
-		d.target = target
+		if target != "" {
+			d.targets = append(d.targets, target)
+		}
+	}
+}
+
+func WithTargets(targets []string) DeviationOptionSetter {
+	return func(d *DeviationOptions) {
+		for _, t := range targets {
+			WithTarget(t)(d)
+		}
 	}
 }
 
@@ -117,8 +138,23 @@ func WithFilterPath(prefixes []string) DeviationOptionSetter {
 	}
 }
 
+// WithIgnorePath drops the deviations of paths with these prefixes
+func WithIgnorePath(prefixes []string) DeviationOptionSetter {
+	return func(d *DeviationOptions) {
+		d.ignorePath = prefixes
+	}
+}
+
 func WithAutoAcceptSelectPathPrefix(autoAccept bool) DeviationOptionSetter {
 	return func(d *DeviationOptions) {
 		d.autoAcceptSelectPathPrefix = autoAccept
 	}
 }
+
+func WithOutput(out io.Writer) DeviationOptionSetter {
+	return func(d *DeviationOptions) {
+		if out != nil {
+			d.out = out
+		}
+	}
+}
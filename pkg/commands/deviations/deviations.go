@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
@@ -86,9 +89,22 @@ type deviationViewConfig struct {
 }
 
 func buildDeviationViewConfig(devs types.Deviations, deviations []*types.Deviation, do *DeviationOptions, maxNameLength int) deviationViewConfig {
+	if devs.MultipleTargets() {
+		maxTargetLength := devs.MaxTargetNameLength()
+		return deviationViewConfig{
+			header: fmt.Sprintf("Namespace: %s, Targets: %s", do.namespace, strings.Join(devs.Targets(), ", ")),
+			searchItem: func(i int) string {
+				return fmt.Sprintf("%s%s%s%s", deviations[i].Target(), deviations[i].Name(), deviations[i].DesiredValue, deviations[i].ActualValue)
+			},
+			display: func(i int) string {
+				return fmt.Sprintf("%-*s %-*s %s %s", maxTargetLength, deviations[i].Target(), maxNameLength, deviations[i].Name(), reasonInitial(deviations[i].Reason), deviations[i].Path)
+			},
+		}
+	}
+
 	if devs.MultipleIntents() {
 		return deviationViewConfig{
-			header: fmt.Sprintf("Namespace: %s, Target: %s", do.namespace, do.target),
+			header: fmt.Sprintf("Namespace: %s, Target: %s", do.namespace, devs.First().Target()),
 			searchItem: func(i int) string {
 				return fmt.Sprintf("%s%s", deviations[i].DesiredValue, deviations[i].ActualValue)
 			},
@@ -119,8 +135,18 @@ func loadDeviations(ctx context.Context, cl DeviationClient, do *DeviationOption
 		devs := types.Deviations{}
 		devs.AddDeviation(dev)
 		return devs, nil
-	case do.target != "":
-		return cl.GetDeviationsByTarget(ctx, do.namespace, do.target)
+	case len(do.targets) > 0:
+		devs := types.Deviations{}
+		for _, target := range do.targets {
+			targetDevs, err := cl.GetDeviationsByTarget(ctx, do.namespace, target)
+			if err != nil {
+				return nil, fmt.Errorf("target %s: %w", target, err)
+			}
+			for _, dev := range targetDevs {
+				devs.AddDeviation(dev)
+			}
+		}
+		return devs, nil
 	default:
 		return nil, ErrDeviationOrTargetNotSet
 	}
@@ -190,7 +216,7 @@ func Run(ctx context.Context, cl DeviationClient, do *DeviationOptions) (types.D
 
 	// collect all the deviations into a single slice for fuzzy finding
 	deviations := devs.Items()
-	deviations = deviations.FilterByPathPrefixes(do.FilterPath())
+	deviations = deviations.FilterByPathPrefixes(do.FilterPath()).ExcludePathPrefixes(do.IgnorePath())
 	if len(deviations) == 0 {
 		return nil, ErrNoDeviationsAfterPathFiltering
 	}
@@ -206,12 +232,59 @@ func Run(ctx context.Context, cl DeviationClient, do *DeviationOptions) (types.D
 	}
 
 	if do.Revert() && selectedDeviations.HasDeviations() {
-		return nil, revert(ctx, cl, do.namespace, selectedDeviations.First().Target(), selectedDeviations)
+		return nil, revertAll(ctx, cl, do.namespace, selectedDeviations, do.out)
 	}
 
 	return selectedDeviations, nil
 }
 
+// revertResult holds the outcome of clearing the deviations of a single target
+type revertResult struct {
+	target types.TargetKey
+	paths  int
+	err    error
+}
+
+// revertAll groups the deviations by target and clears each target concurrently.
+// A line per target is written to out and the failures are returned joined.
+func revertAll(ctx context.Context, cl DeviationClient, namespace string, devs types.Deviations, out io.Writer) error {
+	groups := devs.ByTarget()
+	results := make([]revertResult, 0, len(groups))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for key, targetDevs := range groups {
+		// fall back to the namespace of the command if the deviation carries none
+		if key.Namespace == "" {
+			key.Namespace = namespace
+		}
+		wg.Add(1)
+		go func(key types.TargetKey, targetDevs types.Deviations) {
+			defer wg.Done()
+			err := revert(ctx, cl, key.Namespace, key.Target, targetDevs)
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, revertResult{target: key, paths: len(targetDevs.Items()), err: err})
+		}(key, targetDevs)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].target.String() < results[j].target.String()
+	})
+
+	var errs []error
+	for _, r := range results {
+		if r.err != nil {
+			_, _ = fmt.Fprintf(out, "target %s: revert failed: %v\n", r.target, r.err)
+			errs = append(errs, fmt.Errorf("target %s: %w", r.target, r.err))
+			continue
+		}
+		_, _ = fmt.Fprintf(out, "target %s: reverted %d deviation(s)\n", r.target, r.paths)
+	}
+	return errors.Join(errs...)
+}
+
 // revert clears the specified paths on a target
 func revert(ctx context.Context, cl DeviationClient, namespace, targetName string, devs types.Deviations) error {
 	if !devs.HasDeviations() {
@@ -1,8 +1,11 @@
 package deviations
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 
 	fuzzyfinder "github.com/ktr0731/go-fuzzyfinder"
@@ -230,6 +233,27 @@ func TestRun_NonInteractiveFilterPath(t *testing.T) {
 	}
 }
 
+func TestRun_IgnorePathDropsDeviations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cl := mockdeviations.NewMockDeviationClient(ctrl)
+	cl.EXPECT().GetDeviationsByTarget(gomock.Any(), "default", "target-1").Return(newTestDeviations(), nil).Times(2)
+
+	selected, err := Run(context.Background(), cl, NewDeviationOptions("default", WithTarget("target-1"), WithIgnorePath([]string{"/system/location"})))
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if got := selected.First().DeviationPaths(); len(got) != 1 || got[0] != "/system/name" {
+		t.Fatalf("selected paths = %v, want [/system/name]", got)
+	}
+
+	_, err = Run(context.Background(), cl, NewDeviationOptions("default", WithTarget("target-1"), WithIgnorePath([]string{"/system"})))
+	if !errors.Is(err, ErrNoDeviationsAfterPathFiltering) {
+		t.Fatalf("Run() error = %v, want errors.Is(..., ErrNoDeviationsAfterPathFiltering)", err)
+	}
+}
+
 func TestRun_FilterPathNoMatchesReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -279,6 +303,59 @@ func TestRun_RevertClearsSelectedDeviations(t *testing.T) {
 	}
 }
 
+func TestRun_MultipleTargetsRevertPerTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cl := mockdeviations.NewMockDeviationClient(ctrl)
+	cl.EXPECT().GetDeviationsByTarget(gomock.Any(), "default", "target-1").Return(newTestDeviations(), nil)
+	cl.EXPECT().GetDeviationsByTarget(gomock.Any(), "default", "target-2").Return(newTestTargetDeviations("target-2", "dev-2"), nil)
+
+	var mu sync.Mutex
+	cleared := map[string]int{}
+	cl.EXPECT().ClearTargetDeviations(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(_ context.Context, resource *v1alpha1.TargetClearDeviation) error {
+		mu.Lock()
+		defer mu.Unlock()
+		cleared[resource.Name] = len(resource.Spec.Config[0].Paths)
+		if resource.Name == "target-2" {
+			return errors.New("forbidden")
+		}
+		return nil
+	})
+
+	restore := stubFindDeviationIndexes(func(deviations []*types.Deviation, display func(i int) string, opts ...interface{}) ([]int, error) {
+		if len(deviations) != 4 {
+			t.Fatalf("deviations length = %d, want 4", len(deviations))
+		}
+		if got := display(0); !strings.HasPrefix(got, deviations[0].Target()) {
+			t.Fatalf("display = %q, want target prefix %q", got, deviations[0].Target())
+		}
+		return []int{0, 1, 2, 3}, nil
+	})
+	defer restore()
+
+	out := &bytes.Buffer{}
+	selected, err := Run(context.Background(), cl, NewDeviationOptions("default",
+		WithTargets([]string{"target-1", "target-2"}),
+		WithInteractive(true),
+		WithRevert(true),
+		WithOutput(out),
+	))
+	if err == nil || !strings.Contains(err.Error(), "target default/target-2: forbidden") {
+		t.Fatalf("Run() error = %v, want target-2 failure", err)
+	}
+	if selected != nil {
+		t.Fatalf("selected = %v, want nil after revert", selected)
+	}
+	if cleared["target-1"] != 2 || cleared["target-2"] != 2 {
+		t.Fatalf("cleared = %v, want 2 paths per target", cleared)
+	}
+	want := "target default/target-1: reverted 2 deviation(s)\ntarget default/target-2: revert failed: forbidden\n"
+	if out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+}
+
 func TestRun_NoTargetOrDeviationReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -340,6 +417,15 @@ func newTestDeviations() types.Deviations {
 	return devs
 }
 
+func newTestTargetDeviations(target, name string) types.Deviations {
+	intent := types.NewDeviations(target, name, types.DeviationTypeConfig, 2).SetNamespace("default")
+	intent.AddDeviation(types.NewDeviation("/system/name", "router-1", "router-2", "mismatch"))
+	intent.AddDeviation(types.NewDeviation("/system/location", "lab-1", "lab-2", "mismatch"))
+	devs := types.Deviations{}
+	devs.AddDeviation(intent)
+	return devs
+}
+
 func newTestIntentDeviations() *types.IntentDeviations {
 	intent := types.NewDeviations("target-1", "dev-1", types.DeviationTypeConfig, 2).SetNamespace("default")
 	intent.AddDeviation(types.NewDeviation("/system/name", "router-1", "router-2", "mismatch"))
@@ -0,0 +1,161 @@
+// Package grep searches the running configuration of targets for leaves whose
+// path or value matches a pattern.
+package grep
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+)
+
+// Field is the part of a leaf a pattern is matched against
+type Field string
+
+const (
+	FieldPath  Field = "path"
+	FieldValue Field = "value"
+	FieldBoth  Field = "both"
+)
+
+// Fields lists the fields in the order of the help
+var Fields = []Field{FieldPath, FieldValue, FieldBoth}
+
+// FieldStrings returns the fields as strings
+func FieldStrings() []string {
+	s := make([]string, len(Fields))
+	for i, f := range Fields {
+		s[i] = string(f)
+	}
+	return s
+}
+
+// ParseField converts a string to a Field
+func ParseField(s string) (Field, error) {
+	for _, f := range Fields {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid match field %q, must be one of: %s", s, strings.Join(FieldStrings(), ", "))
+}
+
+// Matcher matches the leaves of a configuration
+type Matcher struct {
+	re    *regexp.Regexp
+	field Field
+}
+
+// NewMatcher returns a matcher of the field. A regular expression matches
+// anywhere in the field, like grep does. Otherwise the pattern is a wildcard
+// pattern matching the whole field, * matches any characters and ? a single one.
+func NewMatcher(pattern string, field Field, regex, ignoreCase bool) (*Matcher, error) {
+	expr := pattern
+	if !regex {
+		expr = "^" + wildcardExpr(pattern) + "$"
+	}
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return &Matcher{re: re, field: field}, nil
+}
+
+// wildcardExpr returns the regular expression of a wildcard pattern
+func wildcardExpr(pattern string) string {
+	sb := &strings.Builder{}
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return sb.String()
+}
+
+// Match returns true if the leaf matches
+func (m *Matcher) Match(path, value string) bool {
+	switch m.field {
+	case FieldPath:
+		return m.re.MatchString(path)
+	case FieldValue:
+		return m.re.MatchString(value)
+	default:
+		return m.re.MatchString(path) || m.re.MatchString(value)
+	}
+}
+
+// Match is a matching leaf of a target
+type Match struct {
+	Path  string
+	Value string
+}
+
+// Result holds the matches of a target, or why it could not be searched
+type Result struct {
+	Target  string
+	Matches []Match
+	Err     error
+}
+
+// Search matches the leaves of the running config of each target, in the
+// order of the targets. A target that fails is reported in its result, the
+// search goes on with the next one.
+func Search(ctx context.Context, cl client.IntentClient, namespace string, targets []string, m *Matcher) []Result {
+	results := make([]Result, 0, len(targets))
+	for _, target := range targets {
+		r := Result{Target: target}
+		intent, err := cl.GetIntent(ctx, client.FormatXPath, client.DatastoreName(namespace, target), runningconfig.RunningIntentName)
+		if err != nil {
+			r.Err = err
+			results = append(results, r)
+			continue
+		}
+		leaves := types.LeavesFromIntent(intent.GetProto())
+		for _, path := range leaves.Paths() {
+			if m.Match(path, leaves[path]) {
+				r.Matches = append(r.Matches, Match{Path: path, Value: leaves[path]})
+			}
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// WriteMatches writes a line per matching leaf with its target, path and value
+func WriteMatches(out io.Writer, results []Result, r render.Renderer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for _, res := range results {
+		for _, m := range res.Matches {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", res.Target, m.Path, r.Value(m.Value))
+		}
+	}
+	return w.Flush()
+}
+
+// WriteCounts writes the number of matches of each searched target
+func WriteCounts(out io.Writer, results []Result) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TARGET\tMATCHES")
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\n", res.Target, len(res.Matches))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,104 @@
+package grep
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/client/clienttest"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+func testClient(t *testing.T) clienttest.IntentClient {
+	return clienttest.IntentClient{
+		"default.srl1": {"running": {Update: []*sdcpb.Update{
+			clienttest.NewUpdate(t, "/system/ntp/server[address=10.1.1.1]/admin-state", "enable"),
+			clienttest.NewUpdate(t, "/system/name/host-name", "srl1"),
+		}}},
+		"default.srl2": {"running": {Update: []*sdcpb.Update{
+			clienttest.NewUpdate(t, "/system/ntp/server[address=10.1.1.10]/admin-state", "enable"),
+			clienttest.NewUpdate(t, "/system/dns/server-list", "10.1.1.1"),
+		}}},
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		field      Field
+		regex      bool
+		ignoreCase bool
+		path       string
+		value      string
+		want       bool
+	}{
+		{name: "wildcard matches the whole value", pattern: "10.1.1.1", field: FieldValue, value: "10.1.1.10", want: false},
+		{name: "wildcard value", pattern: "10.1.1.*", field: FieldValue, value: "10.1.1.10", want: true},
+		{name: "wildcard dot is literal", pattern: "10.1.1.1", field: FieldValue, value: "10-1-1-1", want: false},
+		{name: "single character wildcard", pattern: "srl?", field: FieldValue, value: "srl2", want: true},
+		{name: "wildcard path", pattern: "*ntp*", field: FieldPath, path: "/system/ntp/server[address=10.1.1.1]/admin-state", want: true},
+		{name: "path field ignores the value", pattern: "enable", field: FieldPath, path: "/a", value: "enable", want: false},
+		{name: "both fields", pattern: "*10.1.1.1*", field: FieldBoth, path: "/system/ntp/server[address=10.1.1.1]/admin-state", value: "enable", want: true},
+		{name: "regex matches anywhere", pattern: `ntp/server\[address=10\.1\.1\.1\]`, field: FieldPath, regex: true, path: "/system/ntp/server[address=10.1.1.1]/admin-state", want: true},
+		{name: "ignore case", pattern: "SRL1", field: FieldValue, ignoreCase: true, value: "srl1", want: true},
+		{name: "case sensitive", pattern: "SRL1", field: FieldValue, value: "srl1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.pattern, tt.field, tt.regex, tt.ignoreCase)
+			if err != nil {
+				t.Fatalf("NewMatcher() unexpected error: %v", err)
+			}
+			if got := m.Match(tt.path, tt.value); got != tt.want {
+				t.Fatalf("Match(%q, %q) = %v, want %v", tt.path, tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewMatcher("(", FieldBoth, true, false); err == nil {
+		t.Fatal("NewMatcher() of an invalid regex expected an error")
+	}
+	if _, err := ParseField("key"); err == nil || err.Error() != `invalid match field "key", must be one of: path, value, both` {
+		t.Fatalf("ParseField() error = %v", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m, err := NewMatcher("*10.1.1.1*", FieldBoth, false, false)
+	if err != nil {
+		t.Fatalf("NewMatcher() unexpected error: %v", err)
+	}
+	results := Search(context.Background(), testClient(t), "default", []string{"srl1", "srl2", "srl3"}, m)
+	if len(results) != 3 {
+		t.Fatalf("Search() returned %d results, want 3", len(results))
+	}
+	if results[2].Err == nil {
+		t.Errorf("Search() of srl3 expected an error")
+	}
+
+	out := &bytes.Buffer{}
+	if err := WriteMatches(out, results, render.Default().Plain()); err != nil {
+		t.Fatalf("WriteMatches() unexpected error: %v", err)
+	}
+	want := `srl1   /system/ntp/server[address=10.1.1.1]/admin-state    enable
+srl2   /system/dns/server-list                             10.1.1.1
+srl2   /system/ntp/server[address=10.1.1.10]/admin-state   enable
+`
+	if out.String() != want {
+		t.Fatalf("WriteMatches() =\n%s\nwant\n%s", out, want)
+	}
+
+	out.Reset()
+	if err := WriteCounts(out, results); err != nil {
+		t.Fatalf("WriteCounts() unexpected error: %v", err)
+	}
+	want = `TARGET   MATCHES
+srl1     1
+srl2     2
+`
+	if out.String() != want {
+		t.Fatalf("WriteCounts() =\n%s\nwant\n%s", out, want)
+	}
+}
@@ -0,0 +1,232 @@
+// Package history records snapshots of the configuration of a target, to tell
+// what changed on it since and to return its Configs to an earlier state.
+//
+// A snapshot holds the running config of the target and the leaves of each of
+// its intents, as known by the data-server, together with the Config resources
+// of the target. Snapshots are stored locally or in ConfigMaps, see Store.
+package history
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/commands/runningconfig"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// idLayout is the layout of the snapshot IDs, the UTC time they were taken
+const idLayout = "20060102-150405"
+
+// lastAppliedAnnotation is set by kubectl apply, it is left out of the snapshots
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Client defines the data-server and Kubernetes operations used to take a snapshot
+type Client interface {
+	GetDataStore(ctx context.Context, datastoreName string) (*sdcpb.GetDataStoreResponse, error)
+	client.IntentClient
+	ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error)
+}
+
+// Snapshot is the configuration of a target at a point in time
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	Target    string    `json:"target"`
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message,omitempty"`
+	// Running holds the leaves of the running config
+	Running types.Leaves `json:"running"`
+	// Intents holds the leaves of the intents of the target, sorted by name
+	Intents []Intent `json:"intents,omitempty"`
+	// Configs holds the Config resources of the target, sorted by name
+	Configs []v1alpha1.Config `json:"configs,omitempty"`
+}
+
+// Intent is the configuration of a data-server intent
+type Intent struct {
+	Name     string       `json:"name"`
+	Priority int32        `json:"priority"`
+	Leaves   types.Leaves `json:"leaves"`
+}
+
+// Take takes a snapshot of the target, now is the time it is taken at. An unsaved
+// snapshot is the current state of the target, to compare stored snapshots to.
+func Take(ctx context.Context, cl Client, namespace, target, message string, now time.Time) (*Snapshot, error) {
+	now = now.UTC().Truncate(time.Second)
+	s := &Snapshot{
+		ID:        now.Format(idLayout),
+		Namespace: namespace,
+		Target:    target,
+		Time:      now,
+		Message:   message,
+	}
+
+	datastoreName := client.DatastoreName(namespace, target)
+	ds, err := cl.GetDataStore(ctx, datastoreName)
+	if err != nil {
+		return nil, err
+	}
+	running, err := cl.GetIntent(ctx, client.FormatXPath, datastoreName, runningconfig.RunningIntentName)
+	if err != nil {
+		return nil, err
+	}
+	s.Running = types.LeavesFromIntent(running.GetProto())
+
+	intents := ds.GetIntents()
+	sort.Strings(intents)
+	for _, name := range intents {
+		if name == runningconfig.RunningIntentName {
+			continue
+		}
+		intent, err := cl.GetIntent(ctx, client.FormatXPath, datastoreName, name)
+		if err != nil {
+			return nil, err
+		}
+		s.Intents = append(s.Intents, Intent{
+			Name:     name,
+			Priority: intent.GetProto().GetPriority(),
+			Leaves:   types.LeavesFromIntent(intent.GetProto()),
+		})
+	}
+
+	configs, err := cl.ListConfigs(ctx, namespace, map[string]string{client.TargetLabel: target})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range configs {
+		s.Configs = append(s.Configs, snapshotConfig(c))
+	}
+	sort.Slice(s.Configs, func(i, j int) bool { return s.Configs[i].Name < s.Configs[j].Name })
+	return s, nil
+}
+
+// snapshotConfig returns the Config without its status and server managed metadata
+func snapshotConfig(c v1alpha1.Config) v1alpha1.Config {
+	annotations := map[string]string{}
+	for k, v := range c.Annotations {
+		if k != lastAppliedAnnotation {
+			annotations[k] = v
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	return v1alpha1.Config{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: v1alpha1.ConfigKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            c.Name,
+			Namespace:       c.Namespace,
+			Labels:          c.Labels,
+			Annotations:     annotations,
+			OwnerReferences: c.OwnerReferences,
+		},
+		Spec: c.Spec,
+	}
+}
+
+// intent returns the intent with the name, nil if the snapshot has none
+func (s *Snapshot) intent(name string) *Intent {
+	for i := range s.Intents {
+		if s.Intents[i].Name == name {
+			return &s.Intents[i]
+		}
+	}
+	return nil
+}
+
+// Find returns the snapshot with the ID, or the only one whose ID starts with ref
+func Find(snapshots []*Snapshot, ref string) (*Snapshot, error) {
+	var matches []*Snapshot
+	for _, s := range snapshots {
+		if s.ID == ref {
+			return s, nil
+		}
+		if strings.HasPrefix(s.ID, ref) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("snapshot %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, s := range matches {
+			ids = append(ids, s.ID)
+		}
+		return nil, fmt.Errorf("snapshot %q is ambiguous, it matches %s", ref, strings.Join(ids, ", "))
+	}
+}
+
+// WriteList writes the snapshots as a table
+func WriteList(out io.Writer, snapshots []*Snapshot) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTIME\tRUNNING\tINTENTS\tCONFIGS\tMESSAGE")
+	for _, s := range snapshots {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", s.ID, s.Time.Local().Format(time.RFC3339), len(s.Running), len(s.Intents), len(s.Configs), s.Message)
+	}
+	return w.Flush()
+}
+
+// SectionDiff holds the changes of the running config or of an intent
+type SectionDiff struct {
+	// Name is "running" or the name of the intent
+	Name    string
+	Changes []types.LeafChange
+}
+
+// Diff returns the changes from a snapshot to another, first of the running
+// config and then of the intents by name. Sections without changes are left out.
+func Diff(from, to *Snapshot) []SectionDiff {
+	var diffs []SectionDiff
+	if changes := types.DiffLeaves(from.Running, to.Running); len(changes) > 0 {
+		diffs = append(diffs, SectionDiff{Name: runningconfig.RunningIntentName, Changes: changes})
+	}
+
+	names := map[string]bool{}
+	for _, s := range []*Snapshot{from, to} {
+		for _, i := range s.Intents {
+			names[i.Name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		current, desired := types.Leaves{}, types.Leaves{}
+		if i := from.intent(name); i != nil {
+			current = i.Leaves
+		}
+		if i := to.intent(name); i != nil {
+			desired = i.Leaves
+		}
+		if changes := types.DiffLeaves(current, desired); len(changes) > 0 {
+			diffs = append(diffs, SectionDiff{Name: "intent " + name, Changes: changes})
+		}
+	}
+	return diffs
+}
+
+// WriteDiff writes the changes of each section, the way apply --diff does
+func WriteDiff(out io.Writer, diffs []SectionDiff, r render.Renderer) {
+	for _, d := range diffs {
+		_, _ = fmt.Fprintf(out, "%s: %d change(s)\n", d.Name, len(d.Changes))
+		for _, c := range d.Changes {
+			_, _ = fmt.Fprintln(out, r.LeafChange(c))
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/client/clienttest"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type stubClient struct {
+	clienttest.IntentClient
+	configs []v1alpha1.Config
+	labels  map[string]string
+}
+
+func (s *stubClient) GetDataStore(_ context.Context, name string) (*sdcpb.GetDataStoreResponse, error) {
+	resp := &sdcpb.GetDataStoreResponse{DatastoreName: name}
+	for n := range s.IntentClient[name] {
+		resp.Intents = append(resp.Intents, n)
+	}
+	return resp, nil
+}
+
+func (s *stubClient) ListConfigs(_ context.Context, _ string, labels map[string]string) ([]v1alpha1.Config, error) {
+	s.labels = labels
+	return s.configs, nil
+}
+
+func testConfig(name, hostName string) v1alpha1.Config {
+	return v1alpha1.Config{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      map[string]string{client.TargetLabel: "srl1"},
+			Annotations: map[string]string{lastAppliedAnnotation: "{}"},
+		},
+		Spec: v1alpha1.ConfigSpec{
+			Priority: 10,
+			Config: []v1alpha1.ConfigBlob{{
+				Path:  "/",
+				Value: runtime.RawExtension{Raw: []byte(`{"system": {"name": {"host-name": "` + hostName + `"}}}`)},
+			}},
+		},
+	}
+}
+
+func TestTake(t *testing.T) {
+	cl := &stubClient{
+		IntentClient: clienttest.IntentClient{"default.srl1": {
+			"running": {Update: []*sdcpb.Update{
+				clienttest.NewUpdate(t, "/system/name/host-name", "srl1"),
+				clienttest.NewUpdate(t, "/interface[name=mgmt0]/admin-state", "enable"),
+			}},
+			"default.intent-a": {Priority: 10, Update: []*sdcpb.Update{clienttest.NewUpdate(t, "/system/name/host-name", "srl1")}},
+		}},
+		configs: []v1alpha1.Config{testConfig("intent-a", "srl1")},
+	}
+
+	now := time.Date(2026, 10, 19, 8, 30, 15, 500, time.UTC)
+	s, err := Take(context.Background(), cl, "default", "srl1", "before upgrade", now)
+	if err != nil {
+		t.Fatalf("Take() unexpected error: %v", err)
+	}
+	if s.ID != "20261019-083015" {
+		t.Errorf("ID = %s, want 20261019-083015", s.ID)
+	}
+	if s.Message != "before upgrade" || len(s.Running) != 2 {
+		t.Errorf("unexpected snapshot: %+v", s)
+	}
+	if len(s.Intents) != 1 || s.Intents[0].Name != "default.intent-a" || s.Intents[0].Priority != 10 {
+		t.Fatalf("Intents = %+v, want default.intent-a with priority 10", s.Intents)
+	}
+	if cl.labels[client.TargetLabel] != "srl1" {
+		t.Errorf("ListConfigs() labels = %v, want the target label of srl1", cl.labels)
+	}
+	if len(s.Configs) != 1 || s.Configs[0].Kind != v1alpha1.ConfigKind || s.Configs[0].Annotations != nil {
+		t.Errorf("Configs = %+v, want intent-a without the last-applied annotation", s.Configs)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := &Snapshot{
+		ID:      "20261018-080000",
+		Running: types.Leaves{"/system/name/host-name": "srl1", "/system/information/location": "lab"},
+		Intents: []Intent{
+			{Name: "default.intent-a", Leaves: types.Leaves{"/system/name/host-name": "srl1"}},
+			{Name: "default.intent-old", Leaves: types.Leaves{"/system/information/contact": "noc"}},
+		},
+	}
+	to := &Snapshot{
+		ID:      "20261019-080000",
+		Running: types.Leaves{"/system/name/host-name": "srl1-new", "/system/information/location": "lab"},
+		Intents: []Intent{
+			{Name: "default.intent-a", Leaves: types.Leaves{"/system/name/host-name": "srl1-new"}},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	WriteDiff(out, Diff(from, to), render.Default().Plain())
+	want := `running: 1 change(s)
+~ /system/name/host-name: srl1 -> srl1-new
+intent default.intent-a: 1 change(s)
+~ /system/name/host-name: srl1 -> srl1-new
+intent default.intent-old: 1 change(s)
+- /system/information/contact: noc
+`
+	if out.String() != want {
+		t.Fatalf("WriteDiff() =\n%s\nwant\n%s", out, want)
+	}
+
+	if diffs := Diff(to, to); len(diffs) != 0 {
+		t.Fatalf("Diff() of the same snapshot = %v, want no changes", diffs)
+	}
+}
+
+func TestFind(t *testing.T) {
+	snapshots := []*Snapshot{{ID: "20261018-080000"}, {ID: "20261019-080000"}, {ID: "20261019-090000"}}
+
+	s, err := Find(snapshots, "20261018")
+	if err != nil || s.ID != "20261018-080000" {
+		t.Fatalf("Find() = %v, %v, want 20261018-080000", s, err)
+	}
+	if _, err := Find(snapshots, "20261019"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("Find() error = %v, want ambiguous", err)
+	}
+	if _, err := Find(snapshots, "2025"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Find() error = %v, want not found", err)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	s := &Snapshot{
+		ID:        "20261018-080000",
+		Namespace: "default",
+		Target:    "srl1",
+		Configs: []v1alpha1.Config{
+			snapshotConfig(testConfig("intent-a", "srl1")),
+			snapshotConfig(testConfig("intent-b", "srl1")),
+			snapshotConfig(testConfig("intent-c", "srl1")),
+		},
+	}
+	// intent-a is unchanged, intent-b changed, intent-c deleted and intent-d created
+	unchanged := testConfig("intent-a", "srl1")
+	unchanged.Spec.Config[0].Value.Raw = []byte(`{"system":{"name":{"host-name":"srl1"}}}`)
+	current := []v1alpha1.Config{unchanged, testConfig("intent-b", "srl1-new"), testConfig("intent-d", "srl1")}
+
+	plan := Rollback(s, current)
+	if len(plan.Apply) != 2 || plan.Apply[0].Name != "intent-b" || plan.Apply[1].Name != "intent-c" {
+		t.Fatalf("Apply = %v, want intent-b and intent-c", plan.Apply)
+	}
+	if len(plan.Delete) != 1 || plan.Delete[0].Name != "intent-d" {
+		t.Fatalf("Delete = %v, want intent-d", plan.Delete)
+	}
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if err := WritePlan(out, errOut, s, plan); err != nil {
+		t.Fatalf("WritePlan() unexpected error: %v", err)
+	}
+	if got := strings.Count(out.String(), "kind: Config\n"); got != 2 {
+		t.Errorf("WritePlan() wrote %d manifests, want 2:\n%s", got, out)
+	}
+	if !strings.Contains(out.String(), "\n---\n") || strings.Contains(out.String(), "status") {
+		t.Errorf("unexpected manifests:\n%s", out)
+	}
+	if want := "kubectl delete config -n default intent-d"; !strings.Contains(errOut.String(), want) {
+		t.Errorf("WritePlan() errOut = %q, want %q", errOut, want)
+	}
+
+	errOut.Reset()
+	if err := WritePlan(out, errOut, s, Rollback(s, s.Configs)); err != nil {
+		t.Fatalf("WritePlan() unexpected error: %v", err)
+	}
+	if want := "the Configs of default/srl1 match snapshot 20261018-080000\n"; errOut.String() != want {
+		t.Errorf("WritePlan() errOut = %q, want %q", errOut, want)
+	}
+}
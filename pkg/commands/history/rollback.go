@@ -0,0 +1,132 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// RollbackPlan holds the Config changes returning the intents of a target to a snapshot
+type RollbackPlan struct {
+	// Apply holds the Configs of the snapshot that are missing or differ now
+	Apply []v1alpha1.Config
+	// Delete holds the Configs created since the snapshot
+	Delete []v1alpha1.Config
+}
+
+// Empty is true if the Configs match the snapshot
+func (p RollbackPlan) Empty() bool {
+	return len(p.Apply) == 0 && len(p.Delete) == 0
+}
+
+// Rollback compares the Configs of the snapshot to the current Configs of its target
+func Rollback(s *Snapshot, current []v1alpha1.Config) RollbackPlan {
+	currentByName := map[string]v1alpha1.Config{}
+	for _, c := range current {
+		currentByName[c.Name] = c
+	}
+
+	plan := RollbackPlan{}
+	snapshotNames := map[string]bool{}
+	for _, c := range s.Configs {
+		snapshotNames[c.Name] = true
+		cur, ok := currentByName[c.Name]
+		if !ok || !sameConfig(c, cur) {
+			plan.Apply = append(plan.Apply, c)
+		}
+	}
+	for _, c := range current {
+		if !snapshotNames[c.Name] {
+			plan.Delete = append(plan.Delete, c)
+		}
+	}
+	return plan
+}
+
+// sameConfig compares the labels and the spec of the Configs in their JSON form,
+// the configuration blobs of both may differ in formatting only
+func sameConfig(a, b v1alpha1.Config) bool {
+	if !reflect.DeepEqual(normalize(a.Labels), normalize(b.Labels)) {
+		return false
+	}
+	return reflect.DeepEqual(normalize(a.Spec), normalize(b.Spec))
+}
+
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// manifest returns the Config as it is applied, without the fields owned by the cluster
+func manifest(c v1alpha1.Config) map[string]any {
+	metadata := map[string]any{
+		"name":      c.Name,
+		"namespace": c.Namespace,
+	}
+	if len(c.Labels) > 0 {
+		metadata["labels"] = c.Labels
+	}
+	if len(c.Annotations) > 0 {
+		metadata["annotations"] = c.Annotations
+	}
+	return map[string]any{
+		"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+		"kind":       v1alpha1.ConfigKind,
+		"metadata":   metadata,
+		"spec":       c.Spec,
+	}
+}
+
+// configSetOwner returns the name of the ConfigSet owning the Config, if any
+func configSetOwner(c v1alpha1.Config) string {
+	for _, ref := range c.OwnerReferences {
+		if ref.Kind == v1alpha1.ConfigSetKind {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// WritePlan writes the Configs to apply as YAML manifests to out, and what the
+// manifests do not cover to errOut
+func WritePlan(out, errOut io.Writer, s *Snapshot, plan RollbackPlan) error {
+	if plan.Empty() {
+		_, _ = fmt.Fprintf(errOut, "the Configs of %s/%s match snapshot %s\n", s.Namespace, s.Target, s.ID)
+		return nil
+	}
+
+	for i, c := range plan.Apply {
+		data, err := yaml.Marshal(manifest(c))
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			_, _ = fmt.Fprintln(out, "---")
+		}
+		if _, err := out.Write(data); err != nil {
+			return err
+		}
+		if owner := configSetOwner(c); owner != "" {
+			_, _ = fmt.Fprintf(errOut, "warning: Config %s is owned by ConfigSet %s, which reverts changes to it, roll back the ConfigSet instead\n", c.Name, owner)
+		}
+	}
+	for _, c := range plan.Delete {
+		if owner := configSetOwner(c); owner != "" {
+			_, _ = fmt.Fprintf(errOut, "warning: Config %s was created since the snapshot by ConfigSet %s\n", c.Name, owner)
+			continue
+		}
+		_, _ = fmt.Fprintf(errOut, "Config %s was created since the snapshot, delete it with: kubectl delete config -n %s %s\n", c.Name, c.Namespace, c.Name)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package history
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Store values
+const (
+	StoreLocal     = "local"
+	StoreConfigMap = "configmap"
+)
+
+// Store saves and lists the snapshots of targets
+type Store interface {
+	Save(ctx context.Context, s *Snapshot) error
+	// List returns the snapshots of the target, oldest first
+	List(ctx context.Context, namespace, target string) ([]*Snapshot, error)
+	// String describes where the snapshots are stored
+	String() string
+}
+
+func sortSnapshots(snapshots []*Snapshot) {
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].ID < snapshots[j].ID })
+}
+
+// FileStore stores the snapshots as JSON files in a directory per namespace and target
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store of the snapshots in dir
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// DefaultFileStoreDir returns the history directory in the user data directory,
+// $XDG_DATA_HOME or ~/.local/share
+func DefaultFileStoreDir() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "kubectl-sdc", "history"), nil
+}
+
+func (f *FileStore) targetDir(namespace, target string) string {
+	return filepath.Join(f.dir, namespace, target)
+}
+
+func (f *FileStore) Save(_ context.Context, s *Snapshot) error {
+	dir := f.targetDir(s.Namespace, s.Target)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, s.ID+".json"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("snapshot %s of %s/%s already exists", s.ID, s.Namespace, s.Target)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f *FileStore) List(_ context.Context, namespace, target string) ([]*Snapshot, error) {
+	dir := f.targetDir(namespace, target)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		s := &Snapshot{}
+		if err := json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("failed to read the snapshot %s: %w", e.Name(), err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	sortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+func (f *FileStore) String() string {
+	return f.dir
+}
+
+const (
+	// TargetLabel labels the snapshot ConfigMaps with their target
+	TargetLabel = "kubectl-sdc.sdcio.dev/history-target"
+	// snapshotKey is the binary data key of the gzipped snapshot
+	snapshotKey = "snapshot.json.gz"
+	// maxConfigMapSize is the size limit of the data of a ConfigMap
+	maxConfigMapSize = 1 << 20
+)
+
+// ConfigMapClient defines the Kubernetes operations of the ConfigMap store
+type ConfigMapClient interface {
+	ListConfigMaps(ctx context.Context, namespace string, labels map[string]string) ([]corev1.ConfigMap, error)
+	CreateConfigMap(ctx context.Context, cm *corev1.ConfigMap) error
+}
+
+// ConfigMapStore stores each snapshot gzipped in a ConfigMap in the namespace of
+// its target, so the history is shared by everyone working on the cluster
+type ConfigMapStore struct {
+	client ConfigMapClient
+}
+
+// NewConfigMapStore returns a store of the snapshots in ConfigMaps
+func NewConfigMapStore(cl ConfigMapClient) *ConfigMapStore {
+	return &ConfigMapStore{client: cl}
+}
+
+// ConfigMapName returns the name of the ConfigMap of a snapshot
+func ConfigMapName(target, id string) string {
+	return fmt.Sprintf("sdc-history-%s-%s", target, id)
+}
+
+func (c *ConfigMapStore) Save(ctx context.Context, s *Snapshot) error {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if buf.Len() > maxConfigMapSize {
+		return fmt.Errorf("snapshot %s of %s/%s is %d bytes gzipped, more than the %d bytes a ConfigMap holds, use the %s store", s.ID, s.Namespace, s.Target, buf.Len(), maxConfigMapSize, StoreLocal)
+	}
+
+	err := c.client.CreateConfigMap(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(s.Target, s.ID),
+			Namespace: s.Namespace,
+			Labels:    map[string]string{TargetLabel: s.Target},
+		},
+		BinaryData: map[string][]byte{snapshotKey: buf.Bytes()},
+	})
+	if apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("snapshot %s of %s/%s already exists", s.ID, s.Namespace, s.Target)
+	}
+	return err
+}
+
+func (c *ConfigMapStore) List(ctx context.Context, namespace, target string) ([]*Snapshot, error) {
+	cms, err := c.client.ListConfigMaps(ctx, namespace, map[string]string{TargetLabel: target})
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*Snapshot
+	for _, cm := range cms {
+		data, ok := cm.BinaryData[snapshotKey]
+		if !ok {
+			continue
+		}
+		s, err := readSnapshot(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the snapshot %s: %w", cm.Name, err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	sortSnapshots(snapshots)
+	return snapshots, nil
+}
+
+func readSnapshot(data []byte) (*Snapshot, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(raw, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (c *ConfigMapStore) String() string {
+	return "ConfigMaps labelled " + TargetLabel
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testSnapshots() []*Snapshot {
+	return []*Snapshot{
+		{ID: "20261019-080000", Namespace: "default", Target: "srl1", Time: time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), Running: types.Leaves{"/system/name/host-name": "srl1-new"}},
+		{ID: "20261018-080000", Namespace: "default", Target: "srl1", Time: time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC), Running: types.Leaves{"/system/name/host-name": "srl1"}},
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	for _, s := range testSnapshots() {
+		if err := store.Save(ctx, s); err != nil {
+			t.Fatalf("Save() unexpected error: %v", err)
+		}
+	}
+	if err := store.Save(ctx, testSnapshots()[0]); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("Save() of an existing snapshot error = %v, want already exists", err)
+	}
+
+	got, err := store.List(ctx, "default", "srl1")
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "20261018-080000" || got[1].ID != "20261019-080000" {
+		t.Fatalf("List() = %v, want both snapshots oldest first", got)
+	}
+	if got[1].Running["/system/name/host-name"] != "srl1-new" {
+		t.Errorf("List() running = %v", got[1].Running)
+	}
+
+	if got, err := store.List(ctx, "default", "srl2"); err != nil || len(got) != 0 {
+		t.Fatalf("List() of another target = %v, %v, want none", got, err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, NewFileStore(t.TempDir()))
+}
+
+func TestDefaultFileStoreDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	dir, err := DefaultFileStoreDir()
+	if err != nil {
+		t.Fatalf("DefaultFileStoreDir() unexpected error: %v", err)
+	}
+	if dir != "/data/kubectl-sdc/history" {
+		t.Fatalf("DefaultFileStoreDir() = %s, want /data/kubectl-sdc/history", dir)
+	}
+}
+
+type stubConfigMapClient struct {
+	configMaps []corev1.ConfigMap
+}
+
+func (s *stubConfigMapClient) ListConfigMaps(_ context.Context, namespace string, labels map[string]string) ([]corev1.ConfigMap, error) {
+	var cms []corev1.ConfigMap
+	for _, cm := range s.configMaps {
+		if cm.Namespace == namespace && cm.Labels[TargetLabel] == labels[TargetLabel] {
+			cms = append(cms, cm)
+		}
+	}
+	return cms, nil
+}
+
+func (s *stubConfigMapClient) CreateConfigMap(_ context.Context, cm *corev1.ConfigMap) error {
+	for _, existing := range s.configMaps {
+		if existing.Namespace == cm.Namespace && existing.Name == cm.Name {
+			return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, cm.Name)
+		}
+	}
+	s.configMaps = append(s.configMaps, *cm)
+	return nil
+}
+
+func TestConfigMapStore(t *testing.T) {
+	cl := &stubConfigMapClient{}
+	testStore(t, NewConfigMapStore(cl))
+
+	if name := cl.configMaps[0].Name; name != "sdc-history-srl1-20261019-080000" {
+		t.Errorf("ConfigMap name = %s, want sdc-history-srl1-20261019-080000", name)
+	}
+
+	// random values barely compress, the snapshot does not fit a ConfigMap
+	random := make([]byte, 1<<20)
+	_, _ = rand.Read(random)
+	large := &Snapshot{ID: "20261020-080000", Namespace: "default", Target: "srl1", Running: types.Leaves{"/system/description": hex.EncodeToString(random)}}
+	if err := NewConfigMapStore(cl).Save(context.Background(), large); err == nil || !strings.Contains(err.Error(), "more than the 1048576 bytes a ConfigMap holds") {
+		t.Fatalf("Save() of a large snapshot error = %v, want the ConfigMap size limit", err)
+	}
+}
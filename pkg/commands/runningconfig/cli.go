@@ -0,0 +1,176 @@
+package runningconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// CLIStyle selects how the cli format renders the configuration
+type CLIStyle string
+
+const (
+	// CLIStyleHierarchy renders a brace structured hierarchy, like SR Linux info and SR OS
+	CLIStyleHierarchy CLIStyle = "hierarchy"
+	// CLIStyleSet renders a flat set command per leaf, like Junos and SR Linux
+	CLIStyleSet CLIStyle = "set"
+)
+
+// CLIStyles lists the cli styles, the first is the default
+var CLIStyles = []CLIStyle{CLIStyleHierarchy, CLIStyleSet}
+
+// CLIStyleStrings returns the cli styles as strings
+func CLIStyleStrings() []string {
+	styles := make([]string, len(CLIStyles))
+	for i, s := range CLIStyles {
+		styles[i] = string(s)
+	}
+	return styles
+}
+
+// ParseCLIStyle parses a cli style, case insensitive
+func ParseCLIStyle(s string) (CLIStyle, error) {
+	switch style := CLIStyle(strings.ToLower(s)); style {
+	case CLIStyleHierarchy, CLIStyleSet:
+		return style, nil
+	default:
+		return "", fmt.Errorf("invalid cli style %q, must be one of: %s", s, strings.Join(CLIStyleStrings(), ", "))
+	}
+}
+
+// cliNode is a container, list entry or leaf of the configuration
+type cliNode struct {
+	// name is the element name followed by the key values of a list entry
+	name string
+	// keys are the names of the keys of a list entry, their leaves are part of the name
+	keys     []string
+	value    *sdcpb.TypedValue
+	children map[string]*cliNode
+}
+
+func (n *cliNode) child(pe *sdcpb.PathElem) *cliNode {
+	keys := make([]string, 0, len(pe.GetKey()))
+	for k := range pe.GetKey() {
+		keys = append(keys, k)
+	}
+	// the schema order of the keys is unknown, they are sorted by name
+	slices.Sort(keys)
+	name := pe.GetName()
+	for _, k := range keys {
+		name += " " + cliQuote(pe.GetKey()[k])
+	}
+
+	if n.children == nil {
+		n.children = map[string]*cliNode{}
+	}
+	c, ok := n.children[name]
+	if !ok {
+		c = &cliNode{name: name, keys: keys}
+		n.children[name] = c
+	}
+	return c
+}
+
+func (n *cliNode) sortedChildren() []*cliNode {
+	children := make([]*cliNode, 0, len(n.children))
+	for _, c := range n.children {
+		// the key leaves are rendered as part of the name of the list entry
+		if c.value != nil && len(c.children) == 0 && slices.Contains(n.keys, c.name) {
+			continue
+		}
+		children = append(children, c)
+	}
+	slices.SortFunc(children, func(a, b *cliNode) int { return strings.Compare(a.name, b.name) })
+	return children
+}
+
+// cliTree builds the tree of the leaves of the intent
+func cliTree(intent *sdcpb.Intent) *cliNode {
+	root := &cliNode{}
+	for _, upd := range intent.GetUpdate() {
+		n := root
+		for _, pe := range upd.GetPath().GetElem() {
+			n = n.child(pe)
+		}
+		n.value = upd.GetValue()
+	}
+	return root
+}
+
+// RenderCLI renders the configuration of the intent the way a device CLI shows it.
+// The rendering only relies on the path element names and keys, so it works for
+// any schema. List keys follow the name of the list inline.
+func RenderCLI(intent *sdcpb.Intent, style CLIStyle, r render.Renderer) string {
+	sb := &strings.Builder{}
+	root := cliTree(intent)
+	for _, c := range root.sortedChildren() {
+		switch style {
+		case CLIStyleSet:
+			writeCLISet(sb, c, "set", r)
+		default:
+			writeCLIHierarchy(sb, c, "", r)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func writeCLIHierarchy(sb *strings.Builder, n *cliNode, indent string, r render.Renderer) {
+	if n.value != nil {
+		fmt.Fprintf(sb, "%s%s\n", indent, cliLeaf(n, r))
+	}
+	children := n.sortedChildren()
+	if len(children) == 0 {
+		if n.value == nil {
+			fmt.Fprintf(sb, "%s%s {\n%s}\n", indent, n.name, indent)
+		}
+		return
+	}
+	fmt.Fprintf(sb, "%s%s {\n", indent, n.name)
+	for _, c := range children {
+		writeCLIHierarchy(sb, c, indent+"    ", r)
+	}
+	fmt.Fprintf(sb, "%s}\n", indent)
+}
+
+func writeCLISet(sb *strings.Builder, n *cliNode, prefix string, r render.Renderer) {
+	if n.value != nil {
+		fmt.Fprintf(sb, "%s %s\n", prefix, cliLeaf(n, r))
+	}
+	children := n.sortedChildren()
+	// a list entry without other leaves than its keys is created by its name
+	if len(children) == 0 && n.value == nil {
+		fmt.Fprintf(sb, "%s %s\n", prefix, n.name)
+	}
+	for _, c := range children {
+		writeCLISet(sb, c, prefix+" "+n.name, r)
+	}
+}
+
+// cliLeaf renders the name and value of a leaf. Leaves of the empty type only
+// show their name, leaf-lists show their values in brackets.
+func cliLeaf(n *cliNode, r render.Renderer) string {
+	switch v := n.value.GetValue().(type) {
+	case *sdcpb.TypedValue_EmptyVal:
+		return n.name
+	case *sdcpb.TypedValue_LeaflistVal:
+		values := make([]string, 0, len(v.LeaflistVal.GetElement()))
+		for _, e := range v.LeaflistVal.GetElement() {
+			values = append(values, r.Value(cliQuote(e.ToString())))
+		}
+		return fmt.Sprintf("%s [ %s ]", n.name, strings.Join(values, " "))
+	default:
+		return fmt.Sprintf("%s %s", n.name, r.Value(cliQuote(n.value.ToString())))
+	}
+}
+
+// cliQuote quotes values that are empty or hold characters the CLI would split on
+func cliQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\r\"'{}[];#\\") {
+		return s
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(s) + `"`
+}
@@ -0,0 +1,100 @@
+package runningconfig
+
+import (
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+func testIntent(t *testing.T) *sdcpb.Intent {
+	t.Helper()
+	updates := []struct {
+		path  string
+		value *sdcpb.TypedValue
+	}{
+		{"/system/name/host-name", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "srl1"}}},
+		{"/interface[name=ethernet-1/1]/name", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "ethernet-1/1"}}},
+		{"/interface[name=ethernet-1/1]/mtu", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_UintVal{UintVal: 9232}}},
+		{"/interface[name=ethernet-1/1]/description", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "to spine"}}},
+		{"/interface[name=ethernet-1/1]/subinterface[index=0]/index", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_UintVal{UintVal: 0}}},
+		{"/interface[name=mgmt0]/name", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "mgmt0"}}},
+		{"/acl/acl-filter[name=f1][type=ipv4]/entry[sequence-id=10]/action/accept", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_EmptyVal{}}},
+		{"/system/dns/server-list", &sdcpb.TypedValue{Value: &sdcpb.TypedValue_LeaflistVal{LeaflistVal: &sdcpb.ScalarArray{Element: []*sdcpb.TypedValue{
+			{Value: &sdcpb.TypedValue_StringVal{StringVal: "1.1.1.1"}},
+			{Value: &sdcpb.TypedValue_StringVal{StringVal: "8.8.8.8"}},
+		}}}}},
+	}
+
+	intent := &sdcpb.Intent{}
+	for _, u := range updates {
+		p, err := sdcpb.ParsePath(u.path)
+		if err != nil {
+			t.Fatalf("ParsePath(%q) unexpected error: %v", u.path, err)
+		}
+		intent.Update = append(intent.Update, &sdcpb.Update{Path: p, Value: u.value})
+	}
+	return intent
+}
+
+func TestRenderCLI_Hierarchy(t *testing.T) {
+	got := RenderCLI(testIntent(t), CLIStyleHierarchy, render.Renderer{})
+	want := `acl {
+    acl-filter f1 ipv4 {
+        entry 10 {
+            action {
+                accept
+            }
+        }
+    }
+}
+interface ethernet-1/1 {
+    description "to spine"
+    mtu 9232
+    subinterface 0 {
+    }
+}
+interface mgmt0 {
+}
+system {
+    dns {
+        server-list [ 1.1.1.1 8.8.8.8 ]
+    }
+    name {
+        host-name srl1
+    }
+}`
+	if got != want {
+		t.Fatalf("RenderCLI() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderCLI_Set(t *testing.T) {
+	got := RenderCLI(testIntent(t), CLIStyleSet, render.Renderer{})
+	want := `set acl acl-filter f1 ipv4 entry 10 action accept
+set interface ethernet-1/1 description "to spine"
+set interface ethernet-1/1 mtu 9232
+set interface ethernet-1/1 subinterface 0
+set interface mgmt0
+set system dns server-list [ 1.1.1.1 8.8.8.8 ]
+set system name host-name srl1`
+	if got != want {
+		t.Fatalf("RenderCLI() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderCLI_Colors(t *testing.T) {
+	got := RenderCLI(testIntent(t), CLIStyleSet, render.Renderer{Color: true})
+	if want := "set system name host-name \x1b[36msrl1\x1b[0m"; got[len(got)-len(want):] != want {
+		t.Fatalf("RenderCLI() = %q, want the values in cyan", got)
+	}
+}
+
+func TestParseCLIStyle(t *testing.T) {
+	if s, err := ParseCLIStyle("Set"); err != nil || s != CLIStyleSet {
+		t.Fatalf("ParseCLIStyle() = %q, %v", s, err)
+	}
+	if _, err := ParseCLIStyle("junos"); err == nil || err.Error() != `invalid cli style "junos", must be one of: hierarchy, set` {
+		t.Fatalf("ParseCLIStyle() error = %v", err)
+	}
+}
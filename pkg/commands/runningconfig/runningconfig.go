@@ -6,18 +6,34 @@ import (
 	"strings"
 
 	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/gnmi"
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
 	corev1 "k8s.io/api/core/v1"
 )
 
 const defaultDataServicePort = 56000
 
+// RunningIntentName is the name of the intent holding the running configuration of a datastore
+const RunningIntentName = "running"
+
 // DataClient defines the subset of the data client used by runningconfig.
 type DataClient interface {
 	Connect(ctx context.Context) error
-	GetIntent(ctx context.Context, format client.Format, datastoreName, intentName string) (client.Intent, error)
+	client.IntentClient
 	Close() error
 }
 
+// FormatCLI renders the configuration the way a device CLI shows it, see RenderCLI
+const FormatCLI client.Format = "cli"
+
+// The formats writing a gNMI SetRequest replacing the selected subtree
+const (
+	FormatGNMIPrototext = client.Format(gnmi.FormatPrototext)
+	FormatGNMIJSON      = client.Format(gnmi.FormatJSON)
+	FormatGNMISetFile   = client.Format(gnmi.FormatSetFile)
+)
+
 // ValidFormats lists all supported output formats.
 var ValidFormats = []client.Format{
 	client.FormatJSON,
@@ -25,6 +41,10 @@ var ValidFormats = []client.Format{
 	client.FormatXML,
 	client.FormatXPath,
 	client.FormatYAML,
+	FormatCLI,
+	FormatGNMIPrototext,
+	FormatGNMIJSON,
+	FormatGNMISetFile,
 }
 
 // FormatListString returns a comma-separated string of valid formats.
@@ -54,6 +74,14 @@ func ParseFormat(formatStr string) (client.Format, error) {
 		return client.FormatXPath, nil
 	case client.FormatYAML:
 		return client.FormatYAML, nil
+	case FormatCLI:
+		return FormatCLI, nil
+	case FormatGNMIPrototext:
+		return FormatGNMIPrototext, nil
+	case FormatGNMIJSON:
+		return FormatGNMIJSON, nil
+	case FormatGNMISetFile:
+		return FormatGNMISetFile, nil
 	default:
 		return "", fmt.Errorf("invalid format %q, must be one of: %s", formatStr, FormatListString())
 	}
@@ -76,15 +104,68 @@ func ResolveDataServicePort(svc *corev1.Service) (int, error) {
 
 // Run connects to the data server and fetches the running configuration for the target.
 func Run(ctx context.Context, dataClient DataClient, namespace, target string, format client.Format) (string, error) {
+	if IsGNMIFormat(format) {
+		return RunSetRequest(ctx, dataClient, namespace, target, "/", format)
+	}
+	if format == FormatCLI {
+		return RunCLI(ctx, dataClient, namespace, target, CLIStyleHierarchy)
+	}
+	configOutput, err := Get(ctx, dataClient, namespace, target, format)
+	if err != nil {
+		return "", err
+	}
+
+	return configOutput.String(), nil
+}
+
+// Get connects to the data server and returns the running intent of the target in the given format.
+func Get(ctx context.Context, dataClient DataClient, namespace, target string, format client.Format) (client.Intent, error) {
 	if err := dataClient.Connect(ctx); err != nil {
-		return "", fmt.Errorf("failed to connect to data-server: %w", err)
+		return nil, fmt.Errorf("failed to connect to data-server: %w", err)
+	}
+
+	return dataClient.GetIntent(ctx, format, client.DatastoreName(namespace, target), RunningIntentName)
+}
+
+// RunCLI fetches the running configuration of the target and renders it in the cli style.
+func RunCLI(ctx context.Context, dataClient DataClient, namespace, target string, style CLIStyle) (string, error) {
+	intent, err := Get(ctx, dataClient, namespace, target, client.FormatXPath)
+	if err != nil {
+		return "", err
+	}
+	return RenderCLI(intent.GetProto(), style, render.Default()), nil
+}
+
+// IsGNMIFormat returns true if the format writes a gNMI SetRequest
+func IsGNMIFormat(format client.Format) bool {
+	return gnmi.IsFormat(string(format))
+}
+
+// SetRequest returns the gNMI SetRequest replacing the subtree at path with the
+// running configuration of the target.
+func SetRequest(ctx context.Context, dataClient DataClient, namespace, target, path string) (*gnmi.SetRequest, error) {
+	p, err := sdcpb.ParsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", path, err)
+	}
+
+	intent, err := Get(ctx, dataClient, namespace, target, client.FormatJSONIETF)
+	if err != nil {
+		return nil, err
 	}
+	return gnmi.ReplaceFromJSON(target, p, intent.GetBlob())
+}
 
-	datastoreName := fmt.Sprintf("%s.%s", namespace, target)
-	configOutput, err := dataClient.GetIntent(ctx, format, datastoreName, "running")
+// RunSetRequest returns the gNMI SetRequest replacing the subtree at path, written in the gNMI format.
+func RunSetRequest(ctx context.Context, dataClient DataClient, namespace, target, path string, format client.Format) (string, error) {
+	req, err := SetRequest(ctx, dataClient, namespace, target, path)
 	if err != nil {
 		return "", err
 	}
 
-	return configOutput.String(), nil
+	sb := &strings.Builder{}
+	if err := gnmi.Write(sb, gnmi.Format(format), []*gnmi.SetRequest{req}); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
 }
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"testing"
 
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/sdcio/kubectl-sdc/pkg/client"
 	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -42,10 +45,11 @@ func (s *stubDataClient) Close() error {
 
 type stubIntent struct {
 	value string
+	blob  []byte
 }
 
 func (s stubIntent) String() string          { return s.value }
-func (s stubIntent) GetBlob() []byte         { return nil }
+func (s stubIntent) GetBlob() []byte         { return s.blob }
 func (s stubIntent) GetProto() *sdcpb.Intent { return nil }
 func (s stubIntent) GetType() client.Format  { return client.FormatXPath }
 
@@ -58,7 +62,7 @@ func TestParseFormat(t *testing.T) {
 	}{
 		{name: "json", input: "json", want: client.FormatJSON},
 		{name: "uppercase", input: "YAML", want: client.FormatYAML},
-		{name: "invalid", input: "bogus", wantErr: `invalid format "bogus", must be one of: json, json-ietf, xml, xpath, yaml`},
+		{name: "invalid", input: "bogus", wantErr: `invalid format "bogus", must be one of: json, json-ietf, xml, xpath, yaml, cli, gnmi-prototext, gnmi-json, gnmic-setfile`},
 	}
 
 	for _, tt := range tests {
@@ -150,3 +154,61 @@ func TestRun(t *testing.T) {
 		}
 	})
 }
+
+func TestRunSetRequest(t *testing.T) {
+	blob := []byte(`{"srl_nokia-interfaces:interface":[{"name":"ethernet-1/1","mtu":9232},{"name":"ethernet-1/2","admin-state":"disable"}]}`)
+
+	t.Run("subtree", func(t *testing.T) {
+		dataClient := &stubDataClient{output: stubIntent{blob: blob}}
+		output, err := RunSetRequest(context.Background(), dataClient, "default", "srl1", "/interface[name=ethernet-1/2]", FormatGNMIPrototext)
+		if err != nil {
+			t.Fatalf("RunSetRequest() unexpected error: %v", err)
+		}
+		if dataClient.format != client.FormatJSONIETF {
+			t.Fatalf("format = %q, want %q", dataClient.format, client.FormatJSONIETF)
+		}
+		// prototext randomizes its spacing, the output is compared as a gnmi.SetRequest
+		got := &gnmipb.SetRequest{}
+		if err := prototext.Unmarshal([]byte(output), got); err != nil {
+			t.Fatalf("prototext.Unmarshal() unexpected error: %v\n%s", err, output)
+		}
+		want := &gnmipb.SetRequest{
+			Prefix: &gnmipb.Path{Target: "srl1"},
+			Replace: []*gnmipb.Update{{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "interface", Key: map[string]string{"name": "ethernet-1/2"}}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"admin-state":"disable","name":"ethernet-1/2"}`)}},
+			}},
+		}
+		if !proto.Equal(got, want) {
+			t.Fatalf("output = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		dataClient := &stubDataClient{output: stubIntent{blob: blob}}
+		_, err := RunSetRequest(context.Background(), dataClient, "default", "srl1", "/interface[name=ethernet-1/3]", FormatGNMIJSON)
+		want := "path /interface[name=ethernet-1/3]: interface[name=ethernet-1/3] not found in the configuration"
+		if err == nil || err.Error() != want {
+			t.Fatalf("RunSetRequest() error = %v, want %q", err, want)
+		}
+	})
+
+	t.Run("run replaces the whole configuration", func(t *testing.T) {
+		dataClient := &stubDataClient{output: stubIntent{blob: []byte(`{"system":{"name":{"host-name":"srl1"}}}`)}}
+		output, err := Run(context.Background(), dataClient, "default", "srl1", FormatGNMISetFile)
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+		want := `# target: srl1
+replaces:
+- encoding: json_ietf
+  path: /
+  value:
+    system:
+      name:
+        host-name: srl1`
+		if output != want {
+			t.Fatalf("output =\n%s\nwant\n%s", output, want)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// NodeKind is the YANG statement kind of a schema node
+type NodeKind string
+
+const (
+	NodeKindContainer NodeKind = "container"
+	NodeKindList      NodeKind = "list"
+	NodeKindLeaf      NodeKind = "leaf"
+	NodeKindLeafList  NodeKind = "leaf-list"
+)
+
+// Node is the description of a single schema node
+type Node struct {
+	Path        string   `json:"path"`
+	Kind        NodeKind `json:"kind"`
+	Type        string   `json:"type,omitempty"`
+	Units       string   `json:"units,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Enums       []string `json:"enums,omitempty"`
+	Keys        []string `json:"keys,omitempty"`
+	Mandatory   []string `json:"mandatory,omitempty"`
+	IsMandatory bool     `json:"isMandatory"`
+	IsState     bool     `json:"isState"`
+	// children of a container or list, sorted, including keys, leaves and leaf-lists
+	Children []string `json:"children,omitempty"`
+}
+
+// Show returns the schema node of the path
+func Show(ctx context.Context, cl SchemaClient, schema *sdcpb.Schema, path string) (*Node, error) {
+	p, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	elem, err := cl.GetSchemaElem(ctx, schema, p)
+	if err != nil {
+		return nil, err
+	}
+	return newNode(p.ToXPath(true), elem), nil
+}
+
+func newNode(path string, elem *sdcpb.SchemaElem) *Node {
+	n := &Node{Path: path}
+	switch {
+	case elem.GetContainer() != nil:
+		c := elem.GetContainer()
+		n.Kind = NodeKindContainer
+		if len(c.GetKeys()) > 0 {
+			n.Kind = NodeKindList
+		}
+		n.Description = c.GetDescription()
+		n.IsState = c.GetIsState()
+		for _, k := range c.GetKeys() {
+			n.Keys = append(n.Keys, k.GetName())
+		}
+		for _, m := range c.GetMandatoryChildrenConfig() {
+			n.Mandatory = append(n.Mandatory, m.GetName())
+		}
+		n.Children = childNames(c)
+	case elem.GetField() != nil:
+		f := elem.GetField()
+		n.Kind = NodeKindLeaf
+		n.Description = f.GetDescription()
+		n.Default = f.GetDefault()
+		n.IsMandatory = f.GetIsMandatory()
+		n.IsState = f.GetIsState()
+		n.Units = f.GetUnits()
+		n.Type, n.Enums = leafType(f.GetType())
+	case elem.GetLeaflist() != nil:
+		l := elem.GetLeaflist()
+		n.Kind = NodeKindLeafList
+		n.Description = l.GetDescription()
+		n.Default = strings.Join(l.GetDefaults(), ", ")
+		n.IsState = l.GetIsState()
+		n.Units = l.GetUnits()
+		n.Type, n.Enums = leafType(l.GetType())
+	}
+	return n
+}
+
+// childNames returns the sorted names of all the children of a container
+func childNames(c *sdcpb.ContainerSchema) []string {
+	names := make([]string, 0, len(c.GetKeys())+len(c.GetFields())+len(c.GetLeaflists())+len(c.GetChildren()))
+	for _, k := range c.GetKeys() {
+		names = append(names, k.GetName())
+	}
+	for _, f := range c.GetFields() {
+		names = append(names, f.GetName())
+	}
+	for _, l := range c.GetLeaflists() {
+		names = append(names, l.GetName())
+	}
+	names = append(names, c.GetChildren()...)
+	return sortedUnique(names)
+}
+
+// leafType renders the type of a leaf, naming the typedef if any, and its enum values
+func leafType(t *sdcpb.SchemaLeafType) (string, []string) {
+	typ := t.GetType()
+	if t.GetTypeName() != "" && t.GetTypeName() != typ {
+		typ = fmt.Sprintf("%s (%s)", t.GetTypeName(), typ)
+	}
+	if len(t.GetUnionTypes()) > 0 {
+		members := make([]string, 0, len(t.GetUnionTypes()))
+		for _, u := range t.GetUnionTypes() {
+			members = append(members, u.GetType())
+		}
+		typ = fmt.Sprintf("%s [%s]", typ, strings.Join(members, " | "))
+	}
+	return typ, t.GetEnumNames()
+}
+
+// WriteNode writes the details of a schema node
+func WriteNode(out io.Writer, n *Node) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fields := [][2]string{
+		{"Path:", n.Path},
+		{"Kind:", string(n.Kind)},
+	}
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{label, value})
+		}
+	}
+	add("Type:", n.Type)
+	add("Units:", n.Units)
+	add("Default:", n.Default)
+	add("Enums:", strings.Join(n.Enums, ", "))
+	add("Keys:", strings.Join(n.Keys, ", "))
+	if n.Kind == NodeKindLeaf {
+		add("Mandatory:", fmt.Sprint(n.IsMandatory))
+	}
+	add("Mandatory Children:", strings.Join(n.Mandatory, ", "))
+	if n.IsState {
+		add("State:", "true")
+	}
+	add("Children:", strings.Join(n.Children, ", "))
+	add("Description:", strings.Join(strings.Fields(n.Description), " "))
+
+	for _, f := range fields {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", f[0], f[1])
+	}
+	return w.Flush()
+}
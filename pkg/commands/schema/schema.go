@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+var ErrSchemaNotSet = errors.New("either a target or a vendor and version must be set")
+
+// SchemaClient defines the schema-server operations used by the schema commands
+type SchemaClient interface {
+	ListSchemas(ctx context.Context) ([]*sdcpb.Schema, error)
+	GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error)
+}
+
+// TargetClient resolves the schema of a target
+type TargetClient interface {
+	GetTargetSchema(ctx context.Context, namespace string, targetName string) (*sdcpb.Schema, error)
+}
+
+// ResolveSchema returns the schema of the target if set, otherwise the schema of vendor and version
+func ResolveSchema(ctx context.Context, cl TargetClient, namespace, target, vendor, version string) (*sdcpb.Schema, error) {
+	if target != "" {
+		return cl.GetTargetSchema(ctx, namespace, target)
+	}
+	if vendor == "" || version == "" {
+		return nil, ErrSchemaNotSet
+	}
+	return &sdcpb.Schema{Vendor: vendor, Version: version}, nil
+}
+
+// List returns the schemas of the schema-server sorted by vendor and version
+func List(ctx context.Context, cl SchemaClient) ([]*sdcpb.Schema, error) {
+	schemas, err := cl.ListSchemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].GetVendor() != schemas[j].GetVendor() {
+			return schemas[i].GetVendor() < schemas[j].GetVendor()
+		}
+		return schemas[i].GetVersion() < schemas[j].GetVersion()
+	})
+	return schemas, nil
+}
+
+// WriteList writes the schemas as a table
+func WriteList(out io.Writer, schemas []*sdcpb.Schema) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VENDOR\tVERSION\tSTATUS")
+	for _, s := range schemas {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", s.GetVendor(), s.GetVersion(), s.GetStatus())
+	}
+	return w.Flush()
+}
+
+// parsePath parses an XPath, keys are accepted but irrelevant for the schema
+func parsePath(p string) (*sdcpb.Path, error) {
+	if p == "" {
+		p = "/"
+	}
+	path, err := sdcpb.ParsePath(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path %q: %w", p, err)
+	}
+	path.IsRootBased = true
+	return path, nil
+}
@@ -0,0 +1,205 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	mockschema "github.com/sdcio/kubectl-sdc/mocks/schema"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+)
+
+var testSchema = &sdcpb.Schema{Vendor: "nokia", Version: "24.10"}
+
+func containerElem(c *sdcpb.ContainerSchema) *sdcpb.SchemaElem {
+	return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Container{Container: c}}
+}
+
+func leafElem(l *sdcpb.LeafSchema) *sdcpb.SchemaElem {
+	return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: l}}
+}
+
+// expectSchema serves the schema elements by key-less XPath
+func expectSchema(cl *mockschema.MockSchemaClient, elems map[string]*sdcpb.SchemaElem) {
+	cl.EXPECT().GetSchemaElem(gomock.Any(), testSchema, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *sdcpb.Schema, p *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+			elem, ok := elems[p.ToXPath(true)]
+			if !ok {
+				return nil, errors.New("unknown element")
+			}
+			return elem, nil
+		}).AnyTimes()
+}
+
+func TestResolveSchema(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	targets := mockschema.NewMockTargetClient(ctrl)
+	targets.EXPECT().GetTargetSchema(gomock.Any(), "default", "srl1").Return(testSchema, nil)
+
+	got, err := ResolveSchema(context.Background(), targets, "default", "srl1", "", "")
+	if err != nil || got != testSchema {
+		t.Fatalf("ResolveSchema() = %v, %v, want %v", got, err, testSchema)
+	}
+
+	got, err = ResolveSchema(context.Background(), targets, "default", "", "arista", "4.33")
+	if err != nil || got.GetVendor() != "arista" || got.GetVersion() != "4.33" {
+		t.Fatalf("ResolveSchema() = %v, %v, want arista 4.33", got, err)
+	}
+
+	if _, err := ResolveSchema(context.Background(), targets, "default", "", "arista", ""); !errors.Is(err, ErrSchemaNotSet) {
+		t.Fatalf("ResolveSchema() error = %v, want %v", err, ErrSchemaNotSet)
+	}
+}
+
+func TestListSorted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mockschema.NewMockSchemaClient(ctrl)
+	cl.EXPECT().ListSchemas(gomock.Any()).Return([]*sdcpb.Schema{
+		{Vendor: "nokia", Version: "24.10", Status: sdcpb.SchemaStatus_OK},
+		{Vendor: "arista", Version: "4.33", Status: sdcpb.SchemaStatus_OK},
+		{Vendor: "nokia", Version: "23.10", Status: sdcpb.SchemaStatus_RELOADING},
+	}, nil)
+
+	schemas, err := List(context.Background(), cl)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := WriteList(out, schemas); err != nil {
+		t.Fatalf("WriteList() error = %v", err)
+	}
+	want := `VENDOR   VERSION   STATUS
+arista   4.33      OK
+nokia    23.10     RELOADING
+nokia    24.10     OK
+`
+	if out.String() != want {
+		t.Fatalf("WriteList() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestShow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mockschema.NewMockSchemaClient(ctrl)
+	expectSchema(cl, map[string]*sdcpb.SchemaElem{
+		"/interface": containerElem(&sdcpb.ContainerSchema{
+			Name:              "interface",
+			Description:       "The list of interfaces\n  on the device",
+			Keys:              []*sdcpb.LeafSchema{{Name: "name", Type: &sdcpb.SchemaLeafType{Type: "string"}}},
+			Fields:            []*sdcpb.LeafSchema{{Name: "admin-state"}, {Name: "mtu"}},
+			Children:          []string{"subinterface"},
+			MandatoryChildren: []*sdcpb.MandatoryChild{{Name: "admin-state"}},
+		}),
+		"/interface/admin-state": leafElem(&sdcpb.LeafSchema{
+			Name:        "admin-state",
+			Description: "The administrative state",
+			Default:     "enable",
+			IsMandatory: true,
+			Type:        &sdcpb.SchemaLeafType{Type: "enumeration", TypeName: "admin-state", EnumNames: []string{"enable", "disable"}},
+		}),
+	})
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "list",
+			path: "/interface[name=ethernet-1/1]",
+			want: `Path:                /interface
+Kind:                list
+Keys:                name
+Mandatory Children:  admin-state
+Children:            admin-state, mtu, name, subinterface
+Description:         The list of interfaces on the device
+`,
+		},
+		{
+			name: "leaf",
+			path: "/interface/admin-state",
+			want: `Path:         /interface/admin-state
+Kind:         leaf
+Type:         admin-state (enumeration)
+Default:      enable
+Enums:        enable, disable
+Mandatory:    true
+Description:  The administrative state
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Show(context.Background(), cl, testSchema, tt.path)
+			if err != nil {
+				t.Fatalf("Show() error = %v", err)
+			}
+			out := &bytes.Buffer{}
+			if err := WriteNode(out, node); err != nil {
+				t.Fatalf("WriteNode() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Fatalf("WriteNode() =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+
+	if _, err := Show(context.Background(), cl, testSchema, "/unknown"); err == nil {
+		t.Fatal("Show() expected error for unknown path")
+	}
+}
+
+func TestTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cl := mockschema.NewMockSchemaClient(ctrl)
+	str := &sdcpb.SchemaLeafType{Type: "string"}
+	expectSchema(cl, map[string]*sdcpb.SchemaElem{
+		"/": containerElem(&sdcpb.ContainerSchema{Children: []string{"system", "interface"}}),
+		"/interface": containerElem(&sdcpb.ContainerSchema{
+			Keys:     []*sdcpb.LeafSchema{{Name: "name", Type: str}},
+			Fields:   []*sdcpb.LeafSchema{{Name: "mtu", Type: &sdcpb.SchemaLeafType{Type: "uint16"}}},
+			Children: []string{"subinterface"},
+		}),
+		"/interface/subinterface": containerElem(&sdcpb.ContainerSchema{
+			Keys:     []*sdcpb.LeafSchema{{Name: "index", Type: &sdcpb.SchemaLeafType{Type: "uint32"}}},
+			Children: []string{"ipv4"},
+		}),
+		"/system": containerElem(&sdcpb.ContainerSchema{
+			Leaflists: []*sdcpb.LeafListSchema{{Name: "dns-server", Type: str}},
+			Fields:    []*sdcpb.LeafSchema{{Name: "name", Type: str}},
+		}),
+	})
+
+	tree, err := Tree(context.Background(), cl, testSchema, "/", 2)
+	if err != nil {
+		t.Fatalf("Tree() error = %v", err)
+	}
+	want := `📦 /
+├── 📦 interface [🔑 name]
+│   ├── 🍃 mtu: uint16
+│   ├── 🍃 name: string
+│   └── 📦 subinterface [🔑 index] ...
+└── 📦 system
+    ├── 🍃 dns-server[]: string
+    └── 🍃 name: string`
+	if got := tree.String(); got != want {
+		t.Fatalf("Tree() =\n%s\nwant\n%s", got, want)
+	}
+
+	tree, err = Tree(context.Background(), cl, testSchema, "/system", 0)
+	if err != nil {
+		t.Fatalf("Tree() error = %v", err)
+	}
+	want = `📦 /system
+├── 🍃 dns-server[]: string
+└── 🍃 name: string`
+	if got := tree.String(); got != want {
+		t.Fatalf("Tree() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := Tree(context.Background(), cl, testSchema, "/interface", 0); err == nil {
+		t.Fatal("Tree() expected error for unknown child")
+	}
+}
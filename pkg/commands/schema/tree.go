@@ -0,0 +1,158 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdcio/kubectl-sdc/pkg/render"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// TreeNode is a schema node with its children expanded
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Kind     NodeKind    `json:"kind"`
+	Type     string      `json:"type,omitempty"`
+	Keys     []string    `json:"keys,omitempty"`
+	Children []*TreeNode `json:"children,omitempty"`
+	// Truncated is set when the children were not expanded due to the depth limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Tree expands the children of the path up to depth levels, a depth of 0 expands everything
+func Tree(ctx context.Context, cl SchemaClient, schema *sdcpb.Schema, path string, depth int) (*TreeNode, error) {
+	p, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	elem, err := cl.GetSchemaElem(ctx, schema, p)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "/"
+	if len(p.GetElem()) > 0 {
+		name = p.ToXPath(true)
+	}
+	if depth == 0 {
+		depth = -1
+	}
+	return expand(ctx, cl, schema, p, name, elem, depth)
+}
+
+// expand builds the tree node of elem, expanding depth levels of children, or all of them if depth is negative
+func expand(ctx context.Context, cl SchemaClient, schema *sdcpb.Schema, path *sdcpb.Path, name string, elem *sdcpb.SchemaElem, depth int) (*TreeNode, error) {
+	n := newNode(path.ToXPath(true), elem)
+	tn := &TreeNode{Name: name, Kind: n.Kind, Type: n.Type, Keys: n.Keys}
+
+	c := elem.GetContainer()
+	if c == nil {
+		return tn, nil
+	}
+	if depth == 0 {
+		tn.Truncated = len(n.Children) > 0
+		return tn, nil
+	}
+
+	// keys, leaves and leaf-lists are part of the container schema
+	for _, f := range append(append([]*sdcpb.LeafSchema{}, c.GetKeys()...), c.GetFields()...) {
+		typ, _ := leafType(f.GetType())
+		tn.Children = append(tn.Children, &TreeNode{Name: f.GetName(), Kind: NodeKindLeaf, Type: typ})
+	}
+	for _, l := range c.GetLeaflists() {
+		typ, _ := leafType(l.GetType())
+		tn.Children = append(tn.Children, &TreeNode{Name: l.GetName(), Kind: NodeKindLeafList, Type: typ})
+	}
+	for _, child := range c.GetChildren() {
+		childPath := path.CopyPathAddElem(sdcpb.NewPathElem(child, nil))
+		childElem, err := cl.GetSchemaElem(ctx, schema, childPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", childPath.ToXPath(true), err)
+		}
+		childNode, err := expand(ctx, cl, schema, childPath, child, childElem, max(depth-1, -1))
+		if err != nil {
+			return nil, err
+		}
+		tn.Children = append(tn.Children, childNode)
+	}
+
+	tn.Children = uniqueChildren(tn.Children)
+	sort.Slice(tn.Children, func(i, j int) bool { return tn.Children[i].Name < tn.Children[j].Name })
+	return tn, nil
+}
+
+// String renders the tree with the same connectors as the blame tree
+func (t *TreeNode) String() string {
+	return t.Render(render.Renderer{Style: render.UnicodeTree})
+}
+
+// Render renders the tree in the style of the renderer
+func (t *TreeNode) Render(r render.Renderer) string {
+	sb := &strings.Builder{}
+	t.write(sb, r.Style, "", true, true)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (t *TreeNode) write(sb *strings.Builder, style render.TreeStyle, prefix string, isLast, isRoot bool) {
+	connector, nextPrefix := style.Connector(prefix, isLast)
+	if isRoot {
+		connector = ""
+		nextPrefix = prefix
+	}
+
+	sb.WriteString(prefix)
+	sb.WriteString(connector)
+	sb.WriteString(t.label(style))
+	sb.WriteString("\n")
+
+	for i, c := range t.Children {
+		c.write(sb, style, nextPrefix, i == len(t.Children)-1, false)
+	}
+}
+
+func (t *TreeNode) label(style render.TreeStyle) string {
+	switch t.Kind {
+	case NodeKindList:
+		return fmt.Sprintf("%s%s [%s%s]%s", style.Container, t.Name, style.Key, strings.Join(t.Keys, ","), t.more())
+	case NodeKindLeaf:
+		return fmt.Sprintf("%s%s: %s", style.Leaf, t.Name, t.Type)
+	case NodeKindLeafList:
+		return fmt.Sprintf("%s%s[]: %s", style.Leaf, t.Name, t.Type)
+	default:
+		return fmt.Sprintf("%s%s%s", style.Container, t.Name, t.more())
+	}
+}
+
+func (t *TreeNode) more() string {
+	if t.Truncated {
+		return " ..."
+	}
+	return ""
+}
+
+func uniqueChildren(children []*TreeNode) []*TreeNode {
+	seen := map[string]struct{}{}
+	result := children[:0]
+	for _, c := range children {
+		if _, ok := seen[c.Name]; ok {
+			continue
+		}
+		seen[c.Name] = struct{}{}
+		result = append(result, c)
+	}
+	return result
+}
+
+func sortedUnique(names []string) []string {
+	sort.Strings(names)
+	result := names[:0]
+	for i, n := range names {
+		if i > 0 && names[i-1] == n {
+			continue
+		}
+		result = append(result, n)
+	}
+	return result
+}
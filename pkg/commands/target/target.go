@@ -0,0 +1,234 @@
+package target
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	condv1alpha1 "github.com/sdcio/config-server/apis/condition/v1alpha1"
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TargetClient defines the interface for target operations
+type TargetClient interface {
+	ListTargets(ctx context.Context, namespace string) ([]v1alpha1.Target, error)
+	GetTarget(ctx context.Context, namespace string, targetName string) (*v1alpha1.Target, error)
+	ListConfigs(ctx context.Context, namespace string, labels map[string]string) ([]v1alpha1.Config, error)
+	ListDeviations(ctx context.Context, namespace string) ([]v1alpha1.Deviation, error)
+	GetDeviationsByTarget(ctx context.Context, namespace string, targetName string) (types.Deviations, error)
+}
+
+// OutputFormat is the output format of the target commands
+type OutputFormat string
+
+const (
+	OutputFormatTable OutputFormat = ""
+	OutputFormatWide  OutputFormat = "wide"
+)
+
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(s)); f {
+	case OutputFormatTable, OutputFormatWide:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid output format %q, must be one of: wide", s)
+	}
+}
+
+// Summary is the status overview of a target
+type Summary struct {
+	Namespace         string             `json:"namespace"`
+	Name              string             `json:"name"`
+	Address           string             `json:"address"`
+	Provider          string             `json:"provider"`
+	Ready             string             `json:"ready"`
+	Connection        string             `json:"connection"`
+	Sync              string             `json:"sync"`
+	SchemaVendor      string             `json:"schemaVendor,omitempty"`
+	SchemaVersion     string             `json:"schemaVersion,omitempty"`
+	ConnectionProfile string             `json:"connectionProfile"`
+	SyncProfile       string             `json:"syncProfile,omitempty"`
+	Configs           int                `json:"configs"`
+	ConfigsReady      int                `json:"configsReady"`
+	Deviations        int                `json:"deviations"`
+	Conditions        []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Schema returns the schema as vendor/version, "-" if not discovered yet
+func (s *Summary) Schema() string {
+	if s.SchemaVendor == "" {
+		return "-"
+	}
+	return s.SchemaVendor + "/" + s.SchemaVersion
+}
+
+// List returns the summary of all targets of the namespace, sorted by name.
+// Deviations without a target label are skipped with a warning on errOut.
+func List(ctx context.Context, cl TargetClient, namespace string, errOut io.Writer) ([]*Summary, error) {
+	targets, err := cl.ListTargets(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := cl.ListConfigs(ctx, namespace, nil)
+	if err != nil {
+		return nil, err
+	}
+	deviations, err := cl.ListDeviations(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	devs := types.Deviations{}
+	for i := range deviations {
+		d := &deviations[i]
+		if _, ok := d.GetLabels()[client.TargetLabel]; !ok {
+			_, _ = fmt.Fprintf(errOut, "warning: skipping deviation %s, it is missing the target label %q\n", d.GetName(), client.TargetLabel)
+			continue
+		}
+		intentDev, err := client.ConvertDeviationIntent(d)
+		if err != nil {
+			return nil, err
+		}
+		devs.AddDeviation(intentDev)
+	}
+
+	configsByTarget := map[string][]v1alpha1.Config{}
+	for _, c := range configs {
+		t := c.GetLabels()[client.TargetLabel]
+		configsByTarget[t] = append(configsByTarget[t], c)
+	}
+	devsByTarget := devs.ByTarget()
+
+	result := make([]*Summary, 0, len(targets))
+	for i := range targets {
+		t := &targets[i]
+		key := types.TargetKey{Namespace: t.Namespace, Target: t.Name}
+		result = append(result, newSummary(t, configsByTarget[t.Name], devsByTarget[key]))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// Describe returns the summary of a single target
+func Describe(ctx context.Context, cl TargetClient, namespace, name string) (*Summary, error) {
+	t, err := cl.GetTarget(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	configs, err := cl.ListConfigs(ctx, namespace, map[string]string{client.TargetLabel: name})
+	if err != nil {
+		return nil, err
+	}
+	devs, err := cl.GetDeviationsByTarget(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return newSummary(t, configs, devs), nil
+}
+
+func newSummary(t *v1alpha1.Target, configs []v1alpha1.Config, devs types.Deviations) *Summary {
+	s := &Summary{
+		Namespace:         t.Namespace,
+		Name:              t.Name,
+		Address:           t.Spec.Address,
+		Provider:          t.Spec.Provider,
+		Ready:             conditionState(t.GetCondition(condv1alpha1.ConditionTypeReady)),
+		Connection:        conditionState(t.GetCondition(v1alpha1.ConditionTypeTargetConnectionReady)),
+		Sync:              conditionState(t.GetCondition(v1alpha1.ConditionTypeTargetDatastoreReady)),
+		ConnectionProfile: t.Spec.ConnectionProfile,
+		Configs:           len(configs),
+		Deviations:        len(devs.Items()),
+	}
+	if t.Spec.SyncProfile != nil {
+		s.SyncProfile = *t.Spec.SyncProfile
+	}
+	if info := t.Status.DiscoveryInfo; info != nil {
+		s.SchemaVendor = info.Provider
+		s.SchemaVersion = info.Version
+	}
+	for _, c := range configs {
+		if c.IsConditionReady() {
+			s.ConfigsReady++
+		}
+	}
+	for _, c := range t.Status.Conditions {
+		s.Conditions = append(s.Conditions, c.Condition)
+	}
+	sort.Slice(s.Conditions, func(i, j int) bool { return s.Conditions[i].Type < s.Conditions[j].Type })
+	return s
+}
+
+// conditionState renders a condition as True, or as its reason when it is not true
+func conditionState(c condv1alpha1.Condition) string {
+	switch {
+	case c.IsTrue():
+		return string(metav1.ConditionTrue)
+	case c.Reason != "":
+		return c.Reason
+	default:
+		return string(c.Status)
+	}
+}
+
+// WriteList writes the target summaries in the given format
+func WriteList(out io.Writer, summaries []*Summary, format OutputFormat) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	header := []string{"NAME", "READY", "CONNECTION", "SYNC", "SCHEMA", "CONFIGS", "DEVIATIONS"}
+	if format == OutputFormatWide {
+		header = append(header, "ADDRESS", "PROVIDER", "CONNECTION-PROFILE", "SYNC-PROFILE")
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, s := range summaries {
+		row := []string{s.Name, s.Ready, s.Connection, s.Sync, s.Schema(), fmt.Sprintf("%d/%d", s.ConfigsReady, s.Configs), fmt.Sprint(s.Deviations)}
+		if format == OutputFormatWide {
+			row = append(row, s.Address, s.Provider, orNone(s.ConnectionProfile), orNone(s.SyncProfile))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// WriteDescribe writes the details of a target in the given format
+func WriteDescribe(out io.Writer, s *Summary, format OutputFormat) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fields := [][2]string{
+		{"Name:", s.Name},
+		{"Namespace:", s.Namespace},
+		{"Address:", s.Address},
+		{"Provider:", s.Provider},
+		{"Ready:", s.Ready},
+		{"Connection:", s.Connection},
+		{"Sync:", s.Sync},
+		{"Schema:", s.Schema()},
+		{"Connection Profile:", orNone(s.ConnectionProfile)},
+		{"Sync Profile:", orNone(s.SyncProfile)},
+		{"Configs:", fmt.Sprintf("%d (%d ready)", s.Configs, s.ConfigsReady)},
+		{"Deviations:", fmt.Sprint(s.Deviations)},
+	}
+	for _, f := range fields {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", f[0], f[1])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out, "Conditions:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, c := range s.Conditions {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+	}
+	return w.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,157 @@
+package target
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	condv1alpha1 "github.com/sdcio/config-server/apis/condition/v1alpha1"
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	invv1alpha1 "github.com/sdcio/config-server/apis/inv/v1alpha1"
+	mocktarget "github.com/sdcio/kubectl-sdc/mocks/target"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestTarget(name string, ready bool) v1alpha1.Target {
+	syncProfile := "gnmi-get"
+	t := v1alpha1.Target{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: v1alpha1.TargetSpec{
+			Provider: "srl.nokia.sdcio.dev",
+			Address:  "172.21.0.2:57400",
+			TargetProfile: invv1alpha1.TargetProfile{
+				ConnectionProfile: "gnmi-skipverify",
+				SyncProfile:       &syncProfile,
+			},
+		},
+	}
+	if ready {
+		t.Status.DiscoveryInfo = &v1alpha1.DiscoveryInfo{Provider: "srl.nokia.sdcio.dev", Version: "24.10.1"}
+		t.SetConditions(condv1alpha1.Ready(), v1alpha1.TargetConnectionReady(), v1alpha1.TargetDatastoreReady())
+	} else {
+		t.SetConditions(condv1alpha1.Failed("discovery not ready"), v1alpha1.TargetConnectionFailed("connection refused"))
+	}
+	return t
+}
+
+func newTestConfig(name, target string, ready bool) v1alpha1.Config {
+	c := v1alpha1.Config{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{client.TargetLabel: target}}}
+	if ready {
+		c.SetConditions(condv1alpha1.Ready())
+	}
+	return c
+}
+
+func newTestDeviations(target string, paths ...string) types.Deviations {
+	intent := types.NewDeviations(target, "dev-"+target, types.DeviationTypeConfig, len(paths)).SetNamespace("default")
+	for _, p := range paths {
+		intent.AddDeviation(types.NewDeviation(p, "a", "b", "NOT_APPLIED"))
+	}
+	devs := types.Deviations{}
+	devs.AddDeviation(intent)
+	return devs
+}
+
+func newTestDeviationResource(name string, labels map[string]string, paths ...string) v1alpha1.Deviation {
+	dt := v1alpha1.DeviationType_CONFIG
+	d := v1alpha1.Deviation{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec:       v1alpha1.DeviationSpec{DeviationType: &dt},
+	}
+	for _, p := range paths {
+		d.Spec.Deviations = append(d.Spec.Deviations, v1alpha1.ConfigDeviation{Path: p, Reason: "NOT_APPLIED"})
+	}
+	return d
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cl := mocktarget.NewMockTargetClient(ctrl)
+	cl.EXPECT().ListTargets(gomock.Any(), "default").Return([]v1alpha1.Target{newTestTarget("srl2", false), newTestTarget("srl1", true)}, nil)
+	cl.EXPECT().ListConfigs(gomock.Any(), "default", nil).Return([]v1alpha1.Config{
+		newTestConfig("a", "srl1", true),
+		newTestConfig("b", "srl1", false),
+		newTestConfig("c", "srl2", false),
+	}, nil)
+	cl.EXPECT().ListDeviations(gomock.Any(), "default").Return([]v1alpha1.Deviation{
+		newTestDeviationResource("dev-srl1", map[string]string{client.TargetLabel: "srl1"}, "/system/name", "/interface"),
+		newTestDeviationResource("dev-orphan", nil, "/system/name"),
+	}, nil)
+
+	errOut := &bytes.Buffer{}
+	summaries, err := List(context.Background(), cl, "default", errOut)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if !strings.Contains(errOut.String(), "warning: skipping deviation dev-orphan") {
+		t.Fatalf("List() did not warn about the untargeted deviation: %q", errOut.String())
+	}
+
+	out := &bytes.Buffer{}
+	if err := WriteList(out, summaries, OutputFormatTable); err != nil {
+		t.Fatalf("WriteList() error = %v", err)
+	}
+	want := strings.Join([]string{
+		"NAME   READY    CONNECTION   SYNC    SCHEMA                        CONFIGS   DEVIATIONS",
+		"srl1   True     True         True    srl.nokia.sdcio.dev/24.10.1   1/2       2",
+		"srl2   Failed   Failed       False   -                             0/1       0",
+	}, "\n")
+	if got := strings.TrimRight(out.String(), "\n"); got != want {
+		t.Fatalf("WriteList() =\n%s\nwant:\n%s", got, want)
+	}
+
+	out.Reset()
+	if err := WriteList(out, summaries, OutputFormatWide); err != nil {
+		t.Fatalf("WriteList() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "CONNECTION-PROFILE") || !strings.Contains(out.String(), "gnmi-skipverify") {
+		t.Fatalf("wide output misses the profiles:\n%s", out.String())
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cl := mocktarget.NewMockTargetClient(ctrl)
+	tgt := newTestTarget("srl1", true)
+	cl.EXPECT().GetTarget(gomock.Any(), "default", "srl1").Return(&tgt, nil)
+	cl.EXPECT().ListConfigs(gomock.Any(), "default", map[string]string{client.TargetLabel: "srl1"}).Return([]v1alpha1.Config{newTestConfig("a", "srl1", true)}, nil)
+	cl.EXPECT().GetDeviationsByTarget(gomock.Any(), "default", "srl1").Return(newTestDeviations("srl1", "/system/name"), nil)
+
+	summary, err := Describe(context.Background(), cl, "default", "srl1")
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	if summary.SchemaVersion != "24.10.1" || summary.SyncProfile != "gnmi-get" || summary.Configs != 1 || summary.ConfigsReady != 1 || summary.Deviations != 1 || len(summary.Conditions) != 3 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	out := &bytes.Buffer{}
+	if err := WriteDescribe(out, summary, OutputFormatTable); err != nil {
+		t.Fatalf("WriteDescribe() error = %v", err)
+	}
+	for _, want := range []string{"Schema:              srl.nokia.sdcio.dev/24.10.1", "Configs:             1 (1 ready)", "TargetConnectionReady"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("describe output misses %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseOutputFormat("yaml"); err == nil || err.Error() != `invalid output format "yaml", must be one of: wide` {
+		t.Fatalf("ParseOutputFormat() error = %v", err)
+	}
+	if f, err := ParseOutputFormat("WIDE"); err != nil || f != OutputFormatWide {
+		t.Fatalf("ParseOutputFormat() = %q, %v", f, err)
+	}
+}
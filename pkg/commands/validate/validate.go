@@ -0,0 +1,326 @@
+package validate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"gopkg.in/yaml.v3"
+)
+
+// TargetClient resolves the schema of a target.
+type TargetClient interface {
+	GetTargetSchema(ctx context.Context, namespace string, targetName string) (*sdcpb.Schema, error)
+}
+
+// SchemaClient looks up schema elements on the schema-server.
+type SchemaClient interface {
+	GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error)
+}
+
+// Finding is a validation error at a position in a manifest.
+type Finding struct {
+	Source  string
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s:%d:%d: %s", f.Source, f.Line, f.Column, f.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", f.Source, f.Line, f.Column, f.Path, f.Message)
+}
+
+// Validator checks the paths and values of Config manifests against the schema of
+// their target. Target schemas and schema elements are cached across documents.
+type Validator struct {
+	targets TargetClient
+	schemas SchemaClient
+
+	targetSchemas map[string]*sdcpb.Schema
+	elems         map[string]schemaLookup
+}
+
+type schemaLookup struct {
+	elem *sdcpb.SchemaElem
+	err  error
+}
+
+func NewValidator(targets TargetClient, schemas SchemaClient) *Validator {
+	return &Validator{
+		targets:       targets,
+		schemas:       schemas,
+		targetSchemas: map[string]*sdcpb.Schema{},
+		elems:         map[string]schemaLookup{},
+	}
+}
+
+// ValidateDocuments validates every Config document of a YAML or JSON manifest. Other
+// kinds are skipped. Manifests without a namespace use the given namespace.
+func (v *Validator) ValidateDocuments(ctx context.Context, namespace, source string, data []byte) ([]Finding, error) {
+	findings := []Finding{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decoding document: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		if scalar(lookup(root, "kind")) != v1alpha1.ConfigKind || !strings.HasPrefix(scalar(lookup(root, "apiVersion")), v1alpha1.Group+"/") {
+			continue
+		}
+		dv := &documentValidator{Validator: v, source: source}
+		if err := dv.validateConfig(ctx, namespace, root); err != nil {
+			return nil, err
+		}
+		findings = append(findings, dv.findings...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Line != findings[j].Line {
+			return findings[i].Line < findings[j].Line
+		}
+		return findings[i].Column < findings[j].Column
+	})
+	return findings, nil
+}
+
+func (v *Validator) targetSchema(ctx context.Context, namespace, target string) (*sdcpb.Schema, error) {
+	key := namespace + "/" + target
+	if s, ok := v.targetSchemas[key]; ok {
+		return s, nil
+	}
+	s, err := v.targets.GetTargetSchema(ctx, namespace, target)
+	if err != nil {
+		return nil, err
+	}
+	v.targetSchemas[key] = s
+	return s, nil
+}
+
+// schemaElem looks up the schema element of a path, keys are not relevant for the lookup.
+func (v *Validator) schemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+	key := fmt.Sprintf("%s/%s/%s", schema.GetVendor(), schema.GetVersion(), path.ToXPath(true))
+	if l, ok := v.elems[key]; ok {
+		return l.elem, l.err
+	}
+	elem, err := v.schemas.GetSchemaElem(ctx, schema, path)
+	v.elems[key] = schemaLookup{elem: elem, err: err}
+	return elem, err
+}
+
+// documentValidator collects the findings of a single document.
+type documentValidator struct {
+	*Validator
+	source   string
+	schema   *sdcpb.Schema
+	findings []Finding
+}
+
+func (d *documentValidator) report(node *yaml.Node, path *sdcpb.Path, format string, args ...any) {
+	f := Finding{Source: d.source, Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, args...)}
+	if path != nil {
+		f.Path = path.ToXPath(false)
+	}
+	d.findings = append(d.findings, f)
+}
+
+func (d *documentValidator) validateConfig(ctx context.Context, namespace string, root *yaml.Node) error {
+	metadata := lookup(root, "metadata")
+	if ns := scalar(lookup(metadata, "namespace")); ns != "" {
+		namespace = ns
+	}
+
+	targetNode := lookup(lookup(metadata, "labels"), client.TargetLabel)
+	if scalar(targetNode) == "" {
+		d.report(nodeOr(metadata, root), nil, "missing the target label %q", client.TargetLabel)
+		return nil
+	}
+
+	schema, err := d.targetSchema(ctx, namespace, scalar(targetNode))
+	if err != nil {
+		d.report(targetNode, nil, "resolving the schema of target %s: %v", scalar(targetNode), err)
+		return nil
+	}
+	d.schema = schema
+
+	blobs := lookup(lookup(root, "spec"), "config")
+	if blobs == nil || blobs.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, blob := range blobs.Content {
+		pathNode := lookup(blob, "path")
+		path, err := sdcpb.ParsePath(scalar(pathNode))
+		if err != nil {
+			d.report(nodeOr(pathNode, blob), nil, "invalid path %q: %v", scalar(pathNode), err)
+			continue
+		}
+		path.IsRootBased = true
+
+		valueNode := lookup(blob, "value")
+		if valueNode == nil {
+			continue
+		}
+		if len(path.GetElem()) == 0 {
+			d.validateContainer(ctx, valueNode, path, nil)
+			continue
+		}
+		elem, err := d.schemaElem(ctx, schema, path)
+		if err != nil {
+			d.report(pathNode, path, "unknown path: %v", schemaError(err))
+			continue
+		}
+		d.validateNode(ctx, valueNode, path, elem)
+	}
+	return nil
+}
+
+func (d *documentValidator) validateNode(ctx context.Context, node *yaml.Node, path *sdcpb.Path, elem *sdcpb.SchemaElem) {
+	switch {
+	case elem.GetContainer() != nil:
+		d.validateContainer(ctx, node, path, elem.GetContainer())
+	case elem.GetField() != nil:
+		d.validateLeaf(node, path, elem)
+	case elem.GetLeaflist() != nil:
+		if node.Kind != yaml.SequenceNode {
+			d.validateLeaf(node, path, elem)
+			return
+		}
+		for _, item := range node.Content {
+			d.validateLeaf(item, path, elem)
+		}
+	}
+}
+
+func (d *documentValidator) validateContainer(ctx context.Context, node *yaml.Node, path *sdcpb.Path, container *sdcpb.ContainerSchema) {
+	if isNull(node) {
+		return
+	}
+	if node.Kind == yaml.SequenceNode {
+		d.validateList(ctx, node, path, container)
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		d.report(node, path, "expected an object, got %q", node.Value)
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		child := path.CopyPathAddElem(sdcpb.NewPathElem(types.StripModulePrefix(keyNode.Value), nil))
+		elem, err := d.schemaElem(ctx, d.schema, child)
+		if err != nil {
+			d.report(keyNode, child, "unknown element %q: %v", keyNode.Value, schemaError(err))
+			continue
+		}
+		d.validateNode(ctx, valueNode, child, elem)
+	}
+}
+
+// validateList validates the entries of a list, each entry must carry all the list keys.
+func (d *documentValidator) validateList(ctx context.Context, node *yaml.Node, path *sdcpb.Path, container *sdcpb.ContainerSchema) {
+	if len(container.GetKeys()) == 0 {
+		d.report(node, path, "%s is not a list", container.GetName())
+		return
+	}
+	for _, entry := range node.Content {
+		if entry.Kind != yaml.MappingNode {
+			d.report(entry, path, "expected a list entry object")
+			continue
+		}
+		entryPath := path
+		missing := false
+		for _, k := range container.GetKeys() {
+			value := scalar(lookupMember(entry, k.GetName()))
+			if value == "" {
+				d.report(entry, path, "list entry is missing key %q", k.GetName())
+				missing = true
+				continue
+			}
+			entryPath = entryPath.CopyPathAddKey(k.GetName(), value)
+		}
+		if missing {
+			continue
+		}
+		d.validateContainer(ctx, entry, entryPath, &sdcpb.ContainerSchema{Name: container.GetName()})
+	}
+}
+
+func (d *documentValidator) validateLeaf(node *yaml.Node, path *sdcpb.Path, elem *sdcpb.SchemaElem) {
+	if node.Kind != yaml.ScalarNode {
+		d.report(node, path, "expected a value, got an object or list")
+		return
+	}
+	if _, err := sdcpb.SchemaElemToTV(elem, node.Value, 0); err != nil {
+		d.report(node, path, "invalid value %q: %v", node.Value, err)
+	}
+}
+
+// schemaError strips the gRPC decoration of schema-server errors.
+func schemaError(err error) string {
+	msg := err.Error()
+	if idx := strings.Index(msg, "desc = "); idx >= 0 {
+		return msg[idx+len("desc = "):]
+	}
+	return msg
+}
+
+// lookup returns the value node of a mapping member, nil if absent.
+func lookup(node *yaml.Node, name string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lookupMember is lookup ignoring the module prefix of member names.
+func lookupMember(node *yaml.Node, name string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if types.StripModulePrefix(node.Content[i].Value) == name {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func scalar(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func nodeOr(node, fallback *yaml.Node) *yaml.Node {
+	if node != nil {
+		return node
+	}
+	return fallback
+}
@@ -0,0 +1,159 @@
+package validate
+
+import (
+	"context"
+	"testing"
+
+	mockvalidate "github.com/sdcio/kubectl-sdc/mocks/validate"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testSchema is a tiny schema keyed by key-less XPath.
+var testSchema = map[string]*sdcpb.SchemaElem{
+	"/system":      {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{Name: "system"}}},
+	"/system/name": {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{Name: "name"}}},
+	"/system/name/host-name": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{
+		Name: "host-name", Type: &sdcpb.SchemaLeafType{Type: "string"},
+	}}},
+	"/interface": {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{
+		Name: "interface", Keys: []*sdcpb.LeafSchema{{Name: "name"}},
+	}}},
+	"/interface/name": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{
+		Name: "name", Type: &sdcpb.SchemaLeafType{Type: "string"},
+	}}},
+	"/interface/admin-state": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{
+		Name: "admin-state", Type: &sdcpb.SchemaLeafType{Type: "enumeration", EnumNames: []string{"enable", "disable"}},
+	}}},
+	"/interface/mtu": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{
+		Name: "mtu", Type: &sdcpb.SchemaLeafType{Type: "uint16"},
+	}}},
+}
+
+func newTestValidator(t *testing.T) *Validator {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	targets := mockvalidate.NewMockTargetClient(ctrl)
+	targets.EXPECT().GetTargetSchema(gomock.Any(), "default", "srl1").Return(&sdcpb.Schema{Vendor: "nokia", Version: "24.10"}, nil).AnyTimes()
+
+	schemas := mockvalidate.NewMockSchemaClient(ctrl)
+	schemas.EXPECT().GetSchemaElem(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+			elem, ok := testSchema[path.ToXPath(true)]
+			if !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "schema %q not found", path.ToXPath(true))
+			}
+			return elem, nil
+		}).AnyTimes()
+
+	return NewValidator(targets, schemas)
+}
+
+func TestValidateDocuments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name: "valid config",
+			manifest: `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  config:
+    - path: /
+      value:
+        system:
+          name:
+            host-name: srl1
+        srl_nokia-interfaces:interface:
+          - name: ethernet-1/1
+            admin-state: enable
+            mtu: 9000
+`,
+			want: []string{},
+		},
+		{
+			name: "invalid paths and values",
+			manifest: `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  config:
+    - path: /
+      value:
+        system:
+          hostname: srl1
+        interface:
+          - name: ethernet-1/1
+            admin-state: up
+            mtu: 70000
+          - mtu: 1500
+    - path: /system/name/domain-name
+      value: example.com
+`,
+			want: []string{
+				`m.yaml:12:11: /system/hostname: unknown element "hostname": schema "/system/hostname" not found`,
+				`m.yaml:15:26: /interface[name=ethernet-1/1]/admin-state: invalid value "up": value "up" does not match any valid enum values [enable, disable]`,
+				`m.yaml:16:18: /interface[name=ethernet-1/1]/mtu: invalid value "70000": "70000" not within ranges: [ 0..65535 ]`,
+				`m.yaml:17:13: /interface: list entry is missing key "name"`,
+				`m.yaml:18:13: /system/name/domain-name: unknown path: schema "/system/name/domain-name" not found`,
+			},
+		},
+		{
+			name: "missing target label",
+			manifest: `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+spec:
+  config: []
+`,
+			want: []string{`m.yaml:4:3: missing the target label "config.sdcio.dev/targetName"`},
+		},
+		{
+			name: "other kinds are skipped",
+			manifest: `apiVersion: config.sdcio.dev/v1alpha1
+kind: TargetClearDeviation
+metadata:
+  name: srl1
+`,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			findings, err := newTestValidator(t).ValidateDocuments(context.Background(), "default", "m.yaml", []byte(tt.manifest))
+			if err != nil {
+				t.Fatalf("ValidateDocuments() error = %v", err)
+			}
+			got := make([]string, 0, len(findings))
+			for _, f := range findings {
+				got = append(got, f.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateDocuments() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("finding %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// Package config reads and writes the kubectl-sdc configuration file. The file holds
+// defaults for the commands keyed by kube context, e.g.:
+//
+//	contexts:
+//	  kind-sdc:
+//	    namespace: lab
+//	    dataServer:
+//	      namespace: sdc-system
+//	      service: data-server
+//	    format:
+//	      blame: xpath
+//	    color: false
+//	    deviation:
+//	      ignorePaths:
+//	        - /system/information
+//	    history:
+//	      store: configmap
+//
+// Command line flags take precedence over the file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// EnvConfig is the environment variable overriding the path of the configuration file
+const EnvConfig = "KUBECTL_SDC_CONFIG"
+
+// Config is the kubectl-sdc configuration file
+type Config struct {
+	// Contexts holds the defaults by kube context name
+	Contexts map[string]*Context `json:"contexts,omitempty"`
+}
+
+// Context holds the defaults of the commands run against a kube context
+type Context struct {
+	// Namespace is used instead of the namespace of the kube context
+	Namespace string `json:"namespace,omitempty"`
+	// DataServer locates the data-server service
+	DataServer DataServer `json:"dataServer,omitzero"`
+	// Format holds the default output format by command
+	Format Format `json:"format,omitzero"`
+	// Color enables or disables colored output, unset leaves it to the terminal detection
+	Color *bool `json:"color,omitempty"`
+	// Deviation holds the deviation ignore policy
+	Deviation Deviation `json:"deviation,omitzero"`
+	// History holds where the history snapshots are stored
+	History History `json:"history,omitzero"`
+}
+
+// DataServer locates the data-server service
+type DataServer struct {
+	Namespace string `json:"namespace,omitempty"`
+	Service   string `json:"service,omitempty"`
+}
+
+// Format holds the default output format of the commands
+type Format struct {
+	Blame         string `json:"blame,omitempty"`
+	Deviation     string `json:"deviation,omitempty"`
+	RunningConfig string `json:"runningconfig,omitempty"`
+}
+
+// Deviation is the deviation ignore policy
+type Deviation struct {
+	// IgnorePaths are path prefixes whose deviations are not shown nor reverted
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
+}
+
+// History holds where the history snapshots are stored
+type History struct {
+	// Store is local or configmap, unset stores the snapshots locally
+	Store string `json:"store,omitempty"`
+}
+
+// key is a settable field of a Context
+type key struct {
+	description string
+	set         func(c *Context, value string) error
+}
+
+// keys are the fields of a Context that can be set with Set
+var keys = map[string]key{
+	"namespace": {
+		description: "namespace used instead of the namespace of the kube context",
+		set:         func(c *Context, v string) error { c.Namespace = v; return nil },
+	},
+	"dataServer.namespace": {
+		description: "namespace of the data-server service",
+		set:         func(c *Context, v string) error { c.DataServer.Namespace = v; return nil },
+	},
+	"dataServer.service": {
+		description: "name of the data-server service",
+		set:         func(c *Context, v string) error { c.DataServer.Service = v; return nil },
+	},
+	"format.blame": {
+		description: "default blame --format",
+		set:         func(c *Context, v string) error { c.Format.Blame = v; return nil },
+	},
+	"format.deviation": {
+		description: "default deviation --format",
+		set:         func(c *Context, v string) error { c.Format.Deviation = v; return nil },
+	},
+	"format.runningconfig": {
+		description: "default runningconfig --format",
+		set:         func(c *Context, v string) error { c.Format.RunningConfig = v; return nil },
+	},
+	"color": {
+		description: "true or false to enable or disable colored output",
+		set: func(c *Context, v string) error {
+			if v == "" {
+				c.Color = nil
+				return nil
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid value %q, must be true or false", v)
+			}
+			c.Color = &b
+			return nil
+		},
+	},
+	"history.store": {
+		description: "where history snapshots are stored, local or configmap",
+		set: func(c *Context, v string) error {
+			if v != "" && v != "local" && v != "configmap" {
+				return fmt.Errorf("invalid value %q, must be local or configmap", v)
+			}
+			c.History.Store = v
+			return nil
+		},
+	},
+	"deviation.ignorePaths": {
+		description: "comma separated path prefixes whose deviations are ignored",
+		set: func(c *Context, v string) error {
+			c.Deviation.IgnorePaths = nil
+			for _, p := range strings.Split(v, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					c.Deviation.IgnorePaths = append(c.Deviation.IgnorePaths, p)
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// validators check the values of the keys whose values are defined by a command,
+// e.g. the formats of the command a key is the default of
+var validators = map[string]func(value string) error{}
+
+// RegisterValidator makes Set check the non-empty values of the key with validate
+func RegisterValidator(name string, validate func(value string) error) {
+	validators[name] = validate
+}
+
+// Keys returns the keys accepted by Set, sorted
+func Keys() []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyDescription returns the description of a key accepted by Set
+func KeyDescription(name string) string {
+	return keys[name].description
+}
+
+// DefaultPath returns the path of the configuration file, $KUBECTL_SDC_CONFIG or
+// kubectl-sdc/config.yaml in $XDG_CONFIG_HOME, by default ~/.config
+func DefaultPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "kubectl-sdc", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path, a missing file is an empty configuration
+func Load(path string) (*Config, error) {
+	c := &Config{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, c); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the configuration file at path, creating its directory if needed
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Context returns the defaults of a kube context, empty if there are none
+func (c *Config) Context(name string) *Context {
+	if ctx, ok := c.Contexts[name]; ok && ctx != nil {
+		return ctx
+	}
+	return &Context{}
+}
+
+// Set sets the key of a kube context to value, an empty value unsets it
+func (c *Config) Set(context, name, value string) error {
+	k, ok := keys[name]
+	if !ok {
+		return fmt.Errorf("unknown key %q, must be one of: %s", name, strings.Join(Keys(), ", "))
+	}
+	if validate, ok := validators[name]; ok && value != "" {
+		if err := validate(value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	ctx := c.Context(context)
+	if err := k.set(ctx, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if c.Contexts == nil {
+		c.Contexts = map[string]*Context{}
+	}
+	c.Contexts[context] = ctx
+	return nil
+}
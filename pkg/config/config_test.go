@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoad_MissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if ctx := cfg.Context("kind-sdc"); ctx.Namespace != "" || ctx.Color != nil {
+		t.Fatalf("Context() = %+v, want empty defaults", ctx)
+	}
+}
+
+func TestLoad_RejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("contexts:\n  kind-sdc:\n    namespce: lab\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "namespce") {
+		t.Fatalf("Load() error = %v, want the unknown field", err)
+	}
+}
+
+func TestSet_SaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubectl-sdc", "config.yaml")
+
+	cfg := &Config{}
+	for _, kv := range [][2]string{
+		{"namespace", "lab"},
+		{"dataServer.namespace", "sdc"},
+		{"dataServer.service", "ds"},
+		{"format.blame", "xpath"},
+		{"color", "false"},
+		{"deviation.ignorePaths", "/system/information, /system/clock,"},
+		{"history.store", "configmap"},
+	} {
+		if err := cfg.Set("kind-sdc", kv[0], kv[1]); err != nil {
+			t.Fatalf("Set(%s) error = %v", kv[0], err)
+		}
+	}
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ctx := loaded.Context("kind-sdc")
+	if ctx.Namespace != "lab" || ctx.DataServer != (DataServer{Namespace: "sdc", Service: "ds"}) || ctx.Format.Blame != "xpath" {
+		t.Fatalf("Context() = %+v, want the set values", ctx)
+	}
+	if ctx.History.Store != "configmap" {
+		t.Fatalf("History.Store = %q, want configmap", ctx.History.Store)
+	}
+	if ctx.Color == nil || *ctx.Color {
+		t.Fatalf("Color = %v, want false", ctx.Color)
+	}
+	if want := []string{"/system/information", "/system/clock"}; !slices.Equal(ctx.Deviation.IgnorePaths, want) {
+		t.Fatalf("IgnorePaths = %v, want %v", ctx.Deviation.IgnorePaths, want)
+	}
+
+	// unset values are left out of the file
+	if err := loaded.Set("kind-sdc", "color", ""); err != nil {
+		t.Fatalf("Set(color) error = %v", err)
+	}
+	if err := loaded.Set("kind-sdc", "deviation.ignorePaths", ""); err != nil {
+		t.Fatalf("Set(deviation.ignorePaths) error = %v", err)
+	}
+	if err := loaded.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "color") || strings.Contains(string(data), "deviation") {
+		t.Fatalf("saved config = %s, want color and deviation unset", data)
+	}
+}
+
+func TestSet_Errors(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Set("kind-sdc", "colour", "true"); err == nil || !strings.Contains(err.Error(), "unknown key") {
+		t.Fatalf("Set(colour) error = %v, want unknown key", err)
+	}
+	if err := cfg.Set("kind-sdc", "color", "maybe"); err == nil || !strings.Contains(err.Error(), "true or false") {
+		t.Fatalf("Set(color) error = %v, want invalid value", err)
+	}
+	if err := cfg.Set("kind-sdc", "history.store", "s3"); err == nil || !strings.Contains(err.Error(), "local or configmap") {
+		t.Fatalf("Set(history.store) error = %v, want invalid value", err)
+	}
+	if len(cfg.Contexts) != 0 {
+		t.Fatalf("Contexts = %v, want none after failed sets", cfg.Contexts)
+	}
+}
+
+func TestSet_RegisteredValidator(t *testing.T) {
+	RegisterValidator("format.blame", func(v string) error {
+		if v != "tree" {
+			return fmt.Errorf("invalid format %q", v)
+		}
+		return nil
+	})
+	t.Cleanup(func() { delete(validators, "format.blame") })
+
+	cfg := &Config{}
+	if err := cfg.Set("kind-sdc", "format.blame", "graph"); err == nil || !strings.Contains(err.Error(), `format.blame: invalid format "graph"`) {
+		t.Fatalf("Set(format.blame) error = %v, want invalid format", err)
+	}
+	if err := cfg.Set("kind-sdc", "format.blame", "tree"); err != nil {
+		t.Fatalf("Set(format.blame) error = %v", err)
+	}
+	if err := cfg.Set("kind-sdc", "format.blame", ""); err != nil {
+		t.Fatalf("unset format.blame error = %v", err)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv(EnvConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if path, _ := DefaultPath(); path != filepath.Join("/xdg", "kubectl-sdc", "config.yaml") {
+		t.Fatalf("DefaultPath() = %s, want it in $XDG_CONFIG_HOME", path)
+	}
+
+	t.Setenv(EnvConfig, "/etc/sdc.yaml")
+	if path, _ := DefaultPath(); path != "/etc/sdc.yaml" {
+		t.Fatalf("DefaultPath() = %s, want $%s", path, EnvConfig)
+	}
+}
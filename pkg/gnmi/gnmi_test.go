@@ -0,0 +1,212 @@
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func mustParsePath(t *testing.T, p string) *sdcpb.Path {
+	t.Helper()
+	path, err := sdcpb.ParsePath(p)
+	if err != nil {
+		t.Fatalf("ParsePath(%q) unexpected error: %v", p, err)
+	}
+	return path
+}
+
+func testDeviations() types.Deviations {
+	devs := types.Deviations{}
+	for _, target := range []string{"srl2", "srl1"} {
+		intent := types.NewDeviations(target, "intent-"+target, types.DeviationTypeConfig, 3).SetNamespace("default")
+		mtu := types.NewDeviation("/interface[name=ethernet-1/1]/mtu", "9232", "1500", "NOT_APPLIED")
+		mtu.Desired = &sdcpb.TypedValue{Value: &sdcpb.TypedValue_UintVal{UintVal: 9232}}
+		intent.AddDeviation(mtu)
+		intent.AddDeviation(types.NewDeviation("/system/name/host-name", "", "leaf1", "UNHANDLED"))
+		intent.AddDeviation(types.NewDeviation("/interface[name=ethernet-1/1]/description", "uplink \"a\"", "", "NOT_APPLIED"))
+		devs.AddDeviation(intent)
+	}
+	return devs
+}
+
+func TestFromDeviations(t *testing.T) {
+	reqs, err := FromDeviations(testDeviations())
+	if err != nil {
+		t.Fatalf("FromDeviations() unexpected error: %v", err)
+	}
+	if len(reqs) != 2 || reqs[0].Target != "srl1" || reqs[1].Target != "srl2" {
+		t.Fatalf("FromDeviations() = %+v, want a request for srl1 and srl2", reqs)
+	}
+
+	for _, req := range reqs {
+		sb := &strings.Builder{}
+		if err := Write(sb, FormatPrototext, []*SetRequest{req}); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+		got := &gnmipb.SetRequest{}
+		if err := prototext.Unmarshal([]byte(sb.String()), got); err != nil {
+			t.Fatalf("prototext.Unmarshal() unexpected error: %v\n%s", err, sb.String())
+		}
+		if want := wantSetRequest(req.Target); !proto.Equal(got, want) {
+			t.Fatalf("Write() = %v, want %v", got, want)
+		}
+	}
+}
+
+// wantSetRequest is the gnmi.SetRequest restoring the desired values of testDeviations
+func wantSetRequest(target string) *gnmipb.SetRequest {
+	iface := &gnmipb.PathElem{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}}
+	return &gnmipb.SetRequest{
+		Prefix: &gnmipb.Path{Target: target},
+		Delete: []*gnmipb.Path{{Elem: []*gnmipb.PathElem{{Name: "system"}, {Name: "name"}, {Name: "host-name"}}}},
+		Update: []*gnmipb.Update{
+			{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{iface, {Name: "description"}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"uplink \"a\""`)}},
+			},
+			{
+				Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{iface, {Name: "mtu"}}},
+				Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`9232`)}},
+			},
+		},
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	reqs, err := FromDeviations(testDeviations())
+	if err != nil {
+		t.Fatalf("FromDeviations() unexpected error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := Write(buf, FormatJSON, reqs); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	dec := json.NewDecoder(buf)
+	for _, target := range []string{"srl1", "srl2"} {
+		var doc json.RawMessage
+		if err := dec.Decode(&doc); err != nil {
+			t.Fatalf("Decode() unexpected error: %v", err)
+		}
+		got := &gnmipb.SetRequest{}
+		if err := protojson.Unmarshal(doc, got); err != nil {
+			t.Fatalf("protojson.Unmarshal() unexpected error: %v\n%s", err, doc)
+		}
+		if want := wantSetRequest(target); !proto.Equal(got, want) {
+			t.Fatalf("Write() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWrite_SetFile(t *testing.T) {
+	req := &SetRequest{
+		Target:  "srl1",
+		Replace: []Update{{Path: mustParsePath(t, "/system/name"), Value: map[string]any{"host-name": "srl1"}}},
+		Delete:  []*sdcpb.Path{mustParsePath(t, "/interface[name=ethernet-1/2]")},
+	}
+	sb := &strings.Builder{}
+	if err := Write(sb, "GNMIC-SETFILE", []*SetRequest{req, req}); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	doc := `# target: srl1
+deletes:
+- /interface[name=ethernet-1/2]
+replaces:
+- encoding: json_ietf
+  path: /system/name
+  value:
+    host-name: srl1`
+	if want := doc + "\n---\n" + doc + "\n"; sb.String() != want {
+		t.Fatalf("Write() =\n%s\nwant\n%s", sb.String(), want)
+	}
+
+	if err := Write(sb, "xml", nil); err == nil {
+		t.Fatal("Write() expected an error for an unknown format")
+	}
+}
+
+func TestReplaceFromJSON(t *testing.T) {
+	config := []byte(`{"srl_nokia-interfaces:interface":[{"name":"ethernet-1/1","mtu":18446744073709551615}],"system":{"name":{"host-name":"srl1"}}}`)
+
+	tests := []struct {
+		path    string
+		want    string
+		wantErr string
+	}{
+		{path: "/", want: `{"srl_nokia-interfaces:interface":[{"mtu":18446744073709551615,"name":"ethernet-1/1"}],"system":{"name":{"host-name":"srl1"}}}`},
+		{path: "/interface", want: `[{"mtu":18446744073709551615,"name":"ethernet-1/1"}]`},
+		{path: "/interface[name=ethernet-1/1]/mtu", want: `18446744073709551615`},
+		{path: "/system/name", want: `{"host-name":"srl1"}`},
+		{path: "/interface/mtu", wantErr: "path /interface/mtu: the keys of the list interface are missing"},
+		{path: "/system/name/host-name/x", wantErr: "path /system/name/host-name/x: x is not a container or list entry"},
+		{path: "/network-instance", wantErr: "path /network-instance: network-instance not found in the configuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, err := ReplaceFromJSON("srl1", mustParsePath(t, tt.path), config)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ReplaceFromJSON() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReplaceFromJSON() unexpected error: %v", err)
+			}
+			got, err := json.Marshal(req.Replace[0].Value)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("value = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	tests := []struct {
+		name string
+		tv   *sdcpb.TypedValue
+		want string
+	}{
+		{name: "string", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "a"}}, want: `"a"`},
+		{name: "int", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_IntVal{IntVal: -3}}, want: `-3`},
+		{name: "bool", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_BoolVal{BoolVal: true}}, want: `true`},
+		{name: "decimal", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_DecimalVal{DecimalVal: &sdcpb.Decimal64{Digits: 125, Precision: 2}}}, want: `"1.25"`},
+		{name: "identityref", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_IdentityrefVal{IdentityrefVal: &sdcpb.IdentityRef{Value: "up", Module: "m"}}}, want: `"m:up"`},
+		{name: "empty", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_EmptyVal{}}, want: `[null]`},
+		{name: "leaflist", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_LeaflistVal{LeaflistVal: &sdcpb.ScalarArray{Element: []*sdcpb.TypedValue{
+			{Value: &sdcpb.TypedValue_StringVal{StringVal: "a"}},
+			{Value: &sdcpb.TypedValue_UintVal{UintVal: 1}},
+		}}}}, want: `["a",1]`},
+		{name: "json ietf", tv: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"a":1}`)}}, want: `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := JSONValue(tt.tv)
+			if err != nil {
+				t.Fatalf("JSONValue() unexpected error: %v", err)
+			}
+			got, err := json.Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("JSONValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := JSONValue(&sdcpb.TypedValue{}); err == nil {
+		t.Fatal("JSONValue() expected an error for a value without a type")
+	}
+}
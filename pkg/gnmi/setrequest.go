@@ -0,0 +1,213 @@
+// Package gnmi builds gNMI SetRequests from the configuration known to SDC, e.g.
+// to push an emergency fix with gnmic starting from the running configuration or
+// from the deviations of a target.
+//
+// The requests are written as gNMI prototext, as gNMI JSON (the protojson
+// mapping), or as a gnmic set-request file (gnmic set --request-file). All
+// values are JSON IETF encoded.
+package gnmi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// Update sets a JSON IETF encodable value at a path
+type Update struct {
+	Path  *sdcpb.Path
+	Value any
+}
+
+// SetRequest is a gNMI SetRequest for a single target
+type SetRequest struct {
+	// Target is set as the target of the prefix of the request
+	Target  string
+	Delete  []*sdcpb.Path
+	Replace []Update
+	Update  []Update
+}
+
+// xpath returns the root based XPath of p, "/" for the root
+func xpath(p *sdcpb.Path) string {
+	return "/" + (&sdcpb.Path{Origin: p.GetOrigin(), Elem: p.GetElem()}).ToXPath(false)
+}
+
+// ReplaceFromJSON returns the SetRequest replacing the subtree at path with its
+// value in config, the JSON IETF encoded configuration of the target
+func ReplaceFromJSON(target string, path *sdcpb.Path, config []byte) (*SetRequest, error) {
+	dec := json.NewDecoder(bytes.NewReader(config))
+	// keep the numbers as they are, e.g. uint64 values do not fit a float64
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON IETF configuration: %w", err)
+	}
+
+	value, err := subtree(value, path.GetElem())
+	if err != nil {
+		return nil, fmt.Errorf("path %s: %w", xpath(path), err)
+	}
+	return &SetRequest{Target: target, Replace: []Update{{Path: path, Value: value}}}, nil
+}
+
+// subtree returns the value at the path elements. JSON IETF qualifies member
+// names with their module where it changes, so the names match with and without
+// the module.
+func subtree(value any, elems []*sdcpb.PathElem) (any, error) {
+	for i, pe := range elems {
+		container, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a container or list entry", pe.GetName())
+		}
+		value, ok = member(container, pe.GetName())
+		if !ok {
+			return nil, fmt.Errorf("%s not found in the configuration", pe.GetName())
+		}
+
+		entries, isList := value.([]any)
+		switch {
+		case !isList:
+		case len(pe.GetKey()) > 0:
+			if value, ok = listEntry(entries, pe.GetKey()); !ok {
+				return nil, fmt.Errorf("%s not found in the configuration", (&sdcpb.Path{Elem: []*sdcpb.PathElem{pe}}).ToXPath(false))
+			}
+		case i < len(elems)-1:
+			return nil, fmt.Errorf("the keys of the list %s are missing", pe.GetName())
+		}
+	}
+	return value, nil
+}
+
+func member(container map[string]any, name string) (any, bool) {
+	if v, ok := container[name]; ok {
+		return v, true
+	}
+	for k, v := range container {
+		if _, local, ok := strings.Cut(k, ":"); ok && local == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func listEntry(entries []any, keys map[string]string) (any, bool) {
+	for _, e := range entries {
+		entry, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		matches := true
+		for k, v := range keys {
+			kv, ok := member(entry, k)
+			if !ok || fmt.Sprint(kv) != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return entry, true
+		}
+	}
+	return nil, false
+}
+
+// FromDeviations returns a SetRequest per target, sorted by namespace and target,
+// that restores the desired values of the deviations. Paths without a desired
+// value are deleted.
+func FromDeviations(devs types.Deviations) ([]*SetRequest, error) {
+	groups := devs.ByTarget()
+	keys := make([]types.TargetKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	reqs := make([]*SetRequest, 0, len(keys))
+	for _, key := range keys {
+		items := groups[key].Items()
+		sort.Slice(items, func(i, j int) bool { return items[i].Path < items[j].Path })
+
+		req := &SetRequest{Target: key.Target}
+		for _, dev := range items {
+			path, err := sdcpb.ParsePath(dev.Path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deviation path %q: %w", dev.Path, err)
+			}
+			switch {
+			case dev.Desired != nil:
+				value, err := JSONValue(dev.Desired)
+				if err != nil {
+					return nil, fmt.Errorf("deviation path %q: %w", dev.Path, err)
+				}
+				req.Update = append(req.Update, Update{Path: path, Value: value})
+			case dev.DesiredValue != "":
+				// without the typed value the desired value is set as a string
+				req.Update = append(req.Update, Update{Path: path, Value: dev.DesiredValue})
+			default:
+				req.Delete = append(req.Delete, path)
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// JSONValue returns the JSON IETF encodable value of a typed value. Integers are
+// encoded as numbers, since the typed value does not tell the size of the YANG
+// type, and decimals as strings.
+func JSONValue(tv *sdcpb.TypedValue) (any, error) {
+	switch v := tv.GetValue().(type) {
+	case *sdcpb.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *sdcpb.TypedValue_AsciiVal:
+		return v.AsciiVal, nil
+	case *sdcpb.TypedValue_IntVal:
+		return v.IntVal, nil
+	case *sdcpb.TypedValue_UintVal:
+		return v.UintVal, nil
+	case *sdcpb.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *sdcpb.TypedValue_FloatVal:
+		return float64(v.FloatVal), nil
+	case *sdcpb.TypedValue_DoubleVal:
+		return v.DoubleVal, nil
+	case *sdcpb.TypedValue_DecimalVal:
+		return tv.ToString(), nil
+	case *sdcpb.TypedValue_BytesVal:
+		// encoded as base64, like the YANG binary type
+		return v.BytesVal, nil
+	case *sdcpb.TypedValue_IdentityrefVal:
+		return v.IdentityrefVal.JsonIetfString(), nil
+	case *sdcpb.TypedValue_EmptyVal:
+		return []any{nil}, nil
+	case *sdcpb.TypedValue_LeaflistVal:
+		elems := make([]any, 0, len(v.LeaflistVal.GetElement()))
+		for _, e := range v.LeaflistVal.GetElement() {
+			ev, err := JSONValue(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, ev)
+		}
+		return elems, nil
+	case *sdcpb.TypedValue_JsonVal:
+		return rawJSON(v.JsonVal)
+	case *sdcpb.TypedValue_JsonIetfVal:
+		return rawJSON(v.JsonIetfVal)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+func rawJSON(b []byte) (any, error) {
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("invalid JSON value %q", b)
+	}
+	return json.RawMessage(b), nil
+}
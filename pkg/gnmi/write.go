@@ -0,0 +1,273 @@
+package gnmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"sigs.k8s.io/yaml"
+)
+
+// Format is the encoding the SetRequests are written in
+type Format string
+
+const (
+	// FormatPrototext writes the SetRequest in the protobuf text format
+	FormatPrototext Format = "gnmi-prototext"
+	// FormatJSON writes the SetRequest in the protobuf JSON mapping
+	FormatJSON Format = "gnmi-json"
+	// FormatSetFile writes a gnmic set-request file, for gnmic set --request-file
+	FormatSetFile Format = "gnmic-setfile"
+)
+
+// Formats lists the formats of the SetRequests
+var Formats = []Format{FormatPrototext, FormatJSON, FormatSetFile}
+
+// FormatStrings returns the formats as strings
+func FormatStrings() []string {
+	formats := make([]string, len(Formats))
+	for i, f := range Formats {
+		formats[i] = string(f)
+	}
+	return formats
+}
+
+// IsFormat returns true if s is one of the formats, case insensitive
+func IsFormat(s string) bool {
+	return slices.Contains(Formats, Format(strings.ToLower(s)))
+}
+
+// encoding is the gNMI encoding of all the values
+const encoding = "json_ietf"
+
+// Write writes the SetRequests in the format. Multiple requests are separated as
+// documents of the format, each naming its target.
+func Write(w io.Writer, format Format, reqs []*SetRequest) error {
+	var write func(*SetRequest) (string, error)
+	switch format = Format(strings.ToLower(string(format))); format {
+	case FormatPrototext:
+		write = writePrototext
+	case FormatJSON:
+		write = writeJSON
+	case FormatSetFile:
+		write = setFile
+	default:
+		return fmt.Errorf("unsupported gNMI format %q", format)
+	}
+
+	docs := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		doc, err := write(req)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, doc)
+	}
+
+	sep := "\n"
+	if format == FormatSetFile {
+		sep = "\n---\n"
+	}
+	_, err := io.WriteString(w, strings.Join(docs, sep)+"\n")
+	return err
+}
+
+func marshalValue(v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return b, nil
+}
+
+// toProtoPath returns the gNMI path of an sdcpb path
+func toProtoPath(p *sdcpb.Path) *gnmipb.Path {
+	gp := &gnmipb.Path{Origin: p.GetOrigin()}
+	for _, pe := range p.GetElem() {
+		gp.Elem = append(gp.Elem, &gnmipb.PathElem{Name: pe.GetName(), Key: pe.GetKey()})
+	}
+	return gp
+}
+
+func toProtoUpdates(updates []Update) ([]*gnmipb.Update, error) {
+	result := make([]*gnmipb.Update, 0, len(updates))
+	for _, u := range updates {
+		val, err := marshalValue(u.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &gnmipb.Update{
+			Path: toProtoPath(u.Path),
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: val}},
+		})
+	}
+	return result, nil
+}
+
+// toProto returns the gnmi.SetRequest of the request, the values are json_ietf encoded
+func toProto(req *SetRequest) (*gnmipb.SetRequest, error) {
+	r := &gnmipb.SetRequest{}
+	if req.Target != "" {
+		r.Prefix = &gnmipb.Path{Target: req.Target}
+	}
+	for _, p := range req.Delete {
+		r.Delete = append(r.Delete, toProtoPath(p))
+	}
+	var err error
+	if r.Replace, err = toProtoUpdates(req.Replace); err != nil {
+		return nil, err
+	}
+	if r.Update, err = toProtoUpdates(req.Update); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// writePrototext writes the request in the multi-line protobuf text format
+func writePrototext(req *SetRequest) (string, error) {
+	r, err := toProto(req)
+	if err != nil {
+		return "", err
+	}
+	data, err := prototext.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// writeJSON writes the request in the protobuf JSON mapping
+func writeJSON(req *SetRequest) (string, error) {
+	r, err := toProto(req)
+	if err != nil {
+		return "", err
+	}
+	data, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PathPrototext returns the gNMI path in the single line prototext format, e.g.
+// elem: {name: "system"} elem: {name: "name"}. Unlike prototext.Format, the
+// spacing is stable, the paths are meant to be read and copied.
+func PathPrototext(p *sdcpb.Path) string {
+	var parts []string
+	if p.GetOrigin() != "" {
+		parts = append(parts, "origin: "+quote([]byte(p.GetOrigin())))
+	}
+	for _, pe := range p.GetElem() {
+		elem := "elem: {name: " + quote([]byte(pe.GetName()))
+		keys := make([]string, 0, len(pe.GetKey()))
+		for k := range pe.GetKey() {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			elem += fmt.Sprintf(" key: {key: %s value: %s}", quote([]byte(k)), quote([]byte(pe.GetKey()[k])))
+		}
+		parts = append(parts, elem+"}")
+	}
+	return strings.Join(parts, " ")
+}
+
+// PathJSON returns the gNMI path in the protobuf JSON mapping, with a stable
+// spacing unlike protojson
+func PathJSON(p *sdcpb.Path) (string, error) {
+	data, err := json.Marshal(toJSONPath(p))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// quote quotes b as a prototext string, escaping the non printable ASCII bytes
+func quote(b []byte) string {
+	sb := &strings.Builder{}
+	sb.WriteByte('"')
+	for _, c := range b {
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c == '\n':
+			sb.WriteString(`\n`)
+		case c == '\r':
+			sb.WriteString(`\r`)
+		case c == '\t':
+			sb.WriteString(`\t`)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(sb, `\%03o`, c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// The protobuf JSON mapping of a gnmi.Path
+type (
+	jsonPathElem struct {
+		Name string            `json:"name"`
+		Key  map[string]string `json:"key,omitempty"`
+	}
+	jsonPath struct {
+		Origin string         `json:"origin,omitempty"`
+		Elem   []jsonPathElem `json:"elem,omitempty"`
+	}
+)
+
+func toJSONPath(p *sdcpb.Path) jsonPath {
+	jp := jsonPath{Origin: p.GetOrigin()}
+	for _, pe := range p.GetElem() {
+		jp.Elem = append(jp.Elem, jsonPathElem{Name: pe.GetName(), Key: pe.GetKey()})
+	}
+	return jp
+}
+
+// The gnmic set-request file
+type (
+	setFileUpdate struct {
+		Path     string `json:"path"`
+		Value    any    `json:"value"`
+		Encoding string `json:"encoding"`
+	}
+	setFileRequest struct {
+		Updates  []setFileUpdate `json:"updates,omitempty"`
+		Replaces []setFileUpdate `json:"replaces,omitempty"`
+		Deletes  []string        `json:"deletes,omitempty"`
+	}
+)
+
+func toSetFileUpdates(updates []Update) []setFileUpdate {
+	var result []setFileUpdate
+	for _, u := range updates {
+		result = append(result, setFileUpdate{Path: xpath(u.Path), Value: u.Value, Encoding: encoding})
+	}
+	return result
+}
+
+// setFile writes the request as a gnmic set-request file. The target is not part
+// of the file, it is noted in a comment and passed to gnmic with --address.
+func setFile(req *SetRequest) (string, error) {
+	r := setFileRequest{
+		Updates:  toSetFileUpdates(req.Update),
+		Replaces: toSetFileUpdates(req.Replace),
+	}
+	for _, p := range req.Delete {
+		r.Deletes = append(r.Deletes, xpath(p))
+	}
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("# target: %s\n%s", req.Target, strings.TrimSuffix(string(data), "\n")), nil
+}
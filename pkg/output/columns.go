@@ -0,0 +1,212 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Column is a column of a table, its cells are the results of the JSONPath
+// expression on the objects
+type Column struct {
+	Header string
+	// JSONPath is a relaxed JSONPath expression, e.g. .metadata.name or {.path}
+	JSONPath string
+}
+
+// wideColumns are the columns printed by -o wide, by kind
+var wideColumns = map[string][]Column{
+	KindBlameEntry: {
+		{Header: "PATH", JSONPath: ".path"},
+		{Header: "VALUE", JSONPath: ".value"},
+		{Header: "OWNER", JSONPath: ".owner"},
+		{Header: "DEVIATION", JSONPath: ".deviationValue"},
+	},
+	KindDeviationEntry: {
+		{Header: "TARGET", JSONPath: ".target"},
+		{Header: "DEVIATION", JSONPath: ".deviation"},
+		{Header: "TYPE", JSONPath: ".type"},
+		{Header: "PATH", JSONPath: ".path"},
+		{Header: "ACTUAL", JSONPath: ".actualValue"},
+		{Header: "DESIRED", JSONPath: ".desiredValue"},
+		{Header: "REASON", JSONPath: ".reason"},
+	},
+	KindIntentUpdate: {
+		{Header: "INTENT", JSONPath: ".intent"},
+		{Header: "PATH", JSONPath: ".path"},
+		{Header: "VALUE", JSONPath: ".value"},
+	},
+	KindDatastoreSummary: {
+		{Header: "NAME", JSONPath: ".name"},
+		{Header: "TYPE", JSONPath: ".targetType"},
+		{Header: "ADDRESS", JSONPath: ".address"},
+		{Header: "VENDOR", JSONPath: ".schemaVendor"},
+		{Header: "VERSION", JSONPath: ".schemaVersion"},
+		{Header: "STATUS", JSONPath: ".status"},
+		{Header: "DETAILS", JSONPath: ".statusDetails"},
+	},
+	KindSchema: {
+		{Header: "NAME", JSONPath: ".name"},
+		{Header: "VENDOR", JSONPath: ".vendor"},
+		{Header: "VERSION", JSONPath: ".version"},
+		{Header: "STATUS", JSONPath: ".status"},
+	},
+	KindSchemaNode: {
+		{Header: "PATH", JSONPath: ".path"},
+		{Header: "KIND", JSONPath: ".kind"},
+		{Header: "TYPE", JSONPath: ".type"},
+		{Header: "DESCRIPTION", JSONPath: ".description"},
+	},
+	KindSchemaTree: {
+		{Header: "NAME", JSONPath: ".name"},
+		{Header: "KIND", JSONPath: ".kind"},
+		{Header: "TYPE", JSONPath: ".type"},
+	},
+	KindLeafChange: {
+		{Header: "FROM", JSONPath: ".from"},
+		{Header: "TO", JSONPath: ".to"},
+		{Header: "SECTION", JSONPath: ".section"},
+		{Header: "OPERATION", JSONPath: ".operation"},
+		{Header: "PATH", JSONPath: ".path"},
+		{Header: "OLD", JSONPath: ".oldValue"},
+		{Header: "NEW", JSONPath: ".newValue"},
+	},
+	KindSnapshot: {
+		{Header: "ID", JSONPath: ".id"},
+		{Header: "TARGET", JSONPath: ".target"},
+		{Header: "TIME", JSONPath: ".time"},
+		{Header: "MESSAGE", JSONPath: ".message"},
+	},
+}
+
+// defaultColumns are the columns printed by -o wide for kinds without wideColumns
+var defaultColumns = []Column{
+	{Header: "NAMESPACE", JSONPath: ".metadata.namespace"},
+	{Header: "NAME", JSONPath: ".metadata.name"},
+}
+
+// ParseCustomColumns parses a custom-columns spec, a comma separated list of
+// HEADER:JSONPATH pairs, e.g. PATH:.path,OWNER:.owner
+func ParseCustomColumns(spec string) ([]Column, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	parts := strings.Split(spec, ",")
+	columns := make([]Column, 0, len(parts))
+	for _, part := range parts {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" || path == "" {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", part)
+		}
+		columns = append(columns, Column{Header: header, JSONPath: path})
+	}
+	return columns, nil
+}
+
+// ColumnsPrinter prints objects as a table with a row per object. Lists are
+// printed with a row per item.
+type ColumnsPrinter struct {
+	columns []Column
+	// wide picks the columns by the kind of the objects
+	wide bool
+}
+
+// NewCustomColumnsPrinter returns a ColumnsPrinter printing the given columns
+func NewCustomColumnsPrinter(columns []Column) *ColumnsPrinter {
+	return &ColumnsPrinter{columns: columns}
+}
+
+// NewWidePrinter returns a ColumnsPrinter printing all the columns of the kind of the objects
+func NewWidePrinter() *ColumnsPrinter {
+	return &ColumnsPrinter{wide: true}
+}
+
+// PrintObj prints the object or the items of the list
+func (p *ColumnsPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	objs := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		var err error
+		if objs, err = meta.ExtractList(obj); err != nil {
+			return err
+		}
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	columns := p.columns
+	if p.wide {
+		var ok bool
+		if columns, ok = wideColumns[objs[0].GetObjectKind().GroupVersionKind().Kind]; !ok {
+			columns = defaultColumns
+		}
+	}
+
+	parsers := make([]*jsonpath.JSONPath, len(columns))
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		parser := jsonpath.New(column.Header).AllowMissingKeys(true)
+		if err := parser.Parse(relaxedJSONPath(column.JSONPath)); err != nil {
+			return fmt.Errorf("invalid JSONPath %q of column %s: %w", column.JSONPath, column.Header, err)
+		}
+		parsers[i] = parser
+		headers[i] = column.Header
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, o := range objs {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+		if err != nil {
+			return err
+		}
+		cells := make([]string, len(parsers))
+		for i, parser := range parsers {
+			if cells[i], err = cell(parser, content); err != nil {
+				return err
+			}
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// cell returns the results of the parser on the object, comma separated, or
+// <none> if there are none
+func cell(parser *jsonpath.JSONPath, content map[string]any) (string, error) {
+	results, err := parser.FindResults(content)
+	if err != nil {
+		return "", err
+	}
+	var values []string
+	for _, result := range results {
+		for _, r := range result {
+			if !r.IsValid() || (r.Kind() == reflect.Interface && r.IsNil()) {
+				continue
+			}
+			values = append(values, fmt.Sprint(r.Interface()))
+		}
+	}
+	if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		return "<none>", nil
+	}
+	return strings.Join(values, ","), nil
+}
+
+// relaxedJSONPath accepts .path, path and {.path} as JSONPath expressions
+func relaxedJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") && strings.HasSuffix(path, "}") {
+		path = path[1 : len(path)-1]
+	}
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return "{" + path + "}"
+}
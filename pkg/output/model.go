@@ -0,0 +1,267 @@
+// Package output prints the results of the commands with the kubectl printers
+// selected by -o/--output: json, yaml, name, wide, jsonpath, go-template and
+// custom-columns.
+//
+// The printers work on an object model of unstructured objects of the
+// kubectl.sdcio.dev/v1alpha1 group, which is not served by any API server and only
+// qualifies the kinds. Every object carries its path as metadata.name and the
+// namespace of its target as metadata.namespace, e.g.:
+//
+//	apiVersion: kubectl.sdcio.dev/v1alpha1
+//	kind: BlameEntry
+//	metadata:
+//	  name: /interface[name=ethernet-1/1]/admin-state
+//	  namespace: default
+//	target: srl1
+//	path: /interface[name=ethernet-1/1]/admin-state
+//	value: enable
+//	owner: default.intent-a
+//
+// Commands print a List of such objects, or a single one. The kinds describing a
+// resource rather than a leaf, e.g. TargetSummary, carry its name as metadata.name.
+package output
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SchemeGroupVersion is the group version of the objects printed by the output formats
+var SchemeGroupVersion = schema.GroupVersion{Group: "kubectl.sdcio.dev", Version: "v1alpha1"}
+
+// The kinds of the object model
+const (
+	KindBlameEntry       = "BlameEntry"
+	KindDeviationEntry   = "DeviationEntry"
+	KindIntentUpdate     = "IntentUpdate"
+	KindTargetSummary    = "TargetSummary"
+	KindDatastoreSummary = "DatastoreSummary"
+	KindSchema           = "Schema"
+	KindSchemaNode       = "SchemaNode"
+	KindSchemaTree       = "SchemaTree"
+	KindLeafChange       = "LeafChange"
+	KindSnapshot         = "Snapshot"
+)
+
+// BlameEntry is a configured leaf of a target and the intent owning it
+type BlameEntry struct {
+	Target string `json:"target"`
+	Path   string `json:"path"`
+	Value  string `json:"value"`
+	Owner  string `json:"owner"`
+	// DeviationValue is the value on the device, when it deviates from Value
+	DeviationValue string `json:"deviationValue,omitempty"`
+}
+
+// DeviationEntry is a path of a target that deviates from the configuration
+type DeviationEntry struct {
+	Target string `json:"target"`
+	// Deviation is the name of the Deviation resource reporting the path
+	Deviation    string `json:"deviation"`
+	Type         string `json:"type"`
+	Path         string `json:"path"`
+	ActualValue  string `json:"actualValue"`
+	DesiredValue string `json:"desiredValue"`
+	Reason       string `json:"reason"`
+}
+
+// IntentUpdate is a leaf set by an intent of a target
+type IntentUpdate struct {
+	Target string `json:"target"`
+	Intent string `json:"intent"`
+	Path   string `json:"path"`
+	Value  string `json:"value"`
+}
+
+// Schema is a schema loaded in the schema-server
+type Schema struct {
+	Name    string `json:"name"`
+	Vendor  string `json:"vendor"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+}
+
+// LeafChange is a leaf added, changed or removed between two configurations,
+// e.g. of two targets or two snapshots
+type LeafChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Section is the running config or the intent the leaf belongs to, if any
+	Section   string `json:"section,omitempty"`
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	OldValue  string `json:"oldValue,omitempty"`
+	NewValue  string `json:"newValue,omitempty"`
+}
+
+// NewObject returns an object of the given kind with the fields of v, which must
+// marshal to a JSON object
+func NewObject(kind, namespace, name string, v any) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetGroupVersionKind(SchemeGroupVersion.WithKind(kind))
+	obj.SetName(name)
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	return obj, nil
+}
+
+// NewList returns a List of the objects, the way kubectl prints multiple objects
+func NewList(objs ...*unstructured.Unstructured) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{Object: map[string]any{}}
+	list.SetAPIVersion("v1")
+	list.SetKind("List")
+	list.Items = make([]unstructured.Unstructured, 0, len(objs))
+	for _, obj := range objs {
+		list.Items = append(list.Items, *obj)
+	}
+	return list
+}
+
+// BlameEntries returns the BlameEntry objects of the leaves of the blame tree
+func BlameEntries(namespace, target string, tree *sdcpb.BlameTreeElement) (*unstructured.UnstructuredList, error) {
+	var objs []*unstructured.Unstructured
+	var err error
+	tree.WalkPath(&sdcpb.Path{IsRootBased: true}, func(elem *sdcpb.BlameTreeElement, path *sdcpb.Path) {
+		if err != nil || elem.GetValue() == nil {
+			return
+		}
+		entry := BlameEntry{
+			Target: target,
+			Path:   path.ToXPath(false),
+			Value:  elem.GetValue().ToString(),
+			Owner:  elem.GetOwner(),
+		}
+		if elem.IsDeviated() {
+			entry.DeviationValue = elem.GetDeviationValue().ToString()
+		}
+		var obj *unstructured.Unstructured
+		obj, err = NewObject(KindBlameEntry, namespace, entry.Path, entry)
+		objs = append(objs, obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(objs...), nil
+}
+
+// DeviationEntries returns the DeviationEntry objects of the deviations, sorted by
+// target, Deviation resource and path
+func DeviationEntries(devs types.Deviations) (*unstructured.UnstructuredList, error) {
+	type namespaced struct {
+		namespace string
+		entry     DeviationEntry
+	}
+	var all []namespaced
+	for _, intentDevs := range devs {
+		for _, dev := range intentDevs.Deviations() {
+			all = append(all, namespaced{
+				namespace: intentDevs.Namespace(),
+				entry: DeviationEntry{
+					Target:       intentDevs.Target(),
+					Deviation:    intentDevs.Name(),
+					Type:         string(intentDevs.Type()),
+					Path:         dev.Path,
+					ActualValue:  dev.ActualValue,
+					DesiredValue: dev.DesiredValue,
+					Reason:       dev.Reason,
+				},
+			})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		if a.entry.Target != b.entry.Target {
+			return a.entry.Target < b.entry.Target
+		}
+		if a.entry.Deviation != b.entry.Deviation {
+			return a.entry.Deviation < b.entry.Deviation
+		}
+		return a.entry.Path < b.entry.Path
+	})
+
+	objs := make([]*unstructured.Unstructured, 0, len(all))
+	for _, d := range all {
+		obj, err := NewObject(KindDeviationEntry, d.namespace, d.entry.Path, d.entry)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return NewList(objs...), nil
+}
+
+// IntentUpdates returns the IntentUpdate objects of the leaves of an intent, sorted by path
+func IntentUpdates(namespace, target, intentName string, intent *sdcpb.Intent) (*unstructured.UnstructuredList, error) {
+	leaves := types.LeavesFromIntent(intent)
+	objs := make([]*unstructured.Unstructured, 0, len(leaves))
+	for _, path := range leaves.Paths() {
+		obj, err := NewObject(KindIntentUpdate, namespace, path, IntentUpdate{
+			Target: target,
+			Intent: intentName,
+			Path:   path,
+			Value:  leaves[path],
+		})
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return NewList(objs...), nil
+}
+
+// Schemas returns the Schema objects of the schemas, named vendor/version
+func Schemas(schemas []*sdcpb.Schema) (*unstructured.UnstructuredList, error) {
+	objs := make([]*unstructured.Unstructured, 0, len(schemas))
+	for _, s := range schemas {
+		obj, err := NewObject(KindSchema, "", s.GetVendor()+"/"+s.GetVersion(), Schema{
+			Name:    s.GetName(),
+			Vendor:  s.GetVendor(),
+			Version: s.GetVersion(),
+			Status:  s.GetStatus().String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return NewList(objs...), nil
+}
+
+// LeafChanges returns the LeafChange objects of the changes from one configuration to another
+func LeafChanges(namespace, from, to, section string, changes []types.LeafChange) ([]*unstructured.Unstructured, error) {
+	objs := make([]*unstructured.Unstructured, 0, len(changes))
+	for _, c := range changes {
+		obj, err := NewObject(KindLeafChange, namespace, c.Path, LeafChange{
+			From:      from,
+			To:        to,
+			Section:   section,
+			Operation: string(c.Operation),
+			Path:      c.Path,
+			OldValue:  c.Old,
+			NewValue:  c.New,
+		})
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
@@ -0,0 +1,170 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+func testBlameTree() *sdcpb.BlameTreeElement {
+	return &sdcpb.BlameTreeElement{
+		Name: "root",
+		Childs: []*sdcpb.BlameTreeElement{{
+			Name: "system",
+			Childs: []*sdcpb.BlameTreeElement{
+				{Name: "host-name", Owner: "default.intent-a", Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "srl1"}}, DeviationValue: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "leaf1"}}},
+				{Name: "description", Owner: "running", Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "lab"}}},
+			},
+		}},
+	}
+}
+
+func testDeviations() types.Deviations {
+	devs := types.Deviations{}
+	for _, target := range []string{"srl2", "srl1"} {
+		intentDevs := types.NewDeviations(target, target, types.DeviationTypeTarget, 0).SetNamespace("default")
+		intentDevs.AddDeviation(types.NewDeviation("/system/name/host-name", target, "leaf", "NOT_APPLIED"))
+		devs.AddDeviation(intentDevs)
+	}
+	return devs
+}
+
+func printBlame(t *testing.T, format string) string {
+	t.Helper()
+	f := NewPrintFlags()
+	f.OutputFormat = format
+	p, err := f.ToPrinter()
+	if err != nil {
+		t.Fatalf("ToPrinter(%s) error = %v", format, err)
+	}
+	entries, err := BlameEntries("default", "srl1", testBlameTree())
+	if err != nil {
+		t.Fatalf("BlameEntries() error = %v", err)
+	}
+	out := &bytes.Buffer{}
+	if err := p.PrintObj(entries, out); err != nil {
+		t.Fatalf("PrintObj(%s) error = %v", format, err)
+	}
+	return out.String()
+}
+
+func TestPrintFlags_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{format: "json", want: []string{`"kind": "List"`, `"kind": "BlameEntry"`, `"deviationValue": "leaf1"`}},
+		{format: "yaml", want: []string{"apiVersion: kubectl.sdcio.dev/v1alpha1", "namespace: default", "owner: running"}},
+		{format: "name", want: []string{"blameentry.kubectl.sdcio.dev//system/host-name\n", "blameentry.kubectl.sdcio.dev//system/description\n"}},
+		{format: "jsonpath={.items[*].owner}", want: []string{"default.intent-a running"}},
+		{format: "go-template={{range .items}}{{.path}}={{.value}};{{end}}", want: []string{"/system/host-name=srl1;/system/description=lab;"}},
+		{format: "custom-columns=PATH:.path,DEVIATION:{.deviationValue}", want: []string{"PATH", "/system/host-name     leaf1", "/system/description   <none>"}},
+		{format: "wide", want: []string{"PATH", "VALUE", "OWNER", "DEVIATION", "default.intent-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got := printBlame(t, tt.format)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output misses %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintFlags_Aliases(t *testing.T) {
+	f := NewPrintFlags("tree", "wide")
+	for _, format := range []string{"", "tree", "Wide"} {
+		f.OutputFormat = format
+		if p, err := f.ToPrinter(); p != nil || err != nil {
+			t.Fatalf("ToPrinter(%s) = %v, %v, want no printer", format, p, err)
+		}
+	}
+	if alias, ok := f.Alias(); !ok || alias != "wide" {
+		t.Fatalf("Alias() = %s, %v, want wide", alias, ok)
+	}
+
+	var nilFlags *PrintFlags
+	if p, err := nilFlags.ToPrinter(); p != nil || err != nil {
+		t.Fatalf("nil ToPrinter() = %v, %v, want no printer", p, err)
+	}
+}
+
+func TestPrintFlags_Invalid(t *testing.T) {
+	for format, want := range map[string]string{
+		"toml":              `invalid output format "toml", must be one of: json, yaml`,
+		"custom-columns=":   "no custom columns given",
+		"custom-columns=ID": "expected <header>:<json-path-expr>",
+	} {
+		f := NewPrintFlags("tree")
+		f.OutputFormat = format
+		if _, err := f.ToPrinter(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ToPrinter(%s) error = %v, want %q", format, err, want)
+		}
+	}
+	if formats := NewPrintFlags("tree", "wide").AllowedFormats(); formats[len(formats)-1] != "tree" || strings.Count(strings.Join(formats, ","), "wide") != 1 {
+		t.Errorf("AllowedFormats() = %v, want the aliases once at the end", formats)
+	}
+}
+
+func TestDeviationEntries(t *testing.T) {
+	entries, err := DeviationEntries(testDeviations())
+	if err != nil {
+		t.Fatalf("DeviationEntries() error = %v", err)
+	}
+	if len(entries.Items) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries.Items))
+	}
+	for i, target := range []string{"srl1", "srl2"} {
+		item := entries.Items[i]
+		if item.GetKind() != KindDeviationEntry || item.GetNamespace() != "default" || item.Object["target"] != target || item.Object["desiredValue"] != target {
+			t.Errorf("entry %d = %v, want the deviation of %s", i, item.Object, target)
+		}
+	}
+}
+
+func TestIntentUpdates(t *testing.T) {
+	path, err := sdcpb.ParsePath("/interface[name=ethernet-1/1]/admin-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	intent := &sdcpb.Intent{Intent: "running", Update: []*sdcpb.Update{
+		{Path: path, Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "enable"}}},
+	}}
+
+	updates, err := IntentUpdates("default", "srl1", "running", intent)
+	if err != nil {
+		t.Fatalf("IntentUpdates() error = %v", err)
+	}
+	if len(updates.Items) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates.Items))
+	}
+	item := updates.Items[0]
+	if item.GetName() != "/interface[name=ethernet-1/1]/admin-state" || item.Object["intent"] != "running" || item.Object["value"] != "enable" {
+		t.Fatalf("update = %v, want the admin-state leaf", item.Object)
+	}
+}
+
+func TestLeafChanges(t *testing.T) {
+	changes, err := LeafChanges("default", "srl1", "srl2", "", []types.LeafChange{
+		{Operation: types.LeafChanged, Path: "/system/name/host-name", Old: "leaf1", New: "leaf2"},
+		{Operation: types.LeafRemoved, Path: "/system/description", Old: "lab"},
+	})
+	if err != nil {
+		t.Fatalf("LeafChanges() error = %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2", len(changes))
+	}
+	first := changes[0]
+	if first.GetKind() != KindLeafChange || first.GetName() != "/system/name/host-name" || first.Object["operation"] != "changed" || first.Object["oldValue"] != "leaf1" || first.Object["newValue"] != "leaf2" {
+		t.Errorf("change = %v, want the host-name change", first.Object)
+	}
+	if _, ok := changes[1].Object["newValue"]; ok || changes[1].Object["from"] != "srl1" {
+		t.Errorf("change = %v, want a removal from srl1 without new value", changes[1].Object)
+	}
+}
@@ -0,0 +1,117 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+const (
+	formatWide          = "wide"
+	formatCustomColumns = "custom-columns"
+)
+
+// PrintFlags is the -o/--output flag shared by the commands. Besides the printer
+// formats, it accepts the native formats of a command as aliases, which take
+// precedence over the printer formats of the same name.
+type PrintFlags struct {
+	OutputFormat string
+
+	aliases       []string
+	jsonYamlFlags *genericclioptions.JSONYamlPrintFlags
+	nameFlags     *genericclioptions.NamePrintFlags
+	templateFlags *genericclioptions.KubeTemplatePrintFlags
+}
+
+// NewPrintFlags returns the print flags of a command with the given native formats
+func NewPrintFlags(aliases ...string) *PrintFlags {
+	return &PrintFlags{
+		aliases:       aliases,
+		jsonYamlFlags: genericclioptions.NewJSONYamlPrintFlags(),
+		nameFlags:     genericclioptions.NewNamePrintFlags(""),
+		templateFlags: genericclioptions.NewKubeTemplatePrintFlags(),
+	}
+}
+
+// AllowedFormats returns the printer formats followed by the aliases
+func (f *PrintFlags) AllowedFormats() []string {
+	formats := f.jsonYamlFlags.AllowedFormats()
+	formats = append(formats, f.nameFlags.AllowedFormats()...)
+	formats = append(formats, formatWide)
+	formats = append(formats, f.templateFlags.AllowedFormats()...)
+	formats = append(formats, formatCustomColumns)
+	for _, alias := range f.aliases {
+		if !slices.Contains(formats, alias) {
+			formats = append(formats, alias)
+		}
+	}
+	return formats
+}
+
+// AddFlags adds the -o/--output flag, with completion, and the --template flags to the command
+func (f *PrintFlags) AddFlags(cmd *cobra.Command) error {
+	cmd.Flags().StringVarP(&f.OutputFormat, "output", "o", "", fmt.Sprintf("output format, one of: %s. Formats taking an argument are given as format=argument, e.g. jsonpath={.items[*].path}", strings.Join(f.AllowedFormats(), ", ")))
+	f.templateFlags.AddFlags(cmd)
+
+	return cmd.RegisterFlagCompletionFunc("output", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		var formats []string
+		for _, format := range f.AllowedFormats() {
+			if slices.Contains(f.templateFlags.AllowedFormats(), format) || format == formatCustomColumns {
+				format += "="
+			}
+			formats = append(formats, format)
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// Alias returns the native format selected by -o, if it is one of the aliases
+func (f *PrintFlags) Alias() (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	for _, alias := range f.aliases {
+		if strings.EqualFold(f.OutputFormat, alias) {
+			return alias, true
+		}
+	}
+	return "", false
+}
+
+// ToPrinter returns the printer of the output format. It returns nil, without an
+// error, when no output format is set or the output format is an alias.
+func (f *PrintFlags) ToPrinter() (printers.ResourcePrinter, error) {
+	if f == nil || f.OutputFormat == "" {
+		return nil, nil
+	}
+	if _, ok := f.Alias(); ok {
+		return nil, nil
+	}
+
+	if strings.EqualFold(f.OutputFormat, formatWide) {
+		return NewWidePrinter(), nil
+	}
+	if spec, ok := strings.CutPrefix(f.OutputFormat, formatCustomColumns+"="); ok {
+		columns, err := ParseCustomColumns(spec)
+		if err != nil {
+			return nil, err
+		}
+		return NewCustomColumnsPrinter(columns), nil
+	}
+
+	if p, err := f.jsonYamlFlags.ToPrinter(f.OutputFormat); !genericclioptions.IsNoCompatiblePrinterError(err) {
+		return p, err
+	}
+	if p, err := f.nameFlags.ToPrinter(f.OutputFormat); !genericclioptions.IsNoCompatiblePrinterError(err) {
+		return p, err
+	}
+	if p, err := f.templateFlags.ToPrinter(f.OutputFormat); !genericclioptions.IsNoCompatiblePrinterError(err) {
+		return p, err
+	}
+
+	return nil, fmt.Errorf("invalid output format %q, must be one of: %s", f.OutputFormat, strings.Join(f.AllowedFormats(), ", "))
+}
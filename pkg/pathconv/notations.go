@@ -0,0 +1,264 @@
+package pathconv
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+func parseGNMI(s string, n Notation) (Path, error) {
+	sp := &sdcpb.Path{}
+	var err error
+	if n == NotationGNMIJSON {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal([]byte(s), sp)
+	} else {
+		err = prototext.Unmarshal([]byte(s), sp)
+	}
+	if err != nil {
+		return Path{}, err
+	}
+	return FromSDCPB(sp), nil
+}
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// jsonPointer returns the JSON pointer of the path into doc. Without doc the
+// members are named like JSON IETF does, list entries cannot be addressed.
+func (p Path) jsonPointer(doc any) (string, error) {
+	sb := &strings.Builder{}
+	value := doc
+	for i, name := range p.qualifiedNames() {
+		e := p.Elems[i]
+		if doc != nil {
+			container, ok := value.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("%s is not a container or list entry", e.Name)
+			}
+			// use the member name of the document, it is qualified by the schema
+			if name, value, ok = member(container, e.Name); !ok {
+				return "", fmt.Errorf("%s not found in the configuration", e.Name)
+			}
+		}
+		sb.WriteString("/" + jsonPointerEscaper.Replace(name))
+		if len(e.Keys) == 0 {
+			continue
+		}
+
+		if doc == nil {
+			return "", fmt.Errorf("the position of the list entry %s is unknown: %w", p.entryXPath(i), ErrDocumentRequired)
+		}
+		entries, ok := value.([]any)
+		if !ok {
+			return "", fmt.Errorf("%s is not a list", e.Name)
+		}
+		pos := listEntry(entries, e.Keys)
+		if pos < 0 {
+			return "", fmt.Errorf("%s not found in the configuration", p.entryXPath(i))
+		}
+		fmt.Fprintf(sb, "/%d", pos)
+		value = entries[pos]
+	}
+	return sb.String(), nil
+}
+
+// entryXPath returns the XPath of the element i without its parents
+func (p Path) entryXPath(i int) string {
+	return strings.TrimPrefix(Path{Elems: p.Elems[i : i+1]}.WithoutModules().XPath(), "/")
+}
+
+func parseJSONPointer(ctx context.Context, s string, opts Options) (Path, error) {
+	if s == "" || s == "/" {
+		return Path{}, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return Path{}, fmt.Errorf("a JSON pointer starts with /")
+	}
+	tokens := strings.Split(s[1:], "/")
+	for i, tok := range tokens {
+		tokens[i] = jsonPointerUnescaper.Replace(tok)
+	}
+
+	p := Path{}
+	value := opts.Document
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if opts.Document == nil {
+			// YANG identifiers do not start with a digit, the token is the position of a list entry
+			if _, err := strconv.Atoi(tok); err == nil {
+				return Path{}, fmt.Errorf("the list entry at position %s is unknown: %w", tok, ErrDocumentRequired)
+			}
+		} else {
+			container, ok := value.(map[string]any)
+			if !ok {
+				return Path{}, fmt.Errorf("%s is not a container or list entry", tok)
+			}
+			if value, ok = container[tok]; !ok {
+				return Path{}, fmt.Errorf("%s not found in the configuration", tok)
+			}
+		}
+		e := Elem{Name: tok}
+		if module, name, ok := strings.Cut(tok, ":"); ok {
+			e.Module, e.Name = module, name
+		}
+		p.Elems = append(p.Elems, e)
+
+		entries, isList := value.([]any)
+		if !isList || i == len(tokens)-1 {
+			continue
+		}
+		// the next token is the position of the list entry
+		i++
+		pos, err := strconv.Atoi(tokens[i])
+		if err != nil || pos < 0 || pos >= len(entries) {
+			return Path{}, fmt.Errorf("%s is not a position of the list %s", tokens[i], e.Name)
+		}
+		entry, ok := entries[pos].(map[string]any)
+		if !ok {
+			return Path{}, fmt.Errorf("%s is a leaf-list, its entries have no path", e.Name)
+		}
+		if opts.Resolver == nil {
+			return Path{}, fmt.Errorf("the key names of the list %s are unknown: %w", e.Name, ErrSchemaRequired)
+		}
+		_, keyNames, err := opts.Resolver.Resolve(ctx, p)
+		if err != nil {
+			return Path{}, fmt.Errorf("failed to resolve %s: %w", p.withoutKeys().XPath(), err)
+		}
+		last := &p.Elems[len(p.Elems)-1]
+		for _, k := range keyNames {
+			_, kv, ok := member(entry, k)
+			if !ok {
+				return Path{}, fmt.Errorf("the entry %d of the list %s has no key %s", pos, e.Name, k)
+			}
+			last.Keys = append(last.Keys, Key{Name: k, Value: fmt.Sprint(kv)})
+		}
+		value = entry
+	}
+	return p, nil
+}
+
+// member returns the member of the container with the name. JSON IETF qualifies
+// member names with their module where it changes, so the names match with and
+// without the module.
+func member(container map[string]any, name string) (string, any, bool) {
+	if v, ok := container[name]; ok {
+		return name, v, true
+	}
+	for k, v := range container {
+		if _, local, ok := strings.Cut(k, ":"); ok && local == name {
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
+
+// listEntry returns the position of the entry with the keys, -1 if not found
+func listEntry(entries []any, keys []Key) int {
+	for i, e := range entries {
+		entry, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		matches := true
+		for _, k := range keys {
+			if _, kv, ok := member(entry, k.Name); !ok || fmt.Sprint(kv) != k.Value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return i
+		}
+	}
+	return -1
+}
+
+const restconfData = "/restconf/data"
+
+// restconf returns the RESTCONF data resource path. The key values follow the
+// list name in the order of the keys, which is the schema order only if the
+// path is resolved.
+func (p Path) restconf() string {
+	sb := &strings.Builder{}
+	sb.WriteString(restconfData)
+	for i, name := range p.qualifiedNames() {
+		sb.WriteString("/" + name)
+		keys := p.Elems[i].Keys
+		if len(keys) == 0 {
+			continue
+		}
+		values := make([]string, len(keys))
+		for j, k := range keys {
+			values[j] = restconfEscape(k.Value)
+		}
+		sb.WriteString("=" + strings.Join(values, ","))
+	}
+	return sb.String()
+}
+
+// restconfEscape percent-encodes all but the unreserved characters, as RFC 8040
+// requires for the reserved characters in key values
+func restconfEscape(s string) string {
+	sb := &strings.Builder{}
+	for _, b := range []byte(s) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(sb, "%%%02X", b)
+		}
+	}
+	return sb.String()
+}
+
+// parseRESTCONF parses a RESTCONF data resource path or URL. The names of the
+// keys are left empty, the path needs to be resolved.
+func parseRESTCONF(s string) (Path, error) {
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return Path{}, err
+		}
+		// the escaped path keeps the encoded slashes of the key values
+		s = u.EscapedPath()
+	}
+	s, _, _ = strings.Cut(s, "?")
+	if _, after, ok := strings.Cut(s, restconfData); ok {
+		s = after
+	}
+
+	p := Path{}
+	for _, seg := range strings.Split(s, "/") {
+		if seg == "" {
+			continue
+		}
+		nameStr, keysStr, hasKeys := strings.Cut(seg, "=")
+		name, err := url.PathUnescape(nameStr)
+		if err != nil {
+			return Path{}, err
+		}
+		e := Elem{Name: name}
+		if module, local, ok := strings.Cut(name, ":"); ok {
+			e.Module, e.Name = module, local
+		}
+		if hasKeys {
+			for _, v := range strings.Split(keysStr, ",") {
+				value, err := url.PathUnescape(v)
+				if err != nil {
+					return Path{}, err
+				}
+				e.Keys = append(e.Keys, Key{Value: value})
+			}
+		}
+		p.Elems = append(p.Elems, e)
+	}
+	return p, nil
+}
@@ -0,0 +1,390 @@
+// Package pathconv converts paths between the notations used around SDC and
+// gNMI: XPath, gNMI paths in prototext and JSON, JSON pointers into a JSON IETF
+// document and RESTCONF data resource paths.
+//
+// Some notations leave out information the others need: RESTCONF and JSON
+// pointers only carry the values of list keys, JSON pointers address list
+// entries by position. A Resolver fills in the modules and key names from the
+// schema and a Document resolves the positions of list entries.
+package pathconv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sdcio/kubectl-sdc/pkg/gnmi"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// Notation is a path notation
+type Notation string
+
+const (
+	// NotationXPath is the XPath notation used by SDC, e.g. /interface[name=ethernet-1/1]/mtu
+	NotationXPath Notation = "xpath"
+	// NotationGNMIPrototext is a gNMI path in prototext
+	NotationGNMIPrototext Notation = "gnmi-prototext"
+	// NotationGNMIJSON is a gNMI path in the protobuf JSON mapping
+	NotationGNMIJSON Notation = "gnmi-json"
+	// NotationJSONPointer is a JSON pointer (RFC 6901) into the JSON IETF configuration
+	NotationJSONPointer Notation = "json-pointer"
+	// NotationRESTCONF is a RESTCONF data resource path (RFC 8040)
+	NotationRESTCONF Notation = "restconf"
+)
+
+// Notations lists the path notations
+var Notations = []Notation{NotationXPath, NotationGNMIPrototext, NotationGNMIJSON, NotationJSONPointer, NotationRESTCONF}
+
+// NotationStrings returns the path notations as strings
+func NotationStrings() []string {
+	notations := make([]string, len(Notations))
+	for i, n := range Notations {
+		notations[i] = string(n)
+	}
+	return notations
+}
+
+// ParseNotation parses a path notation, case insensitive
+func ParseNotation(s string) (Notation, error) {
+	n := Notation(strings.ToLower(s))
+	if !slices.Contains(Notations, n) {
+		return "", fmt.Errorf("invalid path notation %q, must be one of: %s", s, strings.Join(NotationStrings(), ", "))
+	}
+	return n, nil
+}
+
+var prototextPath = regexp.MustCompile(`^(elem|origin|target)\s*(:\s*)?[{"<]`)
+
+// Detect returns the notation of s. JSON pointers look like XPaths without keys,
+// so they are never detected.
+func Detect(s string) Notation {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "{"):
+		return NotationGNMIJSON
+	case prototextPath.MatchString(s):
+		return NotationGNMIPrototext
+	case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"), strings.Contains(s, restconfData):
+		return NotationRESTCONF
+	default:
+		return NotationXPath
+	}
+}
+
+// ErrSchemaRequired is returned when a path can only be converted with the schema
+var ErrSchemaRequired = errors.New("the schema is required")
+
+// ErrDocumentRequired is returned when a path can only be converted with the configuration
+var ErrDocumentRequired = errors.New("the configuration is required")
+
+// Key is a list key. The name of a key parsed from a RESTCONF path is empty until
+// the path is resolved.
+type Key struct {
+	Name  string
+	Value string
+}
+
+// Elem is a path element
+type Elem struct {
+	Name string
+	// Module is the YANG module of the element, if known
+	Module string
+	// Keys are in schema order once the path is resolved
+	Keys []Key
+}
+
+// Path is a path independent of its notation
+type Path struct {
+	Origin string
+	Elems  []Elem
+}
+
+// Resolver resolves the schema information of a path
+type Resolver interface {
+	// Resolve returns the module of the last element of p and, for a list, the
+	// names of its keys in schema order
+	Resolve(ctx context.Context, p Path) (module string, keys []string, err error)
+}
+
+// Options are the optional inputs of a conversion
+type Options struct {
+	// Document is the JSON IETF configuration the JSON pointers point into,
+	// as decoded by ReadDocument
+	Document any
+	// Resolver resolves the modules and list keys from the schema
+	Resolver Resolver
+}
+
+// ReadDocument decodes a JSON IETF configuration. Numbers are kept as they are,
+// so key values compare as they are written.
+func ReadDocument(r io.Reader) (any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON IETF configuration: %w", err)
+	}
+	return doc, nil
+}
+
+// Convert converts s from a notation to another. An empty from detects the notation.
+func Convert(ctx context.Context, s string, from, to Notation, opts Options) (string, error) {
+	p, err := Parse(ctx, s, from, opts)
+	if err != nil {
+		return "", err
+	}
+	return p.Format(to, opts)
+}
+
+// Parse parses s in the notation n, an empty n detects the notation. With a
+// Resolver the path is resolved.
+func Parse(ctx context.Context, s string, n Notation, opts Options) (Path, error) {
+	s = strings.TrimSpace(s)
+	if n == "" {
+		n = Detect(s)
+	}
+
+	var p Path
+	var err error
+	switch n {
+	case NotationXPath:
+		p, err = parseXPath(s)
+	case NotationGNMIPrototext, NotationGNMIJSON:
+		p, err = parseGNMI(s, n)
+	case NotationJSONPointer:
+		p, err = parseJSONPointer(ctx, s, opts)
+	case NotationRESTCONF:
+		p, err = parseRESTCONF(s)
+	default:
+		_, err = ParseNotation(string(n))
+	}
+	if err != nil {
+		return Path{}, fmt.Errorf("invalid %s path %q: %w", n, s, err)
+	}
+
+	if opts.Resolver != nil {
+		if p, err = p.Resolve(ctx, opts.Resolver); err != nil {
+			return Path{}, err
+		}
+	}
+	for _, e := range p.Elems {
+		for _, k := range e.Keys {
+			if k.Name == "" {
+				return Path{}, fmt.Errorf("the key names of the list %s are unknown: %w", e.Name, ErrSchemaRequired)
+			}
+		}
+	}
+	return p, nil
+}
+
+// Resolve returns the path with the modules and key names resolved by r. The
+// keys are sorted in schema order.
+func (p Path) Resolve(ctx context.Context, r Resolver) (Path, error) {
+	resolved := Path{Origin: p.Origin, Elems: make([]Elem, 0, len(p.Elems))}
+	for _, e := range p.Elems {
+		resolved.Elems = append(resolved.Elems, e)
+		if e.Name == "*" || e.Name == "..." {
+			// wildcards have no schema
+			continue
+		}
+		module, keyNames, err := r.Resolve(ctx, resolved)
+		if err != nil {
+			return Path{}, fmt.Errorf("failed to resolve %s: %w", resolved.withoutKeys().XPath(), err)
+		}
+		last := &resolved.Elems[len(resolved.Elems)-1]
+		if last.Module == "" {
+			last.Module = module
+		}
+		if last.Keys, err = resolveKeys(last.Name, e.Keys, keyNames); err != nil {
+			return Path{}, err
+		}
+	}
+	return resolved, nil
+}
+
+// resolveKeys names positional keys and sorts named keys in schema order
+func resolveKeys(list string, keys []Key, names []string) ([]Key, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	resolved := slices.Clone(keys)
+	if keys[0].Name == "" {
+		if len(keys) != len(names) {
+			return nil, fmt.Errorf("the list %s has %d keys, got %d values", list, len(names), len(keys))
+		}
+		for i := range resolved {
+			resolved[i].Name = names[i]
+		}
+		return resolved, nil
+	}
+	index := func(k Key) int {
+		if i := slices.Index(names, k.Name); i >= 0 {
+			return i
+		}
+		return len(names)
+	}
+	slices.SortStableFunc(resolved, func(a, b Key) int { return index(a) - index(b) })
+	return resolved, nil
+}
+
+// withoutKeys returns the path without the keys of its elements, the path of its schema
+func (p Path) withoutKeys() Path {
+	stripped := Path{Origin: p.Origin, Elems: slices.Clone(p.Elems)}
+	for i := range stripped.Elems {
+		stripped.Elems[i].Keys = nil
+	}
+	return stripped
+}
+
+// WithoutModules returns the path without the modules of its elements
+func (p Path) WithoutModules() Path {
+	stripped := Path{Origin: p.Origin, Elems: slices.Clone(p.Elems)}
+	for i := range stripped.Elems {
+		stripped.Elems[i].Module = ""
+	}
+	return stripped
+}
+
+// Format formats the path in the notation n. JSON pointers to list entries
+// need the Document.
+func (p Path) Format(n Notation, opts Options) (string, error) {
+	switch n {
+	case NotationXPath:
+		return p.XPath(), nil
+	case NotationGNMIPrototext:
+		return gnmi.PathPrototext(p.SDCPB()), nil
+	case NotationGNMIJSON:
+		return gnmi.PathJSON(p.SDCPB())
+	case NotationJSONPointer:
+		return p.jsonPointer(opts.Document)
+	case NotationRESTCONF:
+		return p.restconf(), nil
+	default:
+		_, err := ParseNotation(string(n))
+		return "", err
+	}
+}
+
+// qualifiedNames returns the element names, qualified with their module where
+// it differs from the module of the parent, like JSON IETF and RESTCONF do
+func (p Path) qualifiedNames() []string {
+	names := make([]string, len(p.Elems))
+	parent := ""
+	for i, e := range p.Elems {
+		names[i] = e.Name
+		if e.Module != "" && e.Module != parent {
+			names[i] = e.Module + ":" + e.Name
+		}
+		if e.Module != "" {
+			parent = e.Module
+		}
+	}
+	return names
+}
+
+// XPath returns the root based XPath of the path
+func (p Path) XPath() string {
+	sb := &strings.Builder{}
+	if p.Origin != "" {
+		sb.WriteString(p.Origin + ":")
+	}
+	for i, name := range p.qualifiedNames() {
+		sb.WriteString("/" + name)
+		for _, k := range p.Elems[i].Keys {
+			fmt.Fprintf(sb, "[%s=%s]", k.Name, xpathKeyReplacer.Replace(k.Value))
+		}
+	}
+	if len(p.Elems) == 0 {
+		sb.WriteString("/")
+	}
+	return sb.String()
+}
+
+var xpathKeyReplacer = strings.NewReplacer(`[`, `\[`, `]`, `\]`)
+
+// SDCPB returns the path as a sdcpb path, the element names qualified with their module
+func (p Path) SDCPB() *sdcpb.Path {
+	sp := &sdcpb.Path{Origin: p.Origin, IsRootBased: true}
+	for i, name := range p.qualifiedNames() {
+		pe := &sdcpb.PathElem{Name: name}
+		for _, k := range p.Elems[i].Keys {
+			if pe.Key == nil {
+				pe.Key = map[string]string{}
+			}
+			pe.Key[k.Name] = k.Value
+		}
+		sp.Elem = append(sp.Elem, pe)
+	}
+	return sp
+}
+
+// FromSDCPB returns the path of a sdcpb path, module qualified names are split
+// into the module and the name
+func FromSDCPB(sp *sdcpb.Path) Path {
+	p := Path{Origin: sp.GetOrigin()}
+	for _, pe := range sp.GetElem() {
+		e := Elem{Name: pe.GetName()}
+		if module, name, ok := strings.Cut(pe.GetName(), ":"); ok {
+			e.Module, e.Name = module, name
+		}
+		for k, v := range pe.GetKey() {
+			e.Keys = append(e.Keys, Key{Name: k, Value: v})
+		}
+		// the schema order is unknown, the keys are sorted by name
+		slices.SortFunc(e.Keys, func(a, b Key) int { return strings.Compare(a.Name, b.Name) })
+		p.Elems = append(p.Elems, e)
+	}
+	return p
+}
+
+// HasModules reports whether an element of the path has a module
+func (p Path) HasModules() bool {
+	return slices.ContainsFunc(p.Elems, func(e Elem) bool { return e.Module != "" })
+}
+
+func parseXPath(s string) (Path, error) {
+	sp, err := sdcpb.ParsePath(s)
+	if err != nil {
+		return Path{}, err
+	}
+	return FromSDCPB(sp), nil
+}
+
+// Normalize returns the XPath of a path in any notation but a JSON pointer, so
+// the path filters accept the paths copied from gNMI and RESTCONF tools. The
+// modules are left out, like in the paths of SDC. XPaths without modules are
+// returned as they are, keeping their wildcards and partial prefixes.
+func Normalize(s string) (string, error) {
+	if Detect(s) == NotationXPath {
+		if !strings.Contains(s, ":") {
+			return s, nil
+		}
+		if p, err := parseXPath(s); err != nil || !p.HasModules() {
+			return s, nil
+		}
+	}
+	p, err := Parse(context.Background(), s, "", Options{})
+	if err != nil {
+		return "", err
+	}
+	return p.WithoutModules().XPath(), nil
+}
+
+// NormalizeAll normalizes the paths
+func NormalizeAll(paths []string) ([]string, error) {
+	var normalized []string
+	for _, s := range paths {
+		p, err := Normalize(s)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, p)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,205 @@
+package pathconv
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	mockschema "github.com/sdcio/kubectl-sdc/mocks/schema"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"go.uber.org/mock/gomock"
+)
+
+// testResolver resolves the elements by name from a schema of srl_nokia
+type testResolver struct{}
+
+func (testResolver) Resolve(_ context.Context, p Path) (string, []string, error) {
+	switch last := p.Elems[len(p.Elems)-1].Name; last {
+	case "interface":
+		return "srl_nokia-interfaces", []string{"name"}, nil
+	case "acl-filter":
+		return "srl_nokia-acl", []string{"name", "type"}, nil
+	case "entry":
+		return "srl_nokia-acl", []string{"sequence-id"}, nil
+	case "acl":
+		return "srl_nokia-acl", nil, nil
+	case "unknown":
+		return "", nil, errors.New("not found")
+	default:
+		// the parent module
+		return "", nil, nil
+	}
+}
+
+const testDocument = `{
+  "srl_nokia-interfaces:interface": [
+    {"name": "mgmt0", "mtu": 1500},
+    {"name": "ethernet-1/1", "mtu": 9232}
+  ],
+  "srl_nokia-acl:acl": {
+    "acl-filter": [
+      {"name": "f1", "type": "ipv4", "entry": [{"sequence-id": 10}, {"sequence-id": 20}]}
+    ]
+  }
+}`
+
+func testOptions(t *testing.T) Options {
+	t.Helper()
+	doc, err := ReadDocument(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatalf("ReadDocument() unexpected error: %v", err)
+	}
+	return Options{Document: doc, Resolver: testResolver{}}
+}
+
+func TestConvert(t *testing.T) {
+	const xpath = "/interface[name=ethernet-1/1]/mtu"
+	const resolved = "/srl_nokia-interfaces:interface[name=ethernet-1/1]/mtu"
+	const acl = "/srl_nokia-acl:acl/acl-filter[name=f1][type=ipv4]/entry[sequence-id=20]"
+
+	tests := []struct {
+		name string
+		in   string
+		from Notation
+		to   Notation
+		opts bool
+		want string
+	}{
+		{name: "xpath to prototext", in: xpath, to: NotationGNMIPrototext, want: `elem: {name: "interface" key: {key: "name" value: "ethernet-1/1"}} elem: {name: "mtu"}`},
+		{name: "xpath to gnmi json", in: xpath, to: NotationGNMIJSON, want: `{"elem":[{"name":"interface","key":{"name":"ethernet-1/1"}},{"name":"mtu"}]}`},
+		{name: "xpath to restconf", in: xpath, to: NotationRESTCONF, want: "/restconf/data/interface=ethernet-1%2F1/mtu"},
+		{name: "resolved xpath", in: xpath, to: NotationXPath, opts: true, want: resolved},
+		{name: "resolved restconf", in: xpath, to: NotationRESTCONF, opts: true, want: "/restconf/data/srl_nokia-interfaces:interface=ethernet-1%2F1/mtu"},
+		{name: "xpath to json pointer", in: xpath, to: NotationJSONPointer, opts: true, want: "/srl_nokia-interfaces:interface/1/mtu"},
+		{name: "json pointer to xpath", in: "/srl_nokia-interfaces:interface/1/mtu", from: NotationJSONPointer, to: NotationXPath, opts: true, want: resolved},
+		{name: "json pointer with escapes", in: "/srl_nokia-acl:acl/acl-filter/0/entry/1", from: NotationJSONPointer, to: NotationXPath, opts: true, want: acl},
+		{name: "keys in schema order", in: "/acl/acl-filter[type=ipv4][name=f1]/entry[sequence-id=20]", to: NotationRESTCONF, opts: true, want: "/restconf/data/srl_nokia-acl:acl/acl-filter=f1,ipv4/entry=20"},
+		{name: "restconf url to xpath", in: "https://leaf1/restconf/data/srl_nokia-acl:acl/acl-filter=f1,ipv4/entry=20?depth=1", to: NotationXPath, opts: true, want: acl},
+		{name: "restconf without keys", in: "/restconf/data/srl_nokia-acl:acl", to: NotationXPath, want: "/srl_nokia-acl:acl"},
+		{name: "prototext to xpath", in: `origin: "srl" elem {name: "interface" key {key: "name" value: "mgmt0"}}`, to: NotationXPath, want: "srl:/interface[name=mgmt0]"},
+		{name: "gnmi json to xpath", in: `{"elem":[{"name":"system"},{"name":"name"}]}`, to: NotationXPath, want: "/system/name"},
+		{name: "escaped key", in: `/a[k=x\]y]`, to: NotationGNMIPrototext, want: `elem: {name: "a" key: {key: "k" value: "x]y"}}`},
+		{name: "root", in: "/", to: NotationRESTCONF, want: "/restconf/data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{}
+			if tt.opts {
+				opts = testOptions(t)
+			}
+			got, err := Convert(context.Background(), tt.in, tt.from, tt.to, opts)
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("Convert() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		from    Notation
+		to      Notation
+		opts    bool
+		wantErr error
+		want    string
+	}{
+		{name: "restconf keys without schema", in: "/restconf/data/interface=mgmt0", to: NotationXPath, wantErr: ErrSchemaRequired},
+		{name: "json pointer without document", in: "/interface/0", from: NotationJSONPointer, to: NotationXPath, wantErr: ErrDocumentRequired},
+		{name: "list entry without document", in: "/interface[name=mgmt0]", to: NotationJSONPointer, wantErr: ErrDocumentRequired},
+		{name: "missing list entry", in: "/interface[name=ethernet-1/2]", to: NotationJSONPointer, opts: true, want: "interface[name=ethernet-1/2] not found in the configuration"},
+		{name: "wrong number of keys", in: "/restconf/data/interface=a,b", to: NotationXPath, opts: true, want: "the list interface has 1 keys, got 2 values"},
+		{name: "unknown element", in: "/unknown", to: NotationXPath, opts: true, want: "failed to resolve /unknown: not found"},
+		{name: "unknown notation", in: "/a", to: "yang", want: `invalid path notation "yang", must be one of: xpath, gnmi-prototext, gnmi-json, json-pointer, restconf`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{}
+			if tt.opts {
+				opts = testOptions(t)
+			}
+			_, err := Convert(context.Background(), tt.in, tt.from, tt.to, opts)
+			if err == nil {
+				t.Fatal("Convert() expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != "" && err.Error() != tt.want {
+				t.Fatalf("Convert() error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := map[string]Notation{
+		"/interface[name=mgmt0]":                NotationXPath,
+		"openconfig:/interfaces":                NotationXPath,
+		`elem: {name: "interface"}`:             NotationGNMIPrototext,
+		`origin: "openconfig" elem {name: "a"}`: NotationGNMIPrototext,
+		` {"elem":[{"name":"a"}]}`:              NotationGNMIJSON,
+		"/restconf/data/interface=mgmt0":        NotationRESTCONF,
+		"https://leaf1/restconf/data/a":         NotationRESTCONF,
+	}
+	for s, want := range tests {
+		if got := Detect(s); got != want {
+			t.Errorf("Detect(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"/interface[name=ethernet-1/":                 "/interface[name=ethernet-1/",
+		"/interface[name=*]/mtu":                      "/interface[name=*]/mtu",
+		"/x[address=2001:db8::1]":                     "/x[address=2001:db8::1]",
+		"/srl_nokia-interfaces:interface[name=mgmt0]": "/interface[name=mgmt0]",
+		`elem: {name: "system"} elem: {name: "name"}`: "/system/name",
+		"/restconf/data/srl_nokia-system:system/name": "/system/name",
+	}
+	for s, want := range tests {
+		got, err := Normalize(s)
+		if err != nil {
+			t.Fatalf("Normalize(%q) unexpected error: %v", s, err)
+		}
+		if got != want {
+			t.Errorf("Normalize(%q) = %s, want %s", s, got, want)
+		}
+	}
+
+	if _, err := NormalizeAll([]string{"/system", "/restconf/data/interface=mgmt0"}); !errors.Is(err, ErrSchemaRequired) {
+		t.Fatalf("NormalizeAll() error = %v, want %v", err, ErrSchemaRequired)
+	}
+}
+
+func TestSchemaResolver(t *testing.T) {
+	schema := &sdcpb.Schema{Vendor: "Nokia", Version: "24.10.1"}
+	ctrl := gomock.NewController(t)
+	cl := mockschema.NewMockSchemaClient(ctrl)
+	cl.EXPECT().GetSchemaElem(gomock.Any(), schema, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *sdcpb.Schema, p *sdcpb.Path) (*sdcpb.SchemaElem, error) {
+			switch p.ToXPath(false) {
+			case "/interface":
+				return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{
+					Name: "interface", ModuleName: "srl_nokia-interfaces", Keys: []*sdcpb.LeafSchema{{Name: "name"}},
+				}}}, nil
+			case "/interface/mtu":
+				return &sdcpb.SchemaElem{Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{Name: "mtu", ModuleName: "srl_nokia-interfaces"}}}, nil
+			}
+			return nil, errors.New("unknown path " + p.ToXPath(false))
+		}).AnyTimes()
+
+	got, err := Convert(context.Background(), "/restconf/data/interface=mgmt0/mtu", "", NotationXPath, Options{Resolver: &SchemaResolver{Client: cl, Schema: schema}})
+	if err != nil {
+		t.Fatalf("Convert() unexpected error: %v", err)
+	}
+	if want := "/srl_nokia-interfaces:interface[name=mgmt0]/mtu"; got != want {
+		t.Fatalf("Convert() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package pathconv
+
+import (
+	"context"
+	"fmt"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// SchemaClient fetches schema elements from the schema-server
+type SchemaClient interface {
+	GetSchemaElem(ctx context.Context, schema *sdcpb.Schema, path *sdcpb.Path) (*sdcpb.SchemaElem, error)
+}
+
+// SchemaResolver resolves paths from a schema of the schema-server
+type SchemaResolver struct {
+	Client SchemaClient
+	Schema *sdcpb.Schema
+}
+
+// Resolve returns the module of the last element of p and the keys of a list
+func (r *SchemaResolver) Resolve(ctx context.Context, p Path) (string, []string, error) {
+	// the schema-server looks up the elements by name, keys and modules are irrelevant
+	sp := &sdcpb.Path{IsRootBased: true}
+	for _, e := range p.Elems {
+		sp.Elem = append(sp.Elem, &sdcpb.PathElem{Name: e.Name})
+	}
+	elem, err := r.Client.GetSchemaElem(ctx, r.Schema, sp)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch s := elem.GetSchema().(type) {
+	case *sdcpb.SchemaElem_Container:
+		keys := make([]string, 0, len(s.Container.GetKeys()))
+		for _, k := range s.Container.GetKeys() {
+			keys = append(keys, k.GetName())
+		}
+		return s.Container.GetModuleName(), keys, nil
+	case *sdcpb.SchemaElem_Field:
+		return s.Field.GetModuleName(), nil, nil
+	case *sdcpb.SchemaElem_Leaflist:
+		return s.Leaflist.GetModuleName(), nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown schema element %T", s)
+	}
+}
@@ -0,0 +1,177 @@
+// Package render holds the terminal rendering shared by the commands: whether
+// colors are used, the characters of the trees, and the colors of values, blame
+// owners, deviations and diffs.
+//
+// Setup configures the rendering once per command, from the --color and --ascii
+// flags and the output stream. The colors follow github.com/fatih/color, so code
+// using that package directly is switched on and off along with the renderers.
+package render
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	"golang.org/x/term"
+)
+
+// ColorMode selects when colors are used
+type ColorMode string
+
+const (
+	// ColorAuto uses colors when writing to a terminal, unless NO_COLOR is set or TERM is dumb
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// ColorModes lists the color modes, the first is the default
+var ColorModes = []ColorMode{ColorAuto, ColorAlways, ColorNever}
+
+// ColorModeStrings returns the color modes as strings
+func ColorModeStrings() []string {
+	modes := make([]string, len(ColorModes))
+	for i, m := range ColorModes {
+		modes[i] = string(m)
+	}
+	return modes
+}
+
+// ParseColorMode parses a color mode, case insensitive
+func ParseColorMode(s string) (ColorMode, error) {
+	switch m := ColorMode(strings.ToLower(s)); m {
+	case ColorAuto, ColorAlways, ColorNever:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid color mode %q, must be one of: %s", s, strings.Join(ColorModeStrings(), ", "))
+	}
+}
+
+// style is the tree style set by Setup
+var style = UnicodeTree
+
+// Setup configures the colors and the tree style for the commands writing to out.
+// ASCII trees are used when ascii is set or the terminal is dumb.
+func Setup(mode ColorMode, ascii bool, out io.Writer) {
+	color.NoColor = !colorEnabled(mode, out)
+
+	style = UnicodeTree
+	if ascii || os.Getenv("TERM") == "dumb" {
+		style = ASCIITree
+	}
+}
+
+// colorEnabled returns true if the mode enables colors on out
+func colorEnabled(mode ColorMode, out io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	// https://no-color.org: set and not empty disables colors
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// Renderer renders text in a tree style, with or without colors
+type Renderer struct {
+	Style TreeStyle
+	Color bool
+}
+
+// Default returns the Renderer configured by Setup
+func Default() Renderer {
+	return Renderer{Style: style, Color: !color.NoColor}
+}
+
+// Plain returns the Renderer without colors, e.g. for text that is processed further
+func (r Renderer) Plain() Renderer {
+	r.Color = false
+	return r
+}
+
+func (r Renderer) paint(s string, attrs ...color.Attribute) string {
+	if !r.Color || s == "" {
+		return s
+	}
+	c := color.New(attrs...)
+	c.EnableColor()
+	return c.Sprint(s)
+}
+
+// Value colors a configuration value
+func (r Renderer) Value(s string) string {
+	return r.paint(s, color.FgCyan)
+}
+
+// Deviation colors a value, or a marker, of a deviating leaf
+func (r Renderer) Deviation(s string) string {
+	return r.paint(s, color.FgRed, color.Bold)
+}
+
+// ownerColors are the colors of the intent owners, picked by a hash of the owner
+var ownerColors = []color.Attribute{
+	color.FgGreen,
+	color.FgBlue,
+	color.FgMagenta,
+	color.FgHiGreen,
+	color.FgHiBlue,
+	color.FgHiMagenta,
+}
+
+const (
+	// OwnerRunning owns the leaves configured on the device but not by an intent
+	OwnerRunning = "running"
+	// OwnerDefault owns the schema default values
+	OwnerDefault = "default"
+)
+
+// Owner colors the text s of the blame owner. The running and default owners have
+// fixed colors, every intent always gets the same color of a palette.
+func (r Renderer) Owner(owner, s string) string {
+	switch owner {
+	case OwnerRunning:
+		return r.paint(s, color.FgYellow)
+	case OwnerDefault, "":
+		return r.paint(s, color.FgHiBlack)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(owner))
+	return r.paint(s, ownerColors[h.Sum32()%uint32(len(ownerColors))])
+}
+
+// Added colors a leaf that is added
+func (r Renderer) Added(s string) string {
+	return r.paint(s, color.FgGreen)
+}
+
+// Changed colors a leaf that is changed
+func (r Renderer) Changed(s string) string {
+	return r.paint(s, color.FgYellow)
+}
+
+// Removed colors a leaf that is removed
+func (r Renderer) Removed(s string) string {
+	return r.paint(s, color.FgRed)
+}
+
+// LeafChange renders a leaf difference as a diff line, + for an added leaf, ~ for
+// a changed one and - for a removed one
+func (r Renderer) LeafChange(c types.LeafChange) string {
+	switch c.Operation {
+	case types.LeafAdded:
+		return r.Added(fmt.Sprintf("+ %s: %s", c.Path, c.New))
+	case types.LeafChanged:
+		return r.Changed(fmt.Sprintf("~ %s: %s -> %s", c.Path, c.Old, c.New))
+	default:
+		return r.Removed(fmt.Sprintf("- %s: %s", c.Path, c.Old))
+	}
+}
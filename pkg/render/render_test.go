@@ -0,0 +1,140 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/sdcio/kubectl-sdc/pkg/types"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+func stringVal(s string) *sdcpb.TypedValue {
+	return &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: s}}
+}
+
+func testBlameTree() *sdcpb.BlameTreeElement {
+	return &sdcpb.BlameTreeElement{
+		Name: "srl1",
+		Childs: []*sdcpb.BlameTreeElement{
+			{Name: "system", Childs: []*sdcpb.BlameTreeElement{
+				{Name: "host-name", Owner: "default.intent-a", Value: stringVal("srl1"), DeviationValue: stringVal("leaf1")},
+				{Name: "description", Owner: "running", Value: stringVal("lab")},
+			}},
+			{Name: "interface", Childs: []*sdcpb.BlameTreeElement{
+				{Name: "ethernet-1/1", KeyName: "name", Childs: []*sdcpb.BlameTreeElement{
+					{Name: "mtu", Owner: "default", Value: stringVal("9232")},
+				}},
+			}},
+		},
+	}
+}
+
+func TestBlameTree_MatchesProtoRendering(t *testing.T) {
+	r := Renderer{Style: UnicodeTree}
+	tree := testBlameTree()
+
+	if got, want := r.BlameTree(tree), tree.ToString(); got != want {
+		t.Fatalf("BlameTree() =\n%s\nwant\n%s", got, want)
+	}
+	if got, want := r.BlameXPaths(tree), tree.StringSliceXPath(); !slices.Equal(got, want) {
+		t.Fatalf("BlameXPaths() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestBlameTree_ASCII(t *testing.T) {
+	got := Renderer{Style: ASCIITree}.BlameTree(testBlameTree())
+	want := `           -----    |     srl1
+           -----    |     |-- interface
+           -----    |     |   ` + "`" + `-- name=ethernet-1/1
+         default    |     |       ` + "`" + `-- mtu -> 9232
+           -----    |     ` + "`" + `-- system
+         running    |         |-- description -> lab
+default.intent-a(*) |         ` + "`" + `-- host-name -> leaf1 [~> srl1]`
+	if got != want {
+		t.Fatalf("BlameTree() =\n%s\nwant\n%s", got, want)
+	}
+	for _, c := range got {
+		if c > 127 {
+			t.Fatalf("BlameTree() contains the non-ASCII character %q", c)
+		}
+	}
+}
+
+func TestRenderer_Colors(t *testing.T) {
+	r := Renderer{Style: UnicodeTree, Color: true}
+
+	lines := r.BlameXPaths(testBlameTree())
+	if !strings.Contains(strings.Join(lines, "\n"), "\x1b[31;1mleaf1\x1b[0;22m") {
+		t.Fatalf("BlameXPaths() = %q, want the deviation in red", lines)
+	}
+	if r.Owner("default.intent-a", "x") != r.Owner("default.intent-a", "x") || r.Owner("running", "x") == r.Owner("default", "x") {
+		t.Fatalf("owner colors are not stable or not distinct")
+	}
+	if got := r.LeafChange(types.LeafChange{Operation: types.LeafChanged, Path: "/system/name/host-name", Old: "srl1", New: "leaf1"}); got != "\x1b[33m~ /system/name/host-name: srl1 -> leaf1\x1b[0m" {
+		t.Fatalf("LeafChange() = %q, want the change in yellow", got)
+	}
+	if got := r.Plain().LeafChange(types.LeafChange{Operation: types.LeafRemoved, Path: "/system/description", Old: "lab"}); got != "- /system/description: lab" {
+		t.Fatalf("Plain().LeafChange() = %q", got)
+	}
+	if got := r.Plain().Value("v"); got != "v" {
+		t.Fatalf("Plain().Value() = %q, want no colors", got)
+	}
+	if legend := r.BlameLegend(); !strings.Contains(legend, "running") || !strings.Contains(legend, "[~> <intended value>]") {
+		t.Fatalf("BlameLegend() = %q", legend)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	defer func(noColor bool, s TreeStyle) { color.NoColor, style = noColor, s }(color.NoColor, style)
+	t.Setenv("TERM", "xterm")
+	t.Setenv("NO_COLOR", "")
+
+	Setup(ColorAlways, false, &bytes.Buffer{})
+	if r := Default(); !r.Color || r.Style != UnicodeTree {
+		t.Fatalf("Default() = %+v, want colors and the unicode tree", r)
+	}
+
+	// auto does not color a buffer or a file
+	Setup(ColorAuto, true, &bytes.Buffer{})
+	if r := Default(); r.Color || r.Style != ASCIITree {
+		t.Fatalf("Default() = %+v, want no colors and the ASCII tree", r)
+	}
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	Setup(ColorAuto, false, f)
+	if Default().Color {
+		t.Fatalf("Default() colors a non terminal")
+	}
+
+	t.Setenv("TERM", "dumb")
+	Setup(ColorNever, false, &bytes.Buffer{})
+	if r := Default(); r.Color || r.Style != ASCIITree {
+		t.Fatalf("Default() = %+v, want the ASCII tree on a dumb terminal", r)
+	}
+}
+
+func TestColorEnabled_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled(ColorAuto, os.Stdout) {
+		t.Fatalf("colorEnabled() = true with NO_COLOR")
+	}
+	if !colorEnabled(ColorAlways, os.Stdout) {
+		t.Fatalf("colorEnabled(always) = false, want always to override NO_COLOR")
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	if m, err := ParseColorMode("Always"); err != nil || m != ColorAlways {
+		t.Fatalf("ParseColorMode() = %q, %v", m, err)
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil || err.Error() != `invalid color mode "sometimes", must be one of: auto, always, never` {
+		t.Fatalf("ParseColorMode() error = %v", err)
+	}
+}
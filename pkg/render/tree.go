@@ -0,0 +1,174 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// TreeStyle holds the characters a tree is drawn with
+type TreeStyle struct {
+	// Branch and LastBranch connect a node to its parent, LastBranch for the last child
+	Branch     string
+	LastBranch string
+	// Pipe and Space indent the children of a node that is, or is not, followed by siblings
+	Pipe  string
+	Space string
+	// Separator separates a column, e.g. the blame owner, from the tree
+	Separator string
+	// Root, Container, Leaf and Key prefix the name of these nodes
+	Root      string
+	Container string
+	Leaf      string
+	Key       string
+}
+
+var (
+	// UnicodeTree draws trees with box drawing characters and emoji
+	UnicodeTree = TreeStyle{
+		Branch:     "├── ",
+		LastBranch: "└── ",
+		Pipe:       "│   ",
+		Space:      "    ",
+		Separator:  " │ ",
+		Root:       "🎯 ",
+		Container:  "📦 ",
+		Leaf:       "🍃 ",
+		Key:        "🔑 ",
+	}
+	// ASCIITree draws trees with ASCII characters only
+	ASCIITree = TreeStyle{
+		Branch:     "|-- ",
+		LastBranch: "`-- ",
+		Pipe:       "|   ",
+		Space:      "    ",
+		Separator:  " | ",
+	}
+)
+
+// Connector returns the connector of a node and the prefix of its children
+func (s TreeStyle) Connector(prefix string, isLast bool) (string, string) {
+	if isLast {
+		return s.LastBranch, prefix + s.Space
+	}
+	return s.Branch, prefix + s.Pipe
+}
+
+// blameOwner returns the owner of a blame tree element, or dashes if it has none
+func blameOwner(b *sdcpb.BlameTreeElement) string {
+	if b.GetOwner() == "" {
+		return "-----"
+	}
+	return b.GetOwner()
+}
+
+func maxOwnerLength(b *sdcpb.BlameTreeElement) int {
+	l := len(blameOwner(b))
+	for _, c := range b.GetChilds() {
+		l = max(l, maxOwnerLength(c))
+	}
+	return l
+}
+
+// BlameTree renders the blame tree with the owner of every node in front of it.
+// Deviating leaves are marked with (*) and show the value on the device, followed
+// by the value of the owner in brackets.
+func (r Renderer) BlameTree(tree *sdcpb.BlameTreeElement) string {
+	if tree == nil {
+		return ""
+	}
+	sb := &strings.Builder{}
+	r.writeBlameNode(sb, tree, "", false, true, maxOwnerLength(tree))
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (r Renderer) writeBlameNode(sb *strings.Builder, b *sdcpb.BlameTreeElement, prefix string, isLast, isRoot bool, ownerSize int) {
+	connector, nextPrefix := r.Style.Connector(prefix, isLast)
+	icon := r.Style.Container
+	if isRoot {
+		connector, nextPrefix = r.Style.Space, prefix+r.Style.Space
+		icon = r.Style.Root
+	}
+
+	deviated, value, intended := "   ", "", ""
+	switch {
+	case b.GetKeyName() != "":
+		icon = r.Style.Key + b.GetKeyName() + "="
+	case b.IsDeviated():
+		deviated = r.Deviation("(*)")
+		value = " -> " + r.Deviation(b.GetDeviationValue().ToString())
+		intended = fmt.Sprintf(" [~> %s]", b.GetValue().ToString())
+	case b.GetValue() != nil:
+		value = " -> " + b.GetValue().ToString()
+		icon = r.Style.Leaf
+	}
+
+	owner := r.Owner(b.GetOwner(), fmt.Sprintf("%*s", ownerSize, blameOwner(b)))
+	fmt.Fprintf(sb, "%s%s%s%s%s%s%s%s%s\n", owner, deviated, r.Style.Separator, prefix, connector, icon, b.GetName(), value, intended)
+
+	children := slices.Clone(b.GetChilds())
+	slices.SortFunc(children, func(a, b *sdcpb.BlameTreeElement) int { return strings.Compare(a.GetName(), b.GetName()) })
+	for i, c := range children {
+		r.writeBlameNode(sb, c, nextPrefix, i == len(children)-1, false, ownerSize)
+	}
+}
+
+// BlameXPaths renders a line per leaf of the blame tree with its owner, path and
+// value. Deviating leaves are marked with D and show the value on the device,
+// followed by the value of the owner in brackets.
+func (r Renderer) BlameXPaths(tree *sdcpb.BlameTreeElement) []string {
+	lines := []string{}
+	if tree == nil {
+		return lines
+	}
+	ownerSize := maxOwnerLength(tree)
+	tree.WalkPath(&sdcpb.Path{IsRootBased: true}, func(b *sdcpb.BlameTreeElement, path *sdcpb.Path) {
+		if b.GetValue() == nil {
+			return
+		}
+		deviated, value, intended := " ", b.GetValue().ToString(), ""
+		if b.IsDeviated() {
+			deviated = r.Deviation("D")
+			value = r.Deviation(b.GetDeviationValue().ToString())
+			intended = fmt.Sprintf(" [~> %s]", b.GetValue().ToString())
+		}
+		owner := r.Owner(b.GetOwner(), fmt.Sprintf("%-*s", ownerSize, b.GetOwner()))
+		lines = append(lines, fmt.Sprintf("%s [ %s ] %s -> %s%s", deviated, owner, path.ToXPath(false), value, intended))
+	})
+	return lines
+}
+
+// BlameLegend explains the owners and deviation markers of the blame output
+func (r Renderer) BlameLegend() string {
+	rows := [][2]string{
+		{r.Owner(OwnerRunning, OwnerRunning), "configured on the device, not by an intent"},
+		{r.Owner(OwnerDefault, OwnerDefault), "default value of the schema"},
+		{r.Owner("<namespace>.<config>", "<namespace>.<config>"), "configured by the intent of the Config resource"},
+		{r.Deviation("(*)") + " / " + r.Deviation("D"), "deviation: " + r.Deviation("<device value>") + " [~> <intended value>]"},
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("Legend:\n")
+	for _, row := range rows {
+		// pad by the visible width, the colors add invisible characters
+		fmt.Fprintf(sb, "  %s%s  %s\n", row[0], strings.Repeat(" ", max(0, 20-visibleLen(row[0]))), row[1])
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// visibleLen returns the length of s without the ANSI escape sequences
+func visibleLen(s string) int {
+	n, escaped := 0, false
+	for _, c := range s {
+		switch {
+		case c == '\x1b':
+			escaped = true
+		case escaped:
+			escaped = c != 'm'
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -1,6 +1,11 @@
 package types
 
-import "strings"
+import (
+	"sort"
+	"strings"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
 
 type Deviations map[string]*IntentDeviations
 
@@ -57,6 +62,59 @@ func (d Deviations) Items() DeviationSlice {
 	return allDevs
 }
 
+// TargetKey identifies a target by namespace and name
+type TargetKey struct {
+	Namespace string
+	Target    string
+}
+
+func (k TargetKey) String() string {
+	return k.Namespace + "/" + k.Target
+}
+
+// ByTarget groups the deviations by the namespace and target they belong to
+func (d Deviations) ByTarget() map[TargetKey]Deviations {
+	result := map[TargetKey]Deviations{}
+	for name, dev := range d {
+		key := TargetKey{Namespace: dev.Namespace(), Target: dev.Target()}
+		if _, ok := result[key]; !ok {
+			result[key] = Deviations{}
+		}
+		result[key][name] = dev
+	}
+	return result
+}
+
+// Targets returns the sorted, distinct target names of the deviations
+func (d Deviations) Targets() []string {
+	seen := map[string]struct{}{}
+	targets := make([]string, 0, len(d))
+	for _, dev := range d {
+		if _, ok := seen[dev.Target()]; ok {
+			continue
+		}
+		seen[dev.Target()] = struct{}{}
+		targets = append(targets, dev.Target())
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// MultipleTargets returns true if the deviations span more than one target
+func (d Deviations) MultipleTargets() bool {
+	return len(d.Targets()) > 1
+}
+
+func (d Deviations) MaxTargetNameLength() int {
+	maxLength := 0
+	for _, dev := range d {
+		if len(dev.Target()) > maxLength {
+			maxLength = len(dev.Target())
+		}
+	}
+	return maxLength
+}
+
 func (d Deviations) MaxDeviationNameLength() int {
 	maxLength := 0
 	for name := range d {
@@ -152,6 +210,9 @@ type Deviation struct {
 	DesiredValue string `json:"desiredValue" yaml:"desiredValue"`
 	Path         string `json:"path" yaml:"path"`
 	Reason       string `json:"reason" yaml:"reason"`
+	// Desired is the typed desired value, nil when the path should not be set
+	// or the deviation was not read from a Deviation resource
+	Desired *sdcpb.TypedValue `json:"-" yaml:"-"`
 }
 
 func NewDeviation(path string, desiredValue string, actualValue string, reason string) *Deviation {
@@ -166,6 +227,10 @@ func (d *Deviation) Name() string {
 	return d.parent.Name()
 }
 
+func (d *Deviation) Target() string {
+	return d.parent.Target()
+}
+
 func (d *Deviation) StringIndent(indent string) string {
 	return indent + strings.ReplaceAll(d.String(), "\n", "\n"+indent)
 }
@@ -233,6 +298,20 @@ func (d DeviationSlice) FilterByPathPrefixes(prefixes []string) DeviationSlice {
 	return filtered
 }
 
+// ExcludePathPrefixes returns the deviations whose path matches none of the prefixes
+func (d DeviationSlice) ExcludePathPrefixes(prefixes []string) DeviationSlice {
+	if len(prefixes) == 0 {
+		return d
+	}
+	filtered := make(DeviationSlice, 0, len(d))
+	for _, dev := range d {
+		if !matchesAnyPathPrefix(dev.Path, prefixes) {
+			filtered = append(filtered, dev)
+		}
+	}
+	return filtered
+}
+
 func matchesAnyPathPrefix(path string, prefixes []string) bool {
 	for _, prefix := range prefixes {
 		if prefix == "" {
@@ -0,0 +1,281 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+)
+
+// Leaves maps the XPath of configuration leaves to their string value
+type Leaves map[string]string
+
+// Paths returns the sorted leaf paths
+func (l Leaves) Paths() []string {
+	paths := make([]string, 0, len(l))
+	for p := range l {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// FilterPathPrefixes returns the leaves whose path starts with one of the prefixes,
+// all leaves without prefixes
+func (l Leaves) FilterPathPrefixes(prefixes []string) Leaves {
+	if len(prefixes) == 0 {
+		return l
+	}
+	filtered := Leaves{}
+	for p, v := range l {
+		if matchesAnyPathPrefix(p, prefixes) {
+			filtered[p] = v
+		}
+	}
+	return filtered
+}
+
+// ExcludePathPrefixes returns the leaves whose path starts with none of the prefixes
+func (l Leaves) ExcludePathPrefixes(prefixes []string) Leaves {
+	if len(prefixes) == 0 {
+		return l
+	}
+	filtered := Leaves{}
+	for p, v := range l {
+		if !matchesAnyPathPrefix(p, prefixes) {
+			filtered[p] = v
+		}
+	}
+	return filtered
+}
+
+// LeavesFromIntent collects the leaves of a data-server intent
+func LeavesFromIntent(intent *sdcpb.Intent) Leaves {
+	leaves := Leaves{}
+	for _, upd := range intent.GetUpdate() {
+		leaves[upd.GetPath().ToXPath(false)] = upd.GetValue().ToString()
+	}
+	return leaves
+}
+
+// ListKeys maps the key-less XPath of a list to the names of its keys
+type ListKeys map[string][]string
+
+// ListKeysFromIntent learns the list key names from the paths of an intent
+func ListKeysFromIntent(intent *sdcpb.Intent) ListKeys {
+	keys := ListKeys{}
+	for _, upd := range intent.GetUpdate() {
+		elems := upd.GetPath().GetElem()
+		for i, pe := range elems {
+			if len(pe.GetKey()) == 0 {
+				continue
+			}
+			names := make([]string, 0, len(pe.GetKey()))
+			for k := range pe.GetKey() {
+				names = append(names, k)
+			}
+			sort.Strings(names)
+			listPath := &sdcpb.Path{Elem: elems[:i+1], IsRootBased: true}
+			keys[listPath.ToXPath(true)] = names
+		}
+	}
+	return keys
+}
+
+// ListKeyResolver returns the key names of the list at the given key-less path,
+// usually by looking it up in the schema
+type ListKeyResolver func(listPath *sdcpb.Path) ([]string, error)
+
+// LeavesFromConfigBlobs flattens the config blobs of a Config spec into leaves,
+// mirroring how the data-server stores them. The list keys are taken from keys
+// (e.g. learned from the current intent); lists unknown to keys are looked up with
+// resolve and added to keys. A list whose keys cannot be resolved fails the flattening.
+func LeavesFromConfigBlobs(blobs []v1alpha1.ConfigBlob, keys ListKeys, resolve ListKeyResolver) (Leaves, error) {
+	if keys == nil {
+		keys = ListKeys{}
+	}
+	leaves := Leaves{}
+	for _, blob := range blobs {
+		base, err := sdcpb.ParsePath(blob.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", blob.Path, err)
+		}
+		base.IsRootBased = true
+
+		dec := json.NewDecoder(bytes.NewReader(blob.Value.Raw))
+		dec.UseNumber()
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("invalid value for path %q: %w", blob.Path, err)
+		}
+		if err := flattenValue(base, value, keys, resolve, leaves); err != nil {
+			return nil, err
+		}
+	}
+	return leaves, nil
+}
+
+func flattenValue(path *sdcpb.Path, value any, keys ListKeys, resolve ListKeyResolver, leaves Leaves) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		s, err := scalarString(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path.ToXPath(false), err)
+		}
+		leaves[path.ToXPath(false)] = s
+		return nil
+	}
+
+	for name, child := range obj {
+		name = StripModulePrefix(name)
+		childPath := path.CopyPathAddElem(sdcpb.NewPathElem(name, nil))
+
+		list, isList := child.([]any)
+		if !isList || !isObjectList(list) {
+			if err := flattenValue(childPath, child, keys, resolve, leaves); err != nil {
+				return err
+			}
+			continue
+		}
+
+		keyNames, err := listKeyNames(childPath, keys, resolve)
+		if err != nil {
+			return err
+		}
+		for _, entry := range list {
+			entryObj := normalizeKeys(entry.(map[string]any))
+			entryPath := childPath.DeepCopy()
+			for _, k := range keyNames {
+				kv, err := scalarString(entryObj[k])
+				if err != nil {
+					return fmt.Errorf("%s: key %q: %w", childPath.ToXPath(false), k, err)
+				}
+				entryPath.Elem[len(entryPath.Elem)-1].AddKey(k, kv)
+			}
+			if err := flattenValue(entryPath, entryObj, keys, resolve, leaves); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isObjectList returns true if the JSON array represents a YANG list rather than a leaf-list
+func isObjectList(list []any) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, e := range list {
+		if _, ok := e.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// listKeyNames returns the key names of the list at listPath, resolving and
+// remembering them when keys does not know the list yet
+func listKeyNames(listPath *sdcpb.Path, keys ListKeys, resolve ListKeyResolver) ([]string, error) {
+	xpath := listPath.ToXPath(true)
+	if names, ok := keys[xpath]; ok {
+		return names, nil
+	}
+	if resolve == nil {
+		return nil, fmt.Errorf("%s: unknown list keys", xpath)
+	}
+	names, err := resolve(listPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to resolve the list keys: %w", xpath, err)
+	}
+	keys[xpath] = names
+	return names, nil
+}
+
+func normalizeKeys(obj map[string]any) map[string]any {
+	result := make(map[string]any, len(obj))
+	for k, v := range obj {
+		result[StripModulePrefix(k)] = v
+	}
+	return result
+}
+
+// StripModulePrefix removes the JSON-IETF module prefix of a member name
+func StripModulePrefix(name string) string {
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// scalarString renders a JSON leaf value the way sdcpb.TypedValue.ToString does
+func scalarString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "{}", nil
+	case []any:
+		// an empty leaf is encoded as [null]
+		if len(v) == 1 && v[0] == nil {
+			return "{}", nil
+		}
+		elems := make([]string, 0, len(v))
+		for _, e := range v {
+			s, err := scalarString(e)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, s)
+		}
+		return strings.Join(elems, ","), nil
+	default:
+		return "", fmt.Errorf("unexpected value of type %T", value)
+	}
+}
+
+// LeafOperation describes how a leaf changes between two sets of leaves
+type LeafOperation string
+
+const (
+	LeafAdded   LeafOperation = "added"
+	LeafChanged LeafOperation = "changed"
+	LeafRemoved LeafOperation = "removed"
+)
+
+// LeafChange is a single difference between two sets of leaves
+type LeafChange struct {
+	Operation LeafOperation
+	Path      string
+	Old       string
+	New       string
+}
+
+// DiffLeaves returns the changes, sorted by path, required to go from current to desired
+func DiffLeaves(current, desired Leaves) []LeafChange {
+	var changes []LeafChange
+	for path, newValue := range desired {
+		oldValue, ok := current[path]
+		switch {
+		case !ok:
+			changes = append(changes, LeafChange{Operation: LeafAdded, Path: path, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, LeafChange{Operation: LeafChanged, Path: path, Old: oldValue, New: newValue})
+		}
+	}
+	for path, oldValue := range current {
+		if _, ok := desired[path]; !ok {
+			changes = append(changes, LeafChange{Operation: LeafRemoved, Path: path, Old: oldValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
@@ -0,0 +1,529 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/client-go/tools/portforward"
+	"sigs.k8s.io/yaml"
+)
+
+// scheme knows the kinds the APIServer can store
+var scheme = runtime.NewScheme()
+
+// codecs decode the request bodies of the kinds of the scheme
+var codecs = serializer.NewCodecFactory(scheme)
+
+func init() {
+	if err := corev1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+}
+
+// objectKey identifies a stored object
+type objectKey struct {
+	group     string
+	resource  string
+	namespace string
+	name      string
+}
+
+// APIServer is an in-memory Kubernetes API server serving the core resources
+// and config.sdcio.dev resources used by kubectl sdc. It supports get, list
+// (with label and field selectors, also as metadata only), watch, server-side
+// apply, the cleardeviation subresource of targets and port-forwards to pods.
+type APIServer struct {
+	*httptest.Server
+
+	mu              sync.Mutex
+	objects         map[objectKey]*unstructured.Unstructured
+	resourceVersion int
+	// forwards maps a pod name to the address its port-forwards are connected to
+	forwards map[string]string
+	// clearDeviations records the TargetClearDeviation requests
+	clearDeviations []*v1alpha1.TargetClearDeviation
+	// onApply is called with the object about to be stored by a server-side apply
+	onApply func(obj *unstructured.Unstructured)
+}
+
+// NewAPIServer starts an empty APIServer, it is closed when the test ends
+func NewAPIServer(t testing.TB) *APIServer {
+	s := &APIServer{
+		objects:  map[objectKey]*unstructured.Unstructured{},
+		forwards: map[string]string{},
+	}
+	s.Server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Add stores typed objects of the core or config.sdcio.dev API groups
+func (s *APIServer) Add(objs ...runtime.Object) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, obj := range objs {
+		gvks, _, err := scheme.ObjectKinds(obj)
+		if err != nil {
+			return err
+		}
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		u := &unstructured.Unstructured{Object: content}
+		u.SetGroupVersionKind(gvks[0])
+		s.store(u)
+	}
+	return nil
+}
+
+// Get returns a copy of a stored object, nil if it does not exist
+func (s *APIServer) Get(group, resource, namespace, name string) *unstructured.Unstructured {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[objectKey{group: group, resource: resource, namespace: namespace, name: name}]
+	if !ok {
+		return nil
+	}
+	return obj.DeepCopy()
+}
+
+// ForwardPod connects the port-forwards to pod to addr
+func (s *APIServer) ForwardPod(pod, addr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forwards[pod] = addr
+}
+
+// OnApply sets a function called with every object stored by a server-side apply,
+// e.g. to mark a Config ready as the config-server would
+func (s *APIServer) OnApply(fn func(obj *unstructured.Unstructured)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onApply = fn
+}
+
+// ClearDeviations returns the TargetClearDeviation requests received so far
+func (s *APIServer) ClearDeviations() []*v1alpha1.TargetClearDeviation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*v1alpha1.TargetClearDeviation(nil), s.clearDeviations...)
+}
+
+// store saves obj with a new resource version, the caller holds the lock
+func (s *APIServer) store(obj *unstructured.Unstructured) {
+	s.resourceVersion++
+	obj.SetResourceVersion(strconv.Itoa(s.resourceVersion))
+	gvk := obj.GroupVersionKind()
+	s.objects[objectKey{group: gvk.Group, resource: resourceName(gvk.Kind), namespace: obj.GetNamespace(), name: obj.GetName()}] = obj
+}
+
+// resourceName returns the resource name of a kind, all the kinds served are plain plurals
+func resourceName(kind string) string {
+	return strings.ToLower(kind) + "s"
+}
+
+// kindFor returns the kind of a resource of group
+func kindFor(group, resource string) (schema.GroupVersionKind, bool) {
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.Group == group && resourceName(gvk.Kind) == resource && gvk.Version != runtime.APIVersionInternal {
+			return gvk, true
+		}
+	}
+	return schema.GroupVersionKind{}, false
+}
+
+// request is a parsed resource request path
+type request struct {
+	gvk         schema.GroupVersionKind
+	resource    string
+	namespace   string
+	name        string
+	subresource string
+}
+
+func parseRequest(path string) (*request, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var group string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		group = parts[1]
+		parts = parts[3:]
+	default:
+		return nil, fmt.Errorf("unknown path %s", path)
+	}
+
+	r := &request{}
+	if len(parts) >= 2 && parts[0] == "namespaces" {
+		r.namespace = parts[1]
+		parts = parts[2:]
+	}
+	if len(parts) == 0 || len(parts) > 3 {
+		return nil, fmt.Errorf("unknown path %s", path)
+	}
+	r.resource = parts[0]
+	if len(parts) > 1 {
+		r.name = parts[1]
+	}
+	if len(parts) > 2 {
+		r.subresource = parts[2]
+	}
+
+	gvk, ok := kindFor(group, r.resource)
+	if !ok {
+		return nil, fmt.Errorf("unknown resource %s", r.resource)
+	}
+	r.gvk = gvk
+	return r, nil
+}
+
+func (r *request) key() objectKey {
+	return objectKey{group: r.gvk.Group, resource: r.resource, namespace: r.namespace, name: r.name}
+}
+
+func (s *APIServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r, err := parseRequest(req.URL.Path)
+	if err != nil {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path))
+		return
+	}
+
+	switch {
+	case r.subresource == "portforward" && req.Method == http.MethodPost:
+		s.portForward(w, req, r)
+	case r.subresource == v1alpha1.TargetClearDeviation{}.SubResourceName() && req.Method == http.MethodPost:
+		s.clearDeviation(w, req, r)
+	case r.subresource != "":
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: r.gvk.Group, Resource: r.resource + "/" + r.subresource}, r.name))
+	case req.Method == http.MethodGet && r.name == "" && req.URL.Query().Get("watch") == "true":
+		s.watch(w, req, r)
+	case req.Method == http.MethodGet && r.name == "":
+		s.list(w, req, r)
+	case req.Method == http.MethodGet:
+		s.get(w, r)
+	case req.Method == http.MethodPost && r.name == "":
+		s.create(w, req, r)
+	case req.Method == http.MethodPatch:
+		s.apply(w, req, r)
+	default:
+		writeStatus(w, apierrors.NewMethodNotSupported(schema.GroupResource{Group: r.gvk.Group, Resource: r.resource}, req.Method))
+	}
+}
+
+func (s *APIServer) get(w http.ResponseWriter, r *request) {
+	s.mu.Lock()
+	obj, ok := s.objects[r.key()]
+	s.mu.Unlock()
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: r.gvk.Group, Resource: r.resource}, r.name))
+		return
+	}
+	writeJSON(w, http.StatusOK, obj.Object)
+}
+
+// selected returns the objects of the request matching its label and field selectors, sorted by namespace and name
+func (s *APIServer) selected(req *http.Request, r *request) ([]*unstructured.Unstructured, error) {
+	labelSelector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(req.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []*unstructured.Unstructured
+	for key, obj := range s.objects {
+		if key.group != r.gvk.Group || key.resource != r.resource || (r.namespace != "" && key.namespace != r.namespace) {
+			continue
+		}
+		if !labelSelector.Matches(labels.Set(obj.GetLabels())) {
+			continue
+		}
+		if !fieldSelector.Matches(fields.Set{"metadata.name": obj.GetName(), "metadata.namespace": obj.GetNamespace()}) {
+			continue
+		}
+		items = append(items, obj.DeepCopy())
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	return items, nil
+}
+
+func (s *APIServer) list(w http.ResponseWriter, req *http.Request, r *request) {
+	items, err := s.selected(req, r)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	// metadata clients ask for a PartialObjectMetadataList
+	if strings.Contains(req.Header.Get("Accept"), "as=PartialObjectMetadataList") {
+		list := &metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadataList"}}
+		for _, item := range items {
+			m := metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"}}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object["metadata"].(map[string]any), &m.ObjectMeta); err != nil {
+				writeStatus(w, apierrors.NewInternalError(err))
+				return
+			}
+			list.Items = append(list.Items, m)
+		}
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	objects := make([]any, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, item.Object)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"apiVersion": r.gvk.GroupVersion().String(),
+		"kind":       r.gvk.Kind + "List",
+		"metadata":   map[string]any{"resourceVersion": strconv.Itoa(s.currentResourceVersion())},
+		"items":      objects,
+	})
+}
+
+func (s *APIServer) currentResourceVersion() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resourceVersion
+}
+
+// watch sends the matching objects as ADDED events and keeps the stream open until the client leaves
+func (s *APIServer) watch(w http.ResponseWriter, req *http.Request, r *request) {
+	items, err := s.selected(req, r)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(map[string]any{"type": "ADDED", "object": item.Object}); err != nil {
+			return
+		}
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	<-req.Context().Done()
+}
+
+// create stores a new object, failing when one with the same name exists
+func (s *APIServer) create(w http.ResponseWriter, req *http.Request, r *request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	// the typed clients of the core kinds send protobuf
+	decoded, _, err := codecs.UniversalDeserializer().Decode(body, &r.gvk, nil)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(decoded)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(r.gvk)
+	obj.SetNamespace(r.namespace)
+	r.name = obj.GetName()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, found := s.objects[r.key()]; found {
+		writeStatus(w, apierrors.NewAlreadyExists(schema.GroupResource{Group: r.gvk.Group, Resource: r.resource}, r.name))
+		return
+	}
+	s.store(obj)
+	writeJSON(w, http.StatusCreated, obj.Object)
+}
+
+// apply implements server-side apply, the applied fields replace the stored ones
+func (s *APIServer) apply(w http.ResponseWriter, req *http.Request, r *request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	data, err := yaml.YAMLToJSON(body)
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	applied := &unstructured.Unstructured{}
+	if err := applied.UnmarshalJSON(data); err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	applied.SetNamespace(r.namespace)
+	applied.SetName(r.name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj := applied
+	existing, found := s.objects[r.key()]
+	if found {
+		obj = existing.DeepCopy()
+		for k, v := range applied.Object {
+			if k != "metadata" && k != "status" {
+				obj.Object[k] = v
+			}
+		}
+		obj.SetLabels(applied.GetLabels())
+		obj.SetAnnotations(applied.GetAnnotations())
+	}
+	if s.onApply != nil {
+		s.onApply(obj)
+	}
+
+	switch {
+	case req.URL.Query().Get("dryRun") == metav1.DryRunAll:
+	case found && reflect.DeepEqual(obj.Object, existing.Object):
+	default:
+		s.store(obj)
+	}
+	writeJSON(w, http.StatusOK, obj.Object)
+}
+
+func (s *APIServer) clearDeviation(w http.ResponseWriter, req *http.Request, r *request) {
+	s.mu.Lock()
+	_, ok := s.objects[r.key()]
+	s.mu.Unlock()
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: r.gvk.Group, Resource: r.resource}, r.name))
+		return
+	}
+
+	clear := &v1alpha1.TargetClearDeviation{}
+	if err := json.NewDecoder(req.Body).Decode(clear); err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.clearDeviations = append(s.clearDeviations, clear)
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, clear)
+}
+
+// portForward upgrades the request to a SPDY connection and connects every data
+// stream to the address registered for the pod with ForwardPod
+func (s *APIServer) portForward(w http.ResponseWriter, req *http.Request, r *request) {
+	s.mu.Lock()
+	addr, ok := s.forwards[r.name]
+	s.mu.Unlock()
+	if !ok {
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, r.name))
+		return
+	}
+
+	if _, err := httpstream.Handshake(req, w, []string{portforward.PortForwardProtocolV1Name}); err != nil {
+		return
+	}
+	streams := make(chan httpstream.Stream)
+	conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(stream httpstream.Stream, _ <-chan struct{}) error {
+		streams <- stream
+		return nil
+	})
+	if conn == nil {
+		return
+	}
+	defer conn.Close()
+
+	var errorStreams []httpstream.Stream
+	defer func() {
+		for _, stream := range errorStreams {
+			_ = stream.Close()
+		}
+	}()
+	for {
+		select {
+		case stream := <-streams:
+			switch stream.Headers().Get(corev1.StreamType) {
+			case corev1.StreamTypeError:
+				errorStreams = append(errorStreams, stream)
+			case corev1.StreamTypeData:
+				go forwardStream(stream, addr)
+			default:
+				_ = stream.Reset()
+			}
+		case <-conn.CloseChan():
+			return
+		}
+	}
+}
+
+// forwardStream copies a port-forward data stream to and from addr
+func forwardStream(stream httpstream.Stream, addr string) {
+	defer func() { _ = stream.Close() }()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		_ = stream.Reset()
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(conn, stream)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(stream, conn)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.ErrStatus
+	status.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+	writeJSON(w, int(status.Code), status)
+}
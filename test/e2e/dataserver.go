@@ -0,0 +1,175 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DataServer is an in-process data-server and schema-server. It serves the
+// intents, datastores and schemas added to it, intents in the proto format only.
+type DataServer struct {
+	sdcpb.UnimplementedDataServerServer
+	sdcpb.UnimplementedSchemaServerServer
+
+	// Addr is the address the gRPC server listens on
+	Addr string
+
+	mu sync.Mutex
+	// intents maps a datastore name to its intents by name
+	intents    map[string]map[string]*sdcpb.Intent
+	datastores map[string]*sdcpb.GetDataStoreResponse
+	schemas    []*sdcpb.Schema
+	// elems maps a schema to its elements by path without keys
+	elems map[string]map[string]*sdcpb.SchemaElem
+}
+
+// NewDataServer starts an empty DataServer on a local port, it is stopped when the test ends
+func NewDataServer(t testing.TB) *DataServer {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &DataServer{
+		Addr:       lis.Addr().String(),
+		intents:    map[string]map[string]*sdcpb.Intent{},
+		datastores: map[string]*sdcpb.GetDataStoreResponse{},
+		elems:      map[string]map[string]*sdcpb.SchemaElem{},
+	}
+	srv := grpc.NewServer()
+	sdcpb.RegisterDataServerServer(srv, s)
+	sdcpb.RegisterSchemaServerServer(srv, s)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return s
+}
+
+// AddIntent adds or replaces an intent of a datastore, the datastore is created if needed
+func (s *DataServer) AddIntent(datastore string, intent *sdcpb.Intent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.intents[datastore] == nil {
+		s.intents[datastore] = map[string]*sdcpb.Intent{}
+	}
+	_, exists := s.intents[datastore][intent.GetIntent()]
+	s.intents[datastore][intent.GetIntent()] = intent
+	if exists {
+		return
+	}
+	ds := s.datastoreLocked(datastore)
+	ds.Intents = append(ds.Intents, intent.GetIntent())
+	sort.Strings(ds.Intents)
+}
+
+// AddDataStore adds or replaces a datastore
+func (s *DataServer) AddDataStore(ds *sdcpb.GetDataStoreResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.datastores[ds.GetDatastoreName()] = ds
+}
+
+// AddSchema adds a schema with its elements by XPath, the root element has the path "/"
+func (s *DataServer) AddSchema(schema *sdcpb.Schema, elems map[string]*sdcpb.SchemaElem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schemas = append(s.schemas, schema)
+	s.elems[schemaKey(schema)] = elems
+}
+
+// datastoreLocked returns the datastore of name, creating it if needed. The caller holds the lock.
+func (s *DataServer) datastoreLocked(name string) *sdcpb.GetDataStoreResponse {
+	ds, ok := s.datastores[name]
+	if !ok {
+		ds = &sdcpb.GetDataStoreResponse{DatastoreName: name}
+		s.datastores[name] = ds
+	}
+	return ds
+}
+
+func schemaKey(schema *sdcpb.Schema) string {
+	return fmt.Sprintf("%s/%s", schema.GetVendor(), schema.GetVersion())
+}
+
+func (s *DataServer) GetIntent(_ context.Context, req *sdcpb.GetIntentRequest) (*sdcpb.GetIntentResponse, error) {
+	if req.GetFormat() != sdcpb.Format_Intent_Format_PROTO {
+		return nil, status.Errorf(codes.Unimplemented, "format %s is not supported", req.GetFormat())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.datastores[req.GetDatastoreName()]; !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown datastore %s", req.GetDatastoreName())
+	}
+	intent, ok := s.intents[req.GetDatastoreName()][req.GetIntent()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "intent %s not found in datastore %s", req.GetIntent(), req.GetDatastoreName())
+	}
+	return &sdcpb.GetIntentResponse{
+		DatastoreName: req.GetDatastoreName(),
+		Format:        req.GetFormat(),
+		Intent:        &sdcpb.GetIntentResponse_Proto{Proto: proto.Clone(intent).(*sdcpb.Intent)},
+	}, nil
+}
+
+func (s *DataServer) ListDataStore(_ context.Context, _ *sdcpb.ListDataStoreRequest) (*sdcpb.ListDataStoreResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &sdcpb.ListDataStoreResponse{}
+	for _, ds := range s.datastores {
+		resp.Datastores = append(resp.Datastores, proto.Clone(ds).(*sdcpb.GetDataStoreResponse))
+	}
+	sort.Slice(resp.Datastores, func(i, j int) bool {
+		return resp.Datastores[i].GetDatastoreName() < resp.Datastores[j].GetDatastoreName()
+	})
+	return resp, nil
+}
+
+func (s *DataServer) GetDataStore(_ context.Context, req *sdcpb.GetDataStoreRequest) (*sdcpb.GetDataStoreResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds, ok := s.datastores[req.GetDatastoreName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown datastore %s", req.GetDatastoreName())
+	}
+	return proto.Clone(ds).(*sdcpb.GetDataStoreResponse), nil
+}
+
+func (s *DataServer) ListSchema(_ context.Context, _ *sdcpb.ListSchemaRequest) (*sdcpb.ListSchemaResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &sdcpb.ListSchemaResponse{}
+	for _, schema := range s.schemas {
+		resp.Schema = append(resp.Schema, proto.Clone(schema).(*sdcpb.Schema))
+	}
+	return resp, nil
+}
+
+func (s *DataServer) GetSchema(_ context.Context, req *sdcpb.GetSchemaRequest) (*sdcpb.GetSchemaResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elems, ok := s.elems[schemaKey(req.GetSchema())]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown schema %s", schemaKey(req.GetSchema()))
+	}
+	path := "/"
+	if len(req.GetPath().GetElem()) > 0 {
+		p := proto.Clone(req.GetPath()).(*sdcpb.Path)
+		p.IsRootBased = true
+		path = p.ToXPath(true)
+	}
+	elem, ok := elems[path]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown element %s", path)
+	}
+	return &sdcpb.GetSchemaResponse{Schema: proto.Clone(elem).(*sdcpb.SchemaElem)}, nil
+}
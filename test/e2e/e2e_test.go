@@ -0,0 +1,618 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	condv1alpha1 "github.com/sdcio/config-server/apis/condition/v1alpha1"
+	"github.com/sdcio/config-server/apis/config/v1alpha1"
+	"github.com/sdcio/kubectl-sdc/pkg/client"
+	sdcCmd "github.com/sdcio/kubectl-sdc/pkg/cmd"
+	sdcpb "github.com/sdcio/sdc-protos/sdcpb"
+	"google.golang.org/protobuf/encoding/prototext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	target        = "srl1"
+	schemaVendor  = "srl.nokia.sdcio.dev"
+	schemaVersion = "24.10.1"
+)
+
+var targetSchema = &sdcpb.Schema{Name: schemaVendor, Vendor: schemaVendor, Version: schemaVersion}
+
+const blameTree = `{
+  "name": "root",
+  "childs": [
+    {"name": "system", "childs": [
+      {"name": "name", "childs": [
+        {"name": "host-name", "owner": "default.intent-a", "value": {"stringVal": "srl1"}}
+      ]},
+      {"name": "description", "owner": "running", "value": {"stringVal": "lab"}}
+    ]}
+  ]
+}`
+
+// newSeededHarness returns a Harness with a discovered target, its blame tree,
+// a deviation, the running and intent-a intents and the target schema
+func newSeededHarness(t *testing.T) *Harness {
+	t.Helper()
+	h := NewHarness(t)
+
+	deviationType := v1alpha1.DeviationType_TARGET
+	desired, actual := `string_val:"srl1"`, `string_val:"leaf1"`
+	err := h.APIServer.Add(
+		&v1alpha1.Target{
+			ObjectMeta: metav1.ObjectMeta{Name: target, Namespace: Namespace},
+			Spec:       v1alpha1.TargetSpec{Provider: schemaVendor, Address: "10.0.0.1"},
+			Status: v1alpha1.TargetStatus{
+				DiscoveryInfo: &v1alpha1.DiscoveryInfo{Provider: schemaVendor, Version: schemaVersion, Hostname: "leaf1"},
+			},
+		},
+		&v1alpha1.ConfigBlame{
+			ObjectMeta: metav1.ObjectMeta{Name: target, Namespace: Namespace},
+			Status:     v1alpha1.ConfigBlameStatus{Value: runtime.RawExtension{Raw: []byte(blameTree)}},
+		},
+		&v1alpha1.Deviation{
+			ObjectMeta: metav1.ObjectMeta{Name: target, Namespace: Namespace, Labels: map[string]string{client.TargetLabel: target}},
+			Spec: v1alpha1.DeviationSpec{
+				DeviationType: &deviationType,
+				Deviations: []v1alpha1.ConfigDeviation{{
+					Path:         "/system/name/host-name",
+					DesiredValue: &desired,
+					ActualValue:  &actual,
+					Reason:       "NOT_APPLIED",
+				}},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to seed the API server: %v", err)
+	}
+
+	datastore := Namespace + "." + target
+	h.DataServer.AddDataStore(&sdcpb.GetDataStoreResponse{DatastoreName: datastore, Schema: targetSchema})
+	h.DataServer.AddIntent(datastore, &sdcpb.Intent{
+		Intent: "running",
+		Update: []*sdcpb.Update{
+			{Path: mustParsePath(t, "/system/name/host-name"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "leaf1"}}},
+		},
+	})
+	h.DataServer.AddIntent(datastore, &sdcpb.Intent{
+		Intent:   "default.intent-a",
+		Priority: 10,
+		Update: []*sdcpb.Update{
+			{Path: mustParsePath(t, "/system/name/host-name"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "srl1"}}},
+		},
+	})
+
+	stringType := &sdcpb.SchemaLeafType{Type: "string"}
+	h.DataServer.AddSchema(targetSchema, map[string]*sdcpb.SchemaElem{
+		"/": {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{Name: "root", Children: []string{"system"}}}},
+		"/system": {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{
+			Name:     "system",
+			Children: []string{"name"},
+			Fields:   []*sdcpb.LeafSchema{{Name: "description", Type: stringType}},
+		}}},
+		"/system/description": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{Name: "description", Type: stringType}}},
+		"/system/name": {Schema: &sdcpb.SchemaElem_Container{Container: &sdcpb.ContainerSchema{
+			Name:   "name",
+			Fields: []*sdcpb.LeafSchema{{Name: "host-name", Type: stringType}},
+		}}},
+		"/system/name/host-name": {Schema: &sdcpb.SchemaElem_Field{Field: &sdcpb.LeafSchema{Name: "host-name", Description: "the host name of the system", Type: stringType}}},
+	})
+
+	return h
+}
+
+func mustParsePath(t *testing.T, xpath string) *sdcpb.Path {
+	t.Helper()
+	p, err := sdcpb.ParsePath(xpath)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", xpath, err)
+	}
+	return p
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	return path
+}
+
+// expectOutput fails the test if the command failed or its stdout misses one of want
+func expectOutput(t *testing.T, r Result, want ...string) {
+	t.Helper()
+	if r.Err != nil {
+		t.Fatalf("command failed: %v\nstderr: %s", r.Err, r.Stderr)
+	}
+	for _, w := range want {
+		if !strings.Contains(r.Stdout, w) {
+			t.Errorf("stdout misses %q:\n%s", w, r.Stdout)
+		}
+	}
+}
+
+func TestBlame(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("blame", "--target", target, "--format", "xpath", "--filter-owner", "default.*")
+	expectOutput(t, r, "/system/name/host-name")
+	if strings.Contains(r.Stdout, "/system/description") {
+		t.Errorf("blame output is not filtered by owner:\n%s", r.Stdout)
+	}
+
+	r = h.Run("blame", "--target", "unknown")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "not found") {
+		t.Fatalf("blame of an unknown target error = %v, want not found", r.Err)
+	}
+}
+
+func TestDeviation(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("deviation", "--target", target)
+	expectOutput(t, r, "/system/name/host-name")
+
+	r = h.Run("deviation", "--target", target, "--format", "gnmic-setfile")
+	expectOutput(t, r, "# target: "+target, "path: /system/name/host-name", "value: srl1")
+
+	// prototext randomizes its spacing, the output is checked as a gnmi.SetRequest
+	r = h.Run("deviation", "--target", target, "-o", "gnmi-prototext")
+	expectOutput(t, r)
+	req := &gnmipb.SetRequest{}
+	if err := prototext.Unmarshal([]byte(r.Stdout), req); err != nil {
+		t.Fatalf("gnmi-prototext output is not a gnmi.SetRequest: %v\n%s", err, r.Stdout)
+	}
+	if req.GetPrefix().GetTarget() != target || len(req.GetUpdate()) != 1 || string(req.GetUpdate()[0].GetVal().GetJsonIetfVal()) != `"srl1"` {
+		t.Errorf("gnmi-prototext output = %v, want the update of host-name to \"srl1\" on %s", req, target)
+	}
+
+	r = h.Run("deviation", "--target", target, "--revert")
+	expectOutput(t, r, "reverted 1 deviation(s)")
+
+	clears := h.APIServer.ClearDeviations()
+	if len(clears) != 1 {
+		t.Fatalf("got %d cleardeviation requests, want 1", len(clears))
+	}
+	config := clears[0].Spec.Config
+	if len(config) != 1 || len(config[0].Paths) != 1 || config[0].Paths[0] != "/system/name/host-name" {
+		t.Fatalf("cleardeviation request = %+v, want the path /system/name/host-name", config)
+	}
+}
+
+func TestRunningConfig(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("runningconfig", "--target", target)
+	expectOutput(t, r, "/system/name/host-name", "leaf1")
+
+	r = h.Run("runningconfig", "--target", target, "--format", "cli")
+	expectOutput(t, r, "system {\n    name {\n        host-name leaf1\n    }\n}")
+
+	r = h.Run("runningconfig", "--target", target, "-o", "cli", "--cli-style", "set")
+	expectOutput(t, r, "set system name host-name leaf1")
+}
+
+func TestTarget(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("target", "list")
+	expectOutput(t, r, target, schemaVersion)
+
+	r = h.Run("target", "describe", target)
+	expectOutput(t, r, target, schemaVendor, schemaVersion)
+}
+
+func TestOutput(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("blame", "--target", target, "-o", "jsonpath={range .items[*]}{.path}={.value} {.owner}{\"\\n\"}{end}")
+	expectOutput(t, r, "/system/name/host-name=srl1 default.intent-a\n", "/system/description=lab running\n")
+
+	r = h.Run("blame", "--target", target, "-o", "xpath")
+	expectOutput(t, r, "/system/name/host-name -> srl1")
+
+	r = h.Run("deviation", "--target", target, "-o", "custom-columns=PATH:.path,ACTUAL:.actualValue")
+	expectOutput(t, r, "PATH", "ACTUAL", "/system/name/host-name")
+
+	r = h.Run("deviation", "--target", target, "-o", "yaml")
+	expectOutput(t, r, "kind: DeviationEntry", "deviation: "+target, "reason: NOT_APPLIED")
+
+	r = h.Run("runningconfig", "--target", target, "-o", "go-template={{range .items}}{{.intent}} {{.path}} {{.value}}{{end}}")
+	expectOutput(t, r, "running /system/name/host-name leaf1")
+
+	r = h.Run("runningconfig", "--target", target, "-o", "name")
+	expectOutput(t, r, "intentupdate.kubectl.sdcio.dev//system/name/host-name")
+
+	r = h.Run("runningconfig", "--target", target, "-o", "wide")
+	expectOutput(t, r, "INTENT", "running")
+
+	r = h.Run("target", "describe", target, "-o", "json")
+	expectOutput(t, r, `"kind": "TargetSummary"`, `"schemaVersion": "`+schemaVersion+`"`)
+
+	r = h.Run("target", "list", "-o", "wide")
+	expectOutput(t, r, "CONNECTION-PROFILE")
+
+	r = h.Run("datastore", "list", "-o", "name")
+	expectOutput(t, r, "datastoresummary.kubectl.sdcio.dev/default."+target)
+
+	r = h.Run("datastore", "get", target, "-o", "jsonpath={.intents}")
+	expectOutput(t, r, "default.intent-a")
+
+	r = h.Run("schema", "list", "-o", "wide")
+	expectOutput(t, r, "VENDOR", schemaVendor, schemaVersion)
+
+	r = h.Run("schema", "show", "/system/name/host-name", "--target", target, "-o", "yaml")
+	expectOutput(t, r, "kind: SchemaNode", "type: string")
+
+	r = h.Run("schema", "tree", "--target", target, "--depth", "0", "-o", "json")
+	expectOutput(t, r, `"kind": "SchemaTree"`, `"name": "host-name"`)
+
+	r = h.Run("grep", "leaf1", "--match", "value", "-o", "custom-columns=TARGET:.target,PATH:.path")
+	expectOutput(t, r, "TARGET", target+"     /system/name/host-name")
+
+	r = h.Run("grep", "leaf1", "-o", "json", "--count")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "--count cannot be combined with --output") {
+		t.Fatalf("grep -o json --count error = %v, want the flags rejected", r.Err)
+	}
+
+	r = h.Run("blame", "--target", target, "-o", "toml")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), `invalid output format "toml"`) {
+		t.Fatalf("blame -o toml error = %v, want invalid output format", r.Err)
+	}
+
+	r = h.Run("blame", "--target", target, "-o", "json", "--format", "tree")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "none of the others can be") {
+		t.Fatalf("blame -o json --format tree error = %v, want mutually exclusive flags", r.Err)
+	}
+}
+
+func TestRendering(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("blame", "--target", target, "--ascii")
+	expectOutput(t, r, "`-- host-name -> srl1")
+	if strings.ContainsAny(r.Stdout, "│└🍃\x1b") || strings.Contains(r.Stdout, "Legend") {
+		t.Errorf("blame --ascii output is not plain ASCII:\n%s", r.Stdout)
+	}
+
+	r = h.Run("blame", "--target", target, "--color", "always")
+	expectOutput(t, r, "\x1b[33m", "Legend:")
+
+	// an explicit --color takes precedence over the configured color
+	expectOutput(t, h.Run("config", "set", "color", "true"))
+	r = h.Run("runningconfig", "--target", target)
+	expectOutput(t, r, "\x1b[36mleaf1")
+	r = h.Run("runningconfig", "--target", target, "--color=never")
+	expectOutput(t, r, "/system/name/host-name: leaf1")
+
+	r = h.Run("schema", "tree", "--target", target, "--ascii")
+	expectOutput(t, r, "`-- system")
+
+	r = h.Run("blame", "--target", target, "--color", "sometimes")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), `invalid color mode "sometimes"`) {
+		t.Fatalf("blame --color sometimes error = %v, want invalid color mode", r.Err)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("schema", "list")
+	expectOutput(t, r, schemaVendor, schemaVersion)
+
+	r = h.Run("schema", "show", "/system/name/host-name", "--target", target)
+	expectOutput(t, r, "host-name", "string", "the host name of the system")
+
+	r = h.Run("schema", "tree", "--vendor", schemaVendor, "--version", schemaVersion, "--depth", "0")
+	expectOutput(t, r, "system", "name", "host-name", "description")
+}
+
+func TestPath(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("path", "convert", "/system/name/host-name")
+	expectOutput(t, r, "gnmi-prototext", `elem: {name: "system"} elem: {name: "name"} elem: {name: "host-name"}`, "/restconf/data/system/name/host-name")
+
+	r = h.Run("path", "convert", "https://leaf1/restconf/data/system/name", "--target", target, "--to", "xpath")
+	expectOutput(t, r, "/system/name\n")
+}
+
+func TestGrep(t *testing.T) {
+	h := newSeededHarness(t)
+	err := h.APIServer.Add(&v1alpha1.Target{
+		ObjectMeta: metav1.ObjectMeta{Name: "srl2", Namespace: Namespace, Labels: map[string]string{"role": "spine"}},
+		Spec:       v1alpha1.TargetSpec{Provider: schemaVendor, Address: "10.0.0.2"},
+	})
+	if err != nil {
+		t.Fatalf("failed to seed the API server: %v", err)
+	}
+	h.DataServer.AddIntent(Namespace+".srl2", &sdcpb.Intent{
+		Intent: "running",
+		Update: []*sdcpb.Update{
+			{Path: mustParsePath(t, "/system/name/host-name"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "leaf1"}}},
+			{Path: mustParsePath(t, "/system/description"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "spine"}}},
+		},
+	})
+
+	r := h.Run("grep", "leaf1", "--match", "value")
+	expectOutput(t, r, "srl1   /system/name/host-name   leaf1", "srl2   /system/name/host-name   leaf1")
+	if !strings.Contains(r.Stderr, "srl2     1") {
+		t.Errorf("grep stderr misses the count of srl2:\n%s", r.Stderr)
+	}
+
+	r = h.Run("grep", "-l", "role=spine", "--regex", "^/system/", "--count")
+	expectOutput(t, r, "srl2     2")
+	if strings.Contains(r.Stdout, target) {
+		t.Errorf("grep searched %s, which is not selected:\n%s", target, r.Stdout)
+	}
+
+	r = h.Run("grep", "leaf1", "--target", "srl3")
+	if r.Err == nil || r.Err.Error() != "failed to search 1 of 1 target(s)" || !strings.Contains(r.Stderr, "failed to search srl3") {
+		t.Errorf("grep of an unknown target = %v, stderr %q, want a failed search", r.Err, r.Stderr)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	h := newSeededHarness(t)
+	h.DataServer.AddIntent(Namespace+".srl2", &sdcpb.Intent{
+		Intent: "running",
+		Update: []*sdcpb.Update{
+			{Path: mustParsePath(t, "/system/name/host-name"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "leaf2"}}},
+			{Path: mustParsePath(t, "/system/description"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "spine"}}},
+		},
+	})
+
+	// the differences are printed and the command exits non-zero, for CI gates
+	r := h.Run("compare", "--target", target, "--target", "srl2")
+	if r.ExitCode != sdcCmd.ExitCodeDifferences {
+		t.Errorf("compare of different targets exit code = %d, want %d (err: %v)", r.ExitCode, sdcCmd.ExitCodeDifferences, r.Err)
+	}
+	expectOutput(t, Result{Stdout: r.Stdout}, "srl1 -> srl2: 2 difference(s) in 2 leaves compared", "~ /system/name/host-name: leaf1 -> leaf2", "+ /system/description: spine")
+
+	r = h.Run("compare", "--target", target, "--target", "srl2", "--ignore-path", "/system/description", "--replace", "leaf[12]=><host>")
+	expectOutput(t, r, "srl1 and srl2 match, 1 leaves compared")
+
+	rules := writeManifest(t, "ignorePaths:\n- /system/name\n")
+	r = h.Run("compare", "--target", target, "--target", "srl2", "--rules", rules, "--path", "/system")
+	if r.ExitCode != sdcCmd.ExitCodeDifferences {
+		t.Errorf("compare of different targets exit code = %d, want %d (err: %v)", r.ExitCode, sdcCmd.ExitCodeDifferences, r.Err)
+	}
+	expectOutput(t, Result{Stdout: r.Stdout}, "srl1 -> srl2: 1 difference(s) in 1 leaves compared", "+ /system/description: spine")
+
+	r = h.Run("compare", "--target", target, "--target", "srl2", "-o", "jsonpath={range .items[*]}{.operation} {.path}{\"\\n\"}{end}")
+	if r.ExitCode != sdcCmd.ExitCodeDifferences {
+		t.Errorf("compare -o exit code = %d, want %d (err: %v)", r.ExitCode, sdcCmd.ExitCodeDifferences, r.Err)
+	}
+	expectOutput(t, Result{Stdout: r.Stdout}, "changed /system/name/host-name\n", "added /system/description\n")
+}
+
+const historyManifest = `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-a
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  priority: 20
+  config:
+    - path: /system/name
+      value:
+        host-name: srl1-new
+`
+
+func TestHistory(t *testing.T) {
+	h := newSeededHarness(t)
+	expectOutput(t, h.Run("apply", "-f", writeManifest(t, strings.Replace(historyManifest, "priority: 20", "priority: 10", 1))), "config/intent-a created")
+
+	r := h.Run("history", "snapshot", "--target", target, "-m", "baseline")
+	expectOutput(t, r, "snapshot ", "of default/srl1 saved to ")
+	r = h.Run("history", "list", "--target", target)
+	expectOutput(t, r, "ID", "baseline")
+	id := strings.Fields(strings.Split(r.Stdout, "\n")[1])[0]
+
+	r = h.Run("history", "diff", "--target", target, id)
+	if r.Err != nil || !strings.Contains(r.Stderr, "no changes between "+id+" and now") {
+		t.Errorf("diff without changes = %q (err: %v), want no changes", r.Stderr, r.Err)
+	}
+
+	h.DataServer.AddIntent(Namespace+"."+target, &sdcpb.Intent{
+		Intent:   "default.intent-a",
+		Priority: 20,
+		Update: []*sdcpb.Update{
+			{Path: mustParsePath(t, "/system/name/host-name"), Value: &sdcpb.TypedValue{Value: &sdcpb.TypedValue_StringVal{StringVal: "srl1-new"}}},
+		},
+	})
+	expectOutput(t, h.Run("apply", "-f", writeManifest(t, historyManifest)), "config/intent-a configured")
+	expectOutput(t, h.Run("apply", "-f", writeManifest(t, configManifest)), "config/intent-b created")
+
+	r = h.Run("history", "diff", "--target", target, id[:8])
+	expectOutput(t, r, "intent default.intent-a: 1 change(s)", "~ /system/name/host-name: srl1 -> srl1-new")
+
+	r = h.Run("history", "diff", "--target", target, id, "-o", "yaml")
+	expectOutput(t, r, "kind: LeafChange", "from: "+id, "to: now", "section: intent default.intent-a", "newValue: srl1-new")
+
+	r = h.Run("history", "list", "--target", target, "-o", "name")
+	expectOutput(t, r, "snapshot.kubectl.sdcio.dev/"+id)
+
+	r = h.Run("history", "rollback", "--target", target, id)
+	expectOutput(t, r, "name: intent-a", "priority: 10")
+	if !strings.Contains(r.Stderr, "kubectl delete config -n default intent-b") {
+		t.Errorf("rollback stderr misses the deletion of intent-b:\n%s", r.Stderr)
+	}
+	expectOutput(t, h.Run("apply", "-f", writeManifest(t, r.Stdout)), "config/intent-a configured")
+	obj := h.APIServer.Get(v1alpha1.Group, "configs", Namespace, "intent-a")
+	if priority, _, _ := unstructured.NestedInt64(obj.Object, "spec", "priority"); priority != 10 {
+		t.Errorf("rolled back priority = %d, want 10", priority)
+	}
+
+	r = h.Run("history", "snapshot", "--target", target, "--store", "configmap")
+	expectOutput(t, r, "saved to ConfigMaps")
+	r = h.Run("history", "list", "--target", target, "--store", "configmap")
+	expectOutput(t, r, "ID")
+	id = strings.Fields(strings.Split(r.Stdout, "\n")[1])[0]
+	if h.APIServer.Get("", "configmaps", Namespace, "sdc-history-"+target+"-"+id) == nil {
+		t.Errorf("the snapshot ConfigMap of %s was not persisted", id)
+	}
+}
+
+func TestDatastore(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("datastore", "list")
+	expectOutput(t, r, target, schemaVersion)
+
+	r = h.Run("datastore", "get", target)
+	expectOutput(t, r, "default.intent-a")
+}
+
+const configManifest = `apiVersion: config.sdcio.dev/v1alpha1
+kind: Config
+metadata:
+  name: intent-b
+  labels:
+    config.sdcio.dev/targetName: srl1
+spec:
+  priority: 10
+  config:
+    - path: /system
+      value:
+        description: core
+`
+
+func TestApply(t *testing.T) {
+	h := newSeededHarness(t)
+	manifest := writeManifest(t, configManifest)
+
+	r := h.Run("apply", "-f", manifest, "--diff", "--validate", "--dry-run", "server")
+	expectOutput(t, r, "+ /system/description: core", "config/intent-b created (server dry run)")
+	if h.APIServer.Get(v1alpha1.Group, "configs", Namespace, "intent-b") != nil {
+		t.Fatal("the dry run persisted the config")
+	}
+
+	r = h.Run("apply", "-f", manifest)
+	expectOutput(t, r, "config/intent-b created")
+	obj := h.APIServer.Get(v1alpha1.Group, "configs", Namespace, "intent-b")
+	if obj == nil {
+		t.Fatal("the config was not persisted")
+	}
+	if priority, _, _ := unstructured.NestedInt64(obj.Object, "spec", "priority"); priority != 10 {
+		t.Errorf("persisted priority = %d, want 10", priority)
+	}
+
+	r = h.Run("apply", "-f", manifest)
+	expectOutput(t, r, "config/intent-b unchanged")
+
+	// a dry run does not bump the resource version, changes are still reported
+	r = h.Run("apply", "-f", manifest, "--dry-run", "server")
+	expectOutput(t, r, "config/intent-b unchanged (server dry run)")
+	changed := writeManifest(t, strings.Replace(configManifest, "priority: 10", "priority: 20", 1))
+	r = h.Run("apply", "-f", changed, "--dry-run", "server")
+	expectOutput(t, r, "config/intent-b configured (server dry run)")
+	obj = h.APIServer.Get(v1alpha1.Group, "configs", Namespace, "intent-b")
+	if priority, _, _ := unstructured.NestedInt64(obj.Object, "spec", "priority"); priority != 10 {
+		t.Errorf("the dry run persisted priority %d, want 10", priority)
+	}
+}
+
+func TestApplyWait(t *testing.T) {
+	h := newSeededHarness(t)
+	// the config-server marks the applied config ready
+	h.APIServer.OnApply(func(obj *unstructured.Unstructured) {
+		status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&condv1alpha1.ConditionedStatus{
+			Conditions: []condv1alpha1.Condition{condv1alpha1.Ready()},
+		})
+		if err != nil {
+			t.Errorf("failed to build the status: %v", err)
+			return
+		}
+		obj.Object["status"] = status
+	})
+
+	r := h.Run("apply", "-f", writeManifest(t, configManifest), "--wait", "--timeout", "10s")
+	expectOutput(t, r, "config/intent-b created", "ready")
+}
+
+func TestValidate(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("validate", "-f", writeManifest(t, configManifest))
+	expectOutput(t, r, "1 manifest(s) valid")
+
+	invalid := strings.Replace(configManifest, "description: core", "location: core", 1)
+	r = h.Run("validate", "-f", writeManifest(t, invalid))
+	if r.Err == nil {
+		t.Fatalf("validate of an invalid manifest succeeded:\n%s", r.Stdout)
+	}
+	if !strings.Contains(r.Stdout, `unknown element "location"`) {
+		t.Errorf("validate output misses the unknown element:\n%s", r.Stdout)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	h := newSeededHarness(t)
+
+	r := h.Run("runningconfig", "--target", "unknown")
+	if r.ExitCode != sdcCmd.ExitCodeNotFound {
+		t.Errorf("runningconfig of an unknown target exit code = %d, want %d (err: %v)", r.ExitCode, sdcCmd.ExitCodeNotFound, r.Err)
+	}
+
+	// the port-forward reaches nothing, the connection is retried until the request timeout
+	h.APIServer.ForwardPod(dataServerPod, "127.0.0.1:1")
+	r = h.Run("datastore", "list", "--request-timeout", "1s")
+	if r.ExitCode != sdcCmd.ExitCodeError || !strings.Contains(sdcCmd.ErrorHint(r.Err), "--request-timeout") {
+		t.Errorf("datastore list without a serving data-server exit code = %d, hint = %q (err: %v)", r.ExitCode, sdcCmd.ErrorHint(r.Err), r.Err)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	h := newSeededHarness(t)
+
+	expectOutput(t, h.Run("config", "set", "format.blame", "xpath"), `Set format.blame to "xpath" for context "e2e"`)
+	r := h.Run("blame", "--target", target)
+	expectOutput(t, r, "/system/name/host-name")
+	if strings.Contains(r.Stdout, "└") || strings.Contains(r.Stdout, "├") {
+		t.Errorf("blame output is not in the configured xpath format:\n%s", r.Stdout)
+	}
+
+	expectOutput(t, h.Run("config", "set", "deviation.ignorePaths", "/system/name"))
+	r = h.Run("deviation", "--target", target)
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "no deviations found after path filtering") {
+		t.Errorf("deviation with the ignored path error = %v, want no deviations", r.Err)
+	}
+	// flags take precedence over the configuration
+	expectOutput(t, h.Run("deviation", "--target", target, "--ignore-path", "/interface"), "/system/name/host-name")
+
+	expectOutput(t, h.Run("config", "set", "namespace", "lab"))
+	r = h.Run("target", "list")
+	if r.Err != nil || strings.Contains(r.Stdout, target) {
+		t.Errorf("target list in the configured namespace = %q (err: %v), want no target", r.Stdout, r.Err)
+	}
+	expectOutput(t, h.Run("target", "list", "--namespace", Namespace), target)
+
+	expectOutput(t, h.Run("config", "set", "dataServer.service", "other"))
+	r = h.Run("schema", "list")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), `services "other" not found`) {
+		t.Errorf("schema list with the configured data-server error = %v, want service other not found", r.Err)
+	}
+
+	expectOutput(t, h.Run("config", "view", "--minify"), "e2e:", "blame: xpath", "namespace: lab", "service: other", "- /system/name")
+
+	r = h.Run("config", "set", "format.blame", "table")
+	if r.Err == nil {
+		t.Errorf("config set of an invalid blame format succeeded")
+	}
+	r = h.Run("config", "set", "colour", "true")
+	if r.Err == nil || !strings.Contains(r.Err.Error(), "unknown key") {
+		t.Errorf("config set of an unknown key error = %v, want unknown key", r.Err)
+	}
+}
@@ -0,0 +1,132 @@
+// Package e2e runs the kubectl sdc commands end to end, from the cobra root
+// command to a fake Kubernetes API server and an in-process data-server.
+//
+// The Harness seeds the API server with the data-server service and a ready
+// pod whose port-forwards reach the in-process gRPC server, so the commands
+// follow the same path as against a real cluster: kubeconfig loading, the
+// config.sdcio.dev clients, the pod lookup and the SPDY port-forward.
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	sdcCmd "github.com/sdcio/kubectl-sdc/pkg/cmd"
+	"github.com/sdcio/kubectl-sdc/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericiooptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// KubeContext is the current context of the kubeconfig written by the Harness
+	KubeContext = "e2e"
+	// Namespace is the namespace of KubeContext
+	Namespace = "default"
+
+	dataServerNamespace = "sdc-system"
+	dataServerPod       = "data-server-0"
+	dataServicePort     = 56000
+)
+
+// Harness wires the kubectl sdc commands to a fake API server and data-server
+type Harness struct {
+	APIServer  *APIServer
+	DataServer *DataServer
+
+	t testing.TB
+}
+
+// Result is the outcome of a command run by the Harness
+type Result struct {
+	Stdout   string
+	Stderr   string
+	Err      error
+	ExitCode int
+}
+
+// NewHarness starts the fake servers and points KUBECONFIG to them for the rest of the
+// test. The kubectl-sdc configuration file is an empty file of the test.
+func NewHarness(t *testing.T) *Harness {
+	t.Helper()
+
+	h := &Harness{
+		APIServer:  NewAPIServer(t),
+		DataServer: NewDataServer(t),
+		t:          t,
+	}
+
+	err := h.APIServer.Add(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "data-server", Namespace: dataServerNamespace},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "data-server"},
+				Ports: []corev1.ServicePort{{
+					Name:       "data-service",
+					Port:       dataServicePort,
+					TargetPort: intstr.FromInt32(dataServicePort),
+				}},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: dataServerPod, Namespace: dataServerNamespace, Labels: map[string]string{"app": "data-server"}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("failed to seed the data-server: %v", err)
+	}
+	h.APIServer.ForwardPod(dataServerPod, h.DataServer.Addr)
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "kubeconfig")
+	kubeConfig := clientcmdapi.NewConfig()
+	kubeConfig.Clusters[KubeContext] = &clientcmdapi.Cluster{Server: h.APIServer.URL}
+	kubeConfig.AuthInfos[KubeContext] = &clientcmdapi.AuthInfo{}
+	kubeConfig.Contexts[KubeContext] = &clientcmdapi.Context{Cluster: KubeContext, AuthInfo: KubeContext, Namespace: Namespace}
+	kubeConfig.CurrentContext = KubeContext
+	if err := clientcmd.WriteToFile(*kubeConfig, kubeconfigPath); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+	t.Setenv(clientcmd.RecommendedConfigPathEnvVar, kubeconfigPath)
+	t.Setenv(config.EnvConfig, filepath.Join(t.TempDir(), "config.yaml"))
+	// keep the local history snapshots out of the user data directory
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	return h
+}
+
+// Run runs kubectl sdc with args, as the plugin binary would
+func (h *Harness) Run(args ...string) Result {
+	h.t.Helper()
+	return h.RunWithInput("", args...)
+}
+
+// RunWithInput runs kubectl sdc with args and stdin
+func (h *Harness) RunWithInput(stdin string, args ...string) Result {
+	h.t.Helper()
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	streams := genericiooptions.IOStreams{In: bytes.NewBufferString(stdin), Out: out, ErrOut: errOut}
+	root, err := sdcCmd.NewCmdRoot(streams)
+	if err != nil {
+		h.t.Fatalf("failed to create the root command: %v", err)
+	}
+	root.SetArgs(args)
+	root.SetOut(out)
+	root.SetErr(errOut)
+
+	err = root.ExecuteContext(context.Background())
+	r := Result{Stdout: out.String(), Stderr: errOut.String(), Err: err}
+	if err != nil {
+		r.ExitCode = sdcCmd.ExitCode(err)
+	}
+	return r
+}